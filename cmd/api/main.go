@@ -1,17 +1,42 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	files "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	_ "jamlink-backend/docs"
 	"jamlink-backend/internal/adapter/http"
+	"jamlink-backend/internal/adapter/http/cookie"
+	"jamlink-backend/internal/adapter/http/middleware"
+	"jamlink-backend/internal/config"
 	"jamlink-backend/internal/infra/db"
 	emailinfra "jamlink-backend/internal/infra/email"
+	"jamlink-backend/internal/infra/health"
+	"jamlink-backend/internal/infra/maintenance"
+	"jamlink-backend/internal/infra/server"
+	"jamlink-backend/internal/infra/tracing"
 	userRepository "jamlink-backend/internal/modules/auth/repository"
 	userUsecase "jamlink-backend/internal/modules/auth/usecase"
+	consentRepository "jamlink-backend/internal/modules/consent/repository"
+	consentUsecase "jamlink-backend/internal/modules/consent/usecase"
+	"jamlink-backend/internal/modules/invitation/domain/invitation"
+	invitationRepository "jamlink-backend/internal/modules/invitation/repository"
+	invitationUsecase "jamlink-backend/internal/modules/invitation/usecase"
+	"jamlink-backend/internal/shared/async"
+	"jamlink-backend/internal/shared/fingerprint"
 	"jamlink-backend/internal/shared/lang"
+	"jamlink-backend/internal/shared/logging"
 	"jamlink-backend/internal/shared/security"
 )
 
@@ -20,39 +45,308 @@ import (
 // @description This is an API with Swagger and Gin.
 // @host localhost:8080
 // @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 func main() {
 	_ = godotenv.Load()
-	database := db.ConnectDB()
-	db.MigrateDB(database)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrateCommand(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "❌", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, config.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "❌", err)
+		os.Exit(2)
+	}
+	// The log package also writes through this logger from now on.
+	logger := logging.New(os.Stdout, cfg.Log.SlogLevel())
+	slog.SetDefault(logger)
+	logger.Info("configuration loaded", "config", cfg.String())
+
+	if err := run(cfg, logger); err != nil {
+		logger.Error("server failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+// run serves until SIGINT or SIGTERM, then drains in-flight requests and background jobs within
+// cfg.Server.ShutdownTimeout.
+func run(cfg *config.Config, logger *slog.Logger) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	// Deferred first, so that it runs last and flushes the spans of the shutdown itself.
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			logger.Error("failed to flush the traces", "error", err)
+		}
+	}()
+
+	database, err := db.ConnectDB(cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := db.Close(database); err != nil {
+			logger.Error("failed to close the database", "error", err)
+		}
+	}()
+
+	migrator, err := db.NewMigrator(database)
+	if err != nil {
+		return err
+	}
 
 	// Repositories
 	userRepo := userRepository.NewPostgresUserRepository(database)
 	tokenRepo := userRepository.NewPostgresTokenRepository(database)
+	verificationCodeRepo := userRepository.NewPostgresVerificationCodeRepository(database)
+	knownDeviceRepo := userRepository.NewPostgresKnownDeviceRepository(database)
+	impersonationRepo := userRepository.NewPostgresImpersonationRepository(database)
+	deviceAuthRepo := userRepository.NewPostgresDeviceAuthorizationRepository(database)
+	oauthClientRepo := userRepository.NewPostgresOAuthClientRepository(database)
+	authorizationCodeRepo := userRepository.NewPostgresAuthorizationCodeRepository(database)
+	grantRepo := userRepository.NewPostgresGrantRepository(database)
+	invitationRepo := invitationRepository.NewPostgresInvitationRepository(database)
+	documentRepo := consentRepository.NewPostgresDocumentRepository(database)
+	consentRecordRepo := consentRepository.NewPostgresRecordRepository(database)
+	unitOfWork := userRepository.NewPostgresUnitOfWork(database)
 
 	// Services
-	securityService := security.NewSecurityService()
-	emailService := emailinfra.NewBrevoEmailService()
+	secret := []byte(cfg.Security.JWTSecret.Value())
+	tokenStrategy, err := security.NewTokenStrategy(cfg.Security.TokenStrategy, secret, tokenRepo)
+	if err != nil {
+		return fmt.Errorf("invalid token strategy: %w", err)
+	}
+	securityService := security.NewSecurityService(secret, tokenStrategy)
+	emailService := emailinfra.NewBrevoEmailService(cfg.Email)
 	langService := lang.NewLangNormalizer()
+	dispatcher := async.NewWorkerPool(cfg.Server.BackgroundWorkers, cfg.Server.BackgroundQueueSize)
+	fingerprinter := fingerprint.NewFingerprinter(loadGeoLocator(cfg.GeoIP.DBPath))
+
+	// Already validated by config.Load.
+	registrationMode, _ := invitation.ParseRegistrationMode(cfg.Registration.Mode)
+	registrationGate := invitationUsecase.NewRegistrationGate(registrationMode, invitationRepo)
+	registrationConsents := consentUsecase.NewRegistrationConsents(documentRepo, consentRecordRepo)
+	consentGate := consentUsecase.NewConsentGate(documentRepo, consentRecordRepo)
+
+	lifetimes := userUsecase.TokenLifetimes{
+		Access:            cfg.Tokens.AccessTTL,
+		Refresh:           cfg.Tokens.RefreshTTL,
+		ResetPassword:     cfg.Tokens.ResetPasswordTTL,
+		Impersonation:     cfg.Tokens.ImpersonationTTL,
+		ClientCredentials: cfg.Tokens.ClientCredentialsTTL,
+	}
+
+	// The OpenID provider is only enabled when a signing key is configured.
+	oidcKeys, err := security.LoadKeySet(cfg.OIDC.SigningKeyFile, cfg.OIDC.PreviousSigningKeyFile)
+	if err != nil {
+		return fmt.Errorf("invalid OpenID Connect signing key: %w", err)
+	}
+	oidcConfig := http.OIDCConfig{
+		Issuer:     strings.TrimSuffix(cfg.OIDC.Issuer, "/"),
+		LoginURL:   cfg.Frontend.LoginURL,
+		ConsentURL: cfg.Frontend.OAuthConsentURL,
+	}
 
 	// Use Cases
-	createUserUseCase := userUsecase.NewCreateUserUseCase(userRepo, securityService)
-	loginUserUseCase := userUsecase.NewLoginUserUseCase(userRepo, securityService, tokenRepo)
-	loginUserWithGoogleUseCase := userUsecase.NewLoginUserWithGoogleUseCase(userRepo, securityService)
-	refreshTokenUseCase := userUsecase.NewRefreshTokenUseCase(securityService, userRepo, tokenRepo)
-	requestVerifyUserEmailUseCase := userUsecase.NewRequestVerifyUserEmailUseCase(securityService, userRepo, emailService)
+	requestGuardianConsentUseCase := userUsecase.NewRequestGuardianConsentUseCase(userRepo, securityService, emailService, cfg.Frontend.GuardianConsentURL)
+	confirmGuardianConsentUseCase := userUsecase.NewConfirmGuardianConsentUseCase(userRepo, securityService)
+	createUserUseCase := userUsecase.NewCreateUserUseCase(unitOfWork, securityService, emailService, registrationGate, registrationConsents, requestGuardianConsentUseCase, cfg.Registration.MinorAge)
+	loginUserUseCase := userUsecase.NewLoginUserUseCase(userRepo, securityService, tokenRepo, lifetimes)
+	loginUserWithGoogleUseCase := userUsecase.NewLoginUserWithGoogleUseCase(userRepo, unitOfWork, securityService, registrationGate, cfg.Google.ClientID, lifetimes)
+	refreshTokenUseCase := userUsecase.NewRefreshTokenUseCase(securityService, unitOfWork, lifetimes)
+	requestVerifyUserEmailUseCase := userUsecase.NewRequestVerifyUserEmailUseCase(securityService, userRepo, emailService, verificationCodeRepo, cfg.Frontend.VerifyURL)
 	verifyUserUseCase := userUsecase.NewVerifyUserUseCase(userRepo, securityService)
-	requestResetPasswordUseCase := userUsecase.NewRequestResetPasswordUseCase(tokenRepo, userRepo, securityService, emailService)
-	resetPasswordUseCase := userUsecase.NewResetPasswordUseCase(tokenRepo, userRepo, securityService)
-	disconnectUserUseCase := userUsecase.NewDisconnectUserUseCase(tokenRepo)
+	verifyUserWithCodeUseCase := userUsecase.NewVerifyUserWithCodeUseCase(userRepo, verificationCodeRepo, securityService)
+	requestResetPasswordUseCase := userUsecase.NewRequestResetPasswordUseCase(tokenRepo, userRepo, securityService, emailService, cfg.Frontend.VerifyURL, lifetimes)
+	resetPasswordUseCase := userUsecase.NewResetPasswordUseCase(unitOfWork, securityService)
+	disconnectUserUseCase := userUsecase.NewDisconnectUserUseCase(tokenRepo, securityService)
+	reauthenticateUseCase := userUsecase.NewReauthenticateUseCase(userRepo, securityService)
+	recordLoginDeviceUseCase := userUsecase.NewRecordLoginDeviceUseCase(userRepo, knownDeviceRepo, securityService, emailService, fingerprinter, cfg.Frontend.ReportLoginURL)
+	reportSuspiciousLoginUseCase := userUsecase.NewReportSuspiciousLoginUseCase(unitOfWork, securityService, requestResetPasswordUseCase)
+	purgeExpiredTokensUseCase := userUsecase.NewPurgeExpiredTokensUseCase(tokenRepo)
+	purgeUnverifiedUsersUseCase := userUsecase.NewPurgeUnverifiedUsersUseCase(userRepo, securityService, emailService, cfg.Frontend.VerifyURL)
+	impersonateUserUseCase := userUsecase.NewImpersonateUserUseCase(userRepo, impersonationRepo, securityService, lifetimes)
+	notifyImpersonatedUsersUseCase := userUsecase.NewNotifyImpersonatedUsersUseCase(impersonationRepo, userRepo, emailService)
+	impersonationAudit := userUsecase.NewImpersonationAudit(impersonationRepo)
+	requestDeviceAuthorizationUseCase := userUsecase.NewRequestDeviceAuthorizationUseCase(deviceAuthRepo, securityService, cfg.Frontend.DeviceURL)
+	decideDeviceAuthorizationUseCase := userUsecase.NewDecideDeviceAuthorizationUseCase(deviceAuthRepo)
+	exchangeDeviceCodeUseCase := userUsecase.NewExchangeDeviceCodeUseCase(deviceAuthRepo, userRepo, tokenRepo, securityService, lifetimes)
+	clientCredentialsUseCase := userUsecase.NewClientCredentialsUseCase(oauthClientRepo, securityService, lifetimes)
+	registerOAuthClientUseCase := userUsecase.NewRegisterOAuthClientUseCase(userRepo, oauthClientRepo, securityService)
+	authorizeUseCase := userUsecase.NewAuthorizeUseCase(oauthClientRepo, grantRepo, authorizationCodeRepo, securityService)
+	getOAuthClientUseCase := userUsecase.NewGetOAuthClientUseCase(authorizeUseCase)
+	getUserInfoUseCase := userUsecase.NewGetUserInfoUseCase(userRepo)
+	listOAuthGrantsUseCase := userUsecase.NewListOAuthGrantsUseCase(grantRepo, oauthClientRepo)
+	revokeOAuthGrantUseCase := userUsecase.NewRevokeOAuthGrantUseCase(grantRepo)
+	var exchangeAuthorizationCodeUseCase *userUsecase.ExchangeAuthorizationCodeUseCase
+	if oidcKeys != nil {
+		exchangeAuthorizationCodeUseCase = userUsecase.NewExchangeAuthorizationCodeUseCase(oauthClientRepo, authorizationCodeRepo, userRepo, securityService, oidcKeys, oidcConfig.Issuer, lifetimes)
+	}
+	createInvitationUseCase := invitationUsecase.NewCreateInvitationUseCase(userRepo, invitationRepo, securityService)
+	sendInvitationUseCase := invitationUsecase.NewSendInvitationUseCase(userRepo, invitationRepo, emailService, cfg.Frontend.InviteURL)
+	listInvitationsUseCase := invitationUsecase.NewListInvitationsUseCase(invitationRepo)
+	listDocumentsUseCase := consentUsecase.NewListDocumentsUseCase(documentRepo)
+	publishDocumentUseCase := consentUsecase.NewPublishDocumentUseCase(userRepo, documentRepo)
+	getConsentStatusUseCase := consentUsecase.NewGetConsentStatusUseCase(documentRepo, consentRecordRepo)
+	acceptDocumentsUseCase := consentUsecase.NewAcceptDocumentsUseCase(documentRepo, consentRecordRepo)
+	setMarketingConsentUseCase := consentUsecase.NewSetMarketingConsentUseCase(consentRecordRepo)
+	listConsentHistoryUseCase := consentUsecase.NewListConsentHistoryUseCase(consentRecordRepo)
+
+	// Background workers
+	maintenanceWorker := maintenance.NewWorker(database, maintenance.NewConfig(cfg.Maintenance), purgeExpiredTokensUseCase, purgeUnverifiedUsersUseCase, notifyImpersonatedUsersUseCase)
+
+	// Readiness: the email provider is only reported, its outage must not take every replica down.
+	migrations := &health.Flag{}
+	probe := health.NewProbe()
+	probe.Require("database", db.Ping(database))
+	probe.Require("migrations", migrations.Check)
+	probe.Report("email", emailService.Status)
+
+	// Already validated by config.Load.
+	sameSite, _ := cookie.ParseSameSite(cfg.Cookie.SameSite)
+	cookiePolicy := cookie.Policy{
+		Secure:          cfg.Cookie.Secure,
+		Domain:          cfg.Cookie.Domain,
+		SameSite:        sameSite,
+		HostPrefix:      cfg.Cookie.HostPrefix,
+		RefreshTokenTTL: cfg.Tokens.RefreshTTL,
+	}
+
+	session, err := cookie.SessionFromKey(cookiePolicy, cfg.Cookie.SessionKey.Value())
+	if err != nil {
+		return fmt.Errorf("invalid BFF session configuration: %w", err)
+	}
 
 	// Setup router
-	r := gin.Default()
+	if cfg.Log.Level != "debug" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	r := gin.New()
+	// The request span comes first, so that the request logger can log its trace ID. Problems comes after
+	// the middlewares that record the status of the response, which it writes.
+	r.Use(otelgin.Middleware(cfg.Tracing.ServiceName), middleware.RequestLogger(logger), middleware.Metrics(), middleware.Problems(), middleware.Recovery(), middleware.RequestTimeout(cfg.Server.RequestTimeout))
+
+	authHandler := http.NewAuthHandler(r, securityService, dispatcher, cookiePolicy, session, impersonationAudit, langService, createUserUseCase, loginUserUseCase, loginUserWithGoogleUseCase, refreshTokenUseCase, verifyUserUseCase, verifyUserWithCodeUseCase, requestVerifyUserEmailUseCase, requestResetPasswordUseCase, resetPasswordUseCase, disconnectUserUseCase, reauthenticateUseCase, recordLoginDeviceUseCase, reportSuspiciousLoginUseCase, requestGuardianConsentUseCase, confirmGuardianConsentUseCase)
+	// Authenticated user routes; those behind the consent gate answer 403 "consent_required" until the
+	// active terms and privacy policy are accepted. Routes for OAuth clients use
+	// middleware.RequireService with their scopes instead of RequireUser.
+	authenticated := r.Group("/", authHandler.RequireAuth(), middleware.RequireUser())
+	consented := authenticated.Group("/", middleware.RequireCurrentConsent(consentGate))
 
-	http.NewAuthHandler(r, securityService, langService, createUserUseCase, loginUserUseCase, loginUserWithGoogleUseCase, refreshTokenUseCase, verifyUserUseCase, requestVerifyUserEmailUseCase, requestResetPasswordUseCase, resetPasswordUseCase, disconnectUserUseCase)
+	http.NewConsentHandler(r, authenticated, listDocumentsUseCase, publishDocumentUseCase, getConsentStatusUseCase, acceptDocumentsUseCase, setMarketingConsentUseCase, listConsentHistoryUseCase)
+	http.NewImpersonationHandler(authenticated, securityService, impersonateUserUseCase)
+	http.NewOAuthHandler(r, authenticated, consented, securityService, requestDeviceAuthorizationUseCase, decideDeviceAuthorizationUseCase, exchangeDeviceCodeUseCase, clientCredentialsUseCase, registerOAuthClientUseCase, exchangeAuthorizationCodeUseCase)
+	if oidcKeys != nil {
+		http.NewOIDCHandler(r, consented, consentGate, securityService, authHandler.BFFSession(), oidcKeys, oidcConfig, authorizeUseCase, getOAuthClientUseCase, getUserInfoUseCase, listOAuthGrantsUseCase, revokeOAuthGrantUseCase)
+	}
+	http.NewInvitationHandler(consented, langService, createInvitationUseCase, sendInvitationUseCase, listInvitationsUseCase)
+	http.NewHealthHandler(r, probe)
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(files.Handler))
 
-	// Run server
-	if err := r.Run(":8080"); err != nil {
-		return
+	// Metrics are served on the internal listener when there is one, else on the main one behind the
+	// metrics token.
+	var metricsSrv *server.Server
+	switch {
+	case cfg.Metrics.Addr != "":
+		internal := gin.New()
+		internal.Use(middleware.Problems(), middleware.Recovery())
+		http.NewMetricsHandler(internal, "")
+
+		metricsCfg := cfg.Server
+		metricsCfg.Addr = cfg.Metrics.Addr
+		metricsSrv = server.New(metricsCfg, internal)
+	case cfg.Metrics.Token != "":
+		http.NewMetricsHandler(r, cfg.Metrics.Token.Value())
+	}
+
+	// The server starts before the migrations so that the liveness probe answers while they run;
+	// the readiness probe keeps traffic away until they are done.
+	srv := server.New(cfg.Server, r)
+	serverErrs, err := srv.Start()
+	if err != nil {
+		return fmt.Errorf("failed to start the server: %w", err)
+	}
+	// A nil channel never receives, for when there is no metrics listener.
+	var metricsErrs <-chan error
+	if metricsSrv != nil {
+		if metricsErrs, err = metricsSrv.Start(); err != nil {
+			return fmt.Errorf("failed to start the metrics server: %w", err)
+		}
+	}
+
+	if err := migrateOnStart(ctx, cfg.Database, migrator, logger); err != nil {
+		return fmt.Errorf("database migrations failed: %w", err)
+	}
+	migrations.Set()
+
+	workerDone := make(chan struct{})
+	go func() {
+		defer close(workerDone)
+		maintenanceWorker.Start(ctx)
+	}()
+
+	select {
+	case <-ctx.Done():
+		logger.Info("shutting down")
+	case err = <-serverErrs:
+		err = fmt.Errorf("server stopped: %w", err)
+		stop()
+	case err = <-metricsErrs:
+		err = fmt.Errorf("metrics server stopped: %w", err)
+		stop()
+	}
+
+	probe.Drain()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	if shutdownErr := srv.Shutdown(shutdownCtx); shutdownErr != nil {
+		logger.Error("in-flight requests not drained", "error", shutdownErr)
+	}
+	if metricsSrv != nil {
+		if shutdownErr := metricsSrv.Shutdown(shutdownCtx); shutdownErr != nil {
+			logger.Error("metrics server not stopped", "error", shutdownErr)
+		}
+	}
+	select {
+	case <-workerDone:
+	case <-shutdownCtx.Done():
+		logger.Error("maintenance run not finished before the shutdown timeout")
+	}
+	if waitErr := dispatcher.Shutdown(shutdownCtx); waitErr != nil {
+		logger.Error("background jobs not finished", "error", waitErr)
 	}
+
+	return err
+}
+
+// loadGeoLocator returns nil when path is empty: logins are then located by IP prefix only.
+func loadGeoLocator(path string) fingerprint.GeoLocator {
+	if path == "" {
+		return nil
+	}
+
+	locator, err := fingerprint.LoadCSVGeoLocator(path)
+	if err != nil {
+		slog.Warn("GeoIP database not loaded, falling back to IP prefixes", "error", err)
+		return nil
+	}
+
+	return locator
 }
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"jamlink-backend/internal/config"
+	"jamlink-backend/internal/infra/db"
+	"jamlink-backend/internal/infra/db/migrate"
+	"jamlink-backend/internal/shared/logging"
+)
+
+const migrateUsage = `usage: jamlink migrate <command> [configuration flags]
+
+commands:
+  up                      apply the pending migrations
+  down [steps]            revert the last migration, or the last steps ones
+  status                  list the migrations and whether they are applied
+  create <module> <name>  write an empty pair of scripts in the module's migrations, from the repository root`
+
+// migrateCommand runs "jamlink migrate". The arguments after the command's own are the flags of
+// config.Load, which gives the database settings.
+func migrateCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	command, args := args[0], args[1:]
+
+	if command == "create" {
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+		return createMigration(args[0], args[1])
+	}
+
+	steps := 1
+	if command == "down" && len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid number of steps %q", args[0])
+		}
+		steps, args = n, args[1:]
+	}
+
+	switch command {
+	case "up", "down", "status":
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	cfg, err := config.Load(args)
+	if errors.Is(err, config.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// Logs go to stderr, so that the output of status can be piped.
+	slog.SetDefault(logging.New(os.Stderr, cfg.Log.SlogLevel()))
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	database, err := db.ConnectDB(cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close(database) }()
+
+	migrator, err := db.NewMigrator(database)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Println("applied", migration)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("database is up to date")
+		}
+		return err
+	case "down":
+		reverted, err := migrator.Down(ctx, steps)
+		for _, migration := range reverted {
+			fmt.Println("reverted", migration)
+		}
+		return err
+	default:
+		states, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		return printStatus(os.Stdout, states)
+	}
+}
+
+func printStatus(w io.Writer, states []migrate.State) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "VERSION\tMODULE\tNAME\tSTATUS\tAPPLIED AT")
+	for _, state := range states {
+		appliedAt := "-"
+		if state.AppliedAt != nil {
+			appliedAt = state.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%s\n", state.Version, state.Module, state.Name, state.Status, appliedAt)
+	}
+	return table.Flush()
+}
+
+func createMigration(module, name string) error {
+	for _, source := range db.MigrationSources() {
+		if source.Module != module {
+			continue
+		}
+
+		paths, err := migrate.Create(source, name, time.Now())
+		for _, path := range paths {
+			fmt.Println("created", path)
+		}
+		return err
+	}
+
+	return fmt.Errorf("unknown module %q", module)
+}
+
+// migrateOnStart applies the pending migrations, or checks that there are none when the server does
+// not apply them itself.
+func migrateOnStart(ctx context.Context, cfg config.DatabaseConfig, migrator *migrate.Migrator, logger *slog.Logger) error {
+	if !cfg.MigrateOnStart {
+		states, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		if pending := migrate.Pending(states); pending > 0 {
+			return fmt.Errorf("%d pending migrations: run jamlink migrate up", pending)
+		}
+		return nil
+	}
+
+	applied, err := migrator.Up(ctx)
+	for _, migration := range applied {
+		logger.Info("migration applied", "migration", migration.String())
+	}
+	return err
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+
+	"gorm.io/gorm"
+	"jamlink-backend/internal/config"
+	"jamlink-backend/internal/infra/db"
+	userRepository "jamlink-backend/internal/modules/auth/repository"
+	"jamlink-backend/internal/shared/security"
+)
+
+// app wires the dependencies of the commands on demand, so that commands such as print-config or
+// rotate-oidc-keys run without a database.
+type app struct {
+	cfg      *config.Config
+	logger   *slog.Logger
+	stdin    io.Reader
+	database *gorm.DB
+}
+
+func newApp(cfg *config.Config, logger *slog.Logger, stdin io.Reader) *app {
+	return &app{cfg: cfg, logger: logger, stdin: stdin}
+}
+
+func (a *app) db() (*gorm.DB, error) {
+	if a.database == nil {
+		database, err := db.ConnectDB(a.cfg.Database)
+		if err != nil {
+			return nil, err
+		}
+		a.database = database
+	}
+	return a.database, nil
+}
+
+func (a *app) close() {
+	if a.database == nil {
+		return
+	}
+	if err := db.Close(a.database); err != nil {
+		a.logger.Error("failed to close the database", "error", err)
+	}
+}
+
+// repositories are the auth repositories the commands need.
+type repositories struct {
+	users          *userRepository.PostgresUserRepository
+	tokens         *userRepository.PostgresTokenRepository
+	impersonations *userRepository.PostgresImpersonationRepository
+}
+
+func (a *app) repositories() (*repositories, error) {
+	database, err := a.db()
+	if err != nil {
+		return nil, err
+	}
+
+	return &repositories{
+		users:          userRepository.NewPostgresUserRepository(database),
+		tokens:         userRepository.NewPostgresTokenRepository(database),
+		impersonations: userRepository.NewPostgresImpersonationRepository(database),
+	}, nil
+}
+
+func (a *app) securityService(repos *repositories) (security.SecurityService, error) {
+	secret := []byte(a.cfg.Security.JWTSecret.Value())
+	tokenStrategy, err := security.NewTokenStrategy(a.cfg.Security.TokenStrategy, secret, repos.tokens)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token strategy: %w", err)
+	}
+	return security.NewSecurityService(secret, tokenStrategy), nil
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	emailinfra "jamlink-backend/internal/infra/email"
+	"jamlink-backend/internal/infra/maintenance"
+	"jamlink-backend/internal/modules/auth/domain/user"
+	userUsecase "jamlink-backend/internal/modules/auth/usecase"
+	"jamlink-backend/internal/shared/email"
+	"jamlink-backend/internal/shared/lang"
+	"jamlink-backend/internal/shared/security"
+)
+
+type userView struct {
+	ID         uuid.UUID  `json:"id"`
+	Email      string     `json:"email"`
+	Role       string     `json:"role"`
+	Verified   bool       `json:"verified"`
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+}
+
+func userReport(u *user.User) *report {
+	view := userView{ID: u.ID, Email: u.Email, Role: u.Role, Verified: u.Verification.IsVerified, VerifiedAt: u.Verification.VerifiedAt}
+	verifiedAt := "-"
+	if view.VerifiedAt != nil {
+		verifiedAt = view.VerifiedAt.Format(time.RFC3339)
+	}
+
+	return &report{
+		value:   view,
+		columns: []string{"ID", "EMAIL", "ROLE", "VERIFIED", "VERIFIED AT"},
+		rows:    [][]string{{view.ID.String(), view.Email, view.Role, strconv.FormatBool(view.Verified), verifiedAt}},
+	}
+}
+
+func requireEmail(flagName, value string) error {
+	if value == "" {
+		return fmt.Errorf("-%s is required", flagName)
+	}
+	return nil
+}
+
+func createAdmin(fs *flag.FlagSet) action {
+	emailAddr := fs.String("email", "", "email of the administrator")
+	preferredLang := fs.String("lang", "fr-FR", "preferred language of the administrator")
+
+	return func(ctx context.Context, app *app) (*report, error) {
+		if err := requireEmail("email", *emailAddr); err != nil {
+			return nil, err
+		}
+		// Read from stdin rather than a flag, so that the password stays out of the shell history.
+		password, err := readPassword(app)
+		if err != nil {
+			return nil, err
+		}
+
+		repos, err := app.repositories()
+		if err != nil {
+			return nil, err
+		}
+		securityService, err := app.securityService(repos)
+		if err != nil {
+			return nil, err
+		}
+
+		admin, err := userUsecase.NewCreateAdminUseCase(repos.users, securityService).Execute(ctx, userUsecase.CreateAdminInput{
+			Email:         *emailAddr,
+			Password:      password,
+			PreferredLang: lang.NewLangNormalizer().Normalize(*preferredLang),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return userReport(admin), nil
+	}
+}
+
+// readPassword reads the first line of stdin. The prompt goes to stderr; the password is echoed when
+// typed in a terminal, so prefer piping it from a secret store.
+func readPassword(app *app) (string, error) {
+	fmt.Fprint(os.Stderr, "Password: ")
+	scanner := bufio.NewScanner(app.stdin)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", errors.New("no password on stdin")
+	}
+
+	password := strings.TrimRight(scanner.Text(), "\r")
+	if password == "" {
+		return "", errors.New("no password on stdin")
+	}
+	return password, nil
+}
+
+func setVerification(verified bool) func(fs *flag.FlagSet) action {
+	return func(fs *flag.FlagSet) action {
+		emailAddr := fs.String("email", "", "email of the user")
+
+		return func(ctx context.Context, app *app) (*report, error) {
+			if err := requireEmail("email", *emailAddr); err != nil {
+				return nil, err
+			}
+			repos, err := app.repositories()
+			if err != nil {
+				return nil, err
+			}
+
+			updated, err := userUsecase.NewSetUserVerificationUseCase(repos.users).Execute(ctx, userUsecase.SetUserVerificationInput{
+				Email:    *emailAddr,
+				Verified: verified,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return userReport(updated), nil
+		}
+	}
+}
+
+func revokeSessions(fs *flag.FlagSet) action {
+	emailAddr := fs.String("email", "", "email of the user")
+
+	return func(ctx context.Context, app *app) (*report, error) {
+		if err := requireEmail("email", *emailAddr); err != nil {
+			return nil, err
+		}
+		repos, err := app.repositories()
+		if err != nil {
+			return nil, err
+		}
+
+		revoked, err := userUsecase.NewRevokeUserSessionsUseCase(repos.users, repos.tokens).Execute(ctx, userUsecase.RevokeUserSessionsInput{
+			Email: *emailAddr,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return userReport(revoked), nil
+	}
+}
+
+// sampleData fills every placeholder of the templates.
+var sampleData = map[string]string{
+	"CHILD_EMAIL": "child@example.com",
+	"CODE":        "123456",
+	"DATE":        "19/10/2026 14:30",
+	"DEVICE":      "Firefox on Linux",
+	"INVITER":     "admin@example.com",
+	"LOCATION":    "Paris, France",
+	"REASON":      "Test email sent with jamlinkctl",
+	"REQUESTS":    "GET /users/me",
+	"URL":         "https://example.com/jamlinkctl-test",
+}
+
+type sentEmail struct {
+	Template email.TemplateType `json:"template"`
+	Lang     string             `json:"lang"`
+	Error    string             `json:"error,omitempty"`
+}
+
+func sendTestEmail(fs *flag.FlagSet) action {
+	to := fs.String("to", "", "recipient of the test emails")
+	templateName := fs.String("template", "all", "template to send, or all")
+	preferredLang := fs.String("lang", "fr-FR", "language of the templates")
+
+	return func(ctx context.Context, app *app) (*report, error) {
+		if err := requireEmail("to", *to); err != nil {
+			return nil, err
+		}
+		templates, err := selectTemplates(*templateName)
+		if err != nil {
+			return nil, err
+		}
+
+		emailService := emailinfra.NewBrevoEmailService(app.cfg.Email)
+		result := &report{columns: []string{"TEMPLATE", "LANG", "STATUS", "ERROR"}}
+		var sent []sentEmail
+		failed := 0
+		for _, template := range templates {
+			outcome := sentEmail{Template: template, Lang: *preferredLang}
+			status := "sent"
+			if err := emailService.Send(ctx, *to, template, *preferredLang, sampleData); err != nil {
+				outcome.Error = err.Error()
+				status = "failed"
+				failed++
+			}
+			sent = append(sent, outcome)
+			result.rows = append(result.rows, []string{string(template), *preferredLang, status, outcome.Error})
+		}
+		result.value = sent
+
+		if failed > 0 {
+			return result, fmt.Errorf("%d of %d test emails failed", failed, len(templates))
+		}
+		return result, nil
+	}
+}
+
+func selectTemplates(name string) ([]email.TemplateType, error) {
+	if name == "all" {
+		return email.Templates, nil
+	}
+	for _, template := range email.Templates {
+		if string(template) == name {
+			return []email.TemplateType{template}, nil
+		}
+	}
+
+	names := make([]string, len(email.Templates))
+	for i, template := range email.Templates {
+		names[i] = string(template)
+	}
+	return nil, fmt.Errorf("unknown template %q, want all or one of %s", name, strings.Join(names, ", "))
+}
+
+type rotatedKeys struct {
+	ActiveKID    string `json:"active_kid"`
+	ActiveFile   string `json:"active_file"`
+	PreviousKID  string `json:"previous_kid,omitempty"`
+	PreviousFile string `json:"previous_file"`
+}
+
+// rotateOIDCKeys only rotates the RSA keys of the ID tokens. security.jwt_secret, which signs the
+// session tokens and keys the hashes of the stored ones, has no verification window: changing it signs
+// every user out and invalidates pending links and codes.
+func rotateOIDCKeys(fs *flag.FlagSet) action {
+	return func(ctx context.Context, app *app) (*report, error) {
+		oidc := app.cfg.OIDC
+		rotation, err := security.RotateSigningKeys(oidc.SigningKeyFile, oidc.PreviousSigningKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		app.logger.Warn("signing keys rotated: restart the API servers to sign with the new key")
+
+		rotated := rotatedKeys{ActiveKID: rotation.Active.ID, ActiveFile: oidc.SigningKeyFile, PreviousFile: oidc.PreviousSigningKeyFile}
+		result := &report{
+			value:   &rotated,
+			columns: []string{"KEY", "KID", "FILE"},
+			rows:    [][]string{{"active", rotated.ActiveKID, rotated.ActiveFile}},
+		}
+		if rotation.Previous != nil {
+			rotated.PreviousKID = rotation.Previous.ID
+			result.rows = append(result.rows, []string{"previous", rotated.PreviousKID, rotated.PreviousFile})
+		}
+		return result, nil
+	}
+}
+
+func printConfig(fs *flag.FlagSet) action {
+	return func(ctx context.Context, app *app) (*report, error) {
+		settings := app.cfg.Settings()
+		result := &report{value: settings, columns: []string{"KEY", "ENV", "VALUE"}}
+		for _, setting := range settings {
+			result.rows = append(result.rows, []string{setting.Key, setting.Env, setting.Value})
+		}
+		return result, nil
+	}
+}
+
+type maintenanceRun struct {
+	Skipped                bool    `json:"skipped"`
+	TokensDeleted          int64   `json:"tokens_deleted"`
+	UsersWarned            int     `json:"users_warned"`
+	UsersDeleted           int     `json:"users_deleted"`
+	ImpersonationsNotified int     `json:"impersonations_notified"`
+	Failures               int     `json:"failures"`
+	DurationSeconds        float64 `json:"duration_seconds"`
+}
+
+func runMaintenance(fs *flag.FlagSet) action {
+	return func(ctx context.Context, app *app) (*report, error) {
+		database, err := app.db()
+		if err != nil {
+			return nil, err
+		}
+		repos, err := app.repositories()
+		if err != nil {
+			return nil, err
+		}
+		securityService, err := app.securityService(repos)
+		if err != nil {
+			return nil, err
+		}
+		emailService := emailinfra.NewBrevoEmailService(app.cfg.Email)
+
+		worker := maintenance.NewWorker(database, maintenance.NewConfig(app.cfg.Maintenance),
+			userUsecase.NewPurgeExpiredTokensUseCase(repos.tokens),
+			userUsecase.NewPurgeUnverifiedUsersUseCase(repos.users, securityService, emailService, app.cfg.Frontend.VerifyURL),
+			userUsecase.NewNotifyImpersonatedUsersUseCase(repos.impersonations, repos.users, emailService),
+		)
+		runReport, err := worker.RunOnce(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		run := maintenanceRun{
+			Skipped:                runReport.Skipped,
+			TokensDeleted:          runReport.TokensDeleted,
+			UsersWarned:            runReport.UsersWarned,
+			UsersDeleted:           runReport.UsersDeleted,
+			ImpersonationsNotified: runReport.ImpersonationsNotified,
+			Failures:               runReport.Failures,
+			DurationSeconds:        runReport.Duration.Seconds(),
+		}
+		result := &report{
+			value:   run,
+			columns: []string{"SKIPPED", "TOKENS DELETED", "USERS WARNED", "USERS DELETED", "IMPERSONATIONS NOTIFIED", "FAILURES", "DURATION"},
+			rows: [][]string{{
+				strconv.FormatBool(run.Skipped),
+				strconv.FormatInt(run.TokensDeleted, 10),
+				strconv.Itoa(run.UsersWarned),
+				strconv.Itoa(run.UsersDeleted),
+				strconv.Itoa(run.ImpersonationsNotified),
+				strconv.Itoa(run.Failures),
+				runReport.Duration.Round(time.Millisecond).String(),
+			}},
+		}
+		if run.Skipped {
+			return result, errors.New("skipped: another replica is running the maintenance jobs")
+		}
+		return result, nil
+	}
+}
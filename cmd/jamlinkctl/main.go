@@ -0,0 +1,129 @@
+// Command jamlinkctl administers a JamLink deployment. It runs the use cases and repositories of the
+// API against the database and services of the same configuration, for operations that have no
+// endpoint or are needed before any admin can sign in.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/joho/godotenv"
+	"jamlink-backend/internal/config"
+	"jamlink-backend/internal/shared/logging"
+)
+
+const usage = `usage: jamlinkctl [-o table|json] <command> [command flags] [-- configuration flags]
+
+commands:
+%s
+The configuration is loaded like the API's: defaults, -config or CONFIG_FILE, the environment, then
+the configuration flags, for example: jamlinkctl print-config -- -database.host=db.internal`
+
+// errUsage makes main print the usage.
+var errUsage = errors.New("invalid usage")
+
+// action runs a command once its flags are parsed. It returns what to print, even when it fails midway.
+type action func(ctx context.Context, app *app) (*report, error)
+
+type command struct {
+	summary string
+	// setup declares the flags of the command on fs.
+	setup func(fs *flag.FlagSet) action
+}
+
+var commands = map[string]command{
+	"create-admin":     {"create a verified administrator, reading the password from stdin", createAdmin},
+	"verify-user":      {"mark the email of a user as verified", setVerification(true)},
+	"unverify-user":    {"mark the email of a user as not verified", setVerification(false)},
+	"revoke-sessions":  {"delete the refresh tokens of a user, signing them out everywhere", revokeSessions},
+	"send-test-email":  {"send email templates filled with sample data", sendTestEmail},
+	"rotate-oidc-keys": {"replace the OpenID Connect signing key, keeping the current one published", rotateOIDCKeys},
+	"print-config":     {"print the effective configuration, secrets redacted", printConfig},
+	"run-maintenance":  {"run the maintenance jobs once", runMaintenance},
+}
+
+func main() {
+	_ = godotenv.Load()
+
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	switch {
+	case err == nil, errors.Is(err, config.ErrHelp), errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(os.Stderr, usage+"\n", commandList())
+		os.Exit(2)
+	default:
+		fmt.Fprintln(os.Stderr, "❌", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	global := flag.NewFlagSet("jamlinkctl", flag.ContinueOnError)
+	global.SetOutput(io.Discard)
+	format := global.String("o", "table", "output format: table or json")
+	if err := global.Parse(args); err != nil {
+		return errUsage
+	}
+	if *format != "table" && *format != "json" {
+		return fmt.Errorf("unknown output format %q, want table or json", *format)
+	}
+	if global.NArg() == 0 {
+		return errUsage
+	}
+
+	name := global.Arg(0)
+	cmd, ok := commands[name]
+	if !ok {
+		return errUsage
+	}
+	fs := flag.NewFlagSet("jamlinkctl "+name, flag.ContinueOnError)
+	act := cmd.setup(fs)
+	if err := fs.Parse(global.Args()[1:]); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(fs.Args())
+	if err != nil {
+		return err
+	}
+	// Logs go to stderr, so that the output can be piped.
+	logger := logging.New(os.Stderr, cfg.Log.SlogLevel())
+	slog.SetDefault(logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	app := newApp(cfg, logger, stdin)
+	defer app.close()
+
+	result, err := act(ctx, app)
+	if result != nil {
+		if printErr := result.print(stdout, *format); printErr != nil {
+			return errors.Join(err, printErr)
+		}
+	}
+	return err
+}
+
+func commandList() string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "  %-20s %s\n", name, commands[name].summary)
+	}
+	return b.String()
+}
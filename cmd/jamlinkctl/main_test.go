@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"jamlink-backend/internal/config"
+	"jamlink-backend/internal/shared/email"
+)
+
+const testJWTSecret = "0123456789abcdef0123456789abcdef"
+
+// validConfig are the configuration flags that complete the defaults into a valid configuration.
+var validConfig = []string{"--", "-security.jwt_secret=" + testJWTSecret, "-frontend.verify_url=https://jamlink.app/verify"}
+
+// isolateEnv blanks every setting's environment variable, which Load ignores, so the tests do not
+// depend on the environment they run in.
+func isolateEnv(t *testing.T) {
+	cfg := config.Default()
+	for _, setting := range cfg.Settings() {
+		t.Setenv(setting.Env, "")
+	}
+	t.Setenv(config.FileEnv, "")
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+		// errContains is checked when wantErr is nil and an error is expected.
+		errContains string
+		check       func(t *testing.T, output string)
+	}{
+		{name: "no command", args: nil, wantErr: errUsage},
+		{name: "unknown command", args: []string{"drop-database"}, wantErr: errUsage},
+		{name: "unknown global flag", args: []string{"-x", "print-config"}, wantErr: errUsage},
+		{name: "unknown output format", args: []string{"-o", "yaml", "print-config"}, errContains: `unknown output format "yaml"`},
+		{name: "unknown command flag", args: []string{"verify-user", "-id", "42"}, errContains: "flag provided but not defined: -id"},
+		{name: "invalid configuration", args: []string{"print-config", "--", "-security.jwt_secret=short"}, errContains: "security.jwt_secret"},
+		{name: "missing required flag", args: append([]string{"verify-user"}, validConfig...), errContains: "-email is required"},
+		{name: "rotate-oidc-keys without key files", args: append([]string{"rotate-oidc-keys"}, validConfig...), errContains: "requires both the active and the previous key files"},
+		{
+			name: "print-config as a table",
+			args: append([]string{"print-config"}, validConfig...),
+			check: func(t *testing.T, output string) {
+				lines := strings.Split(strings.TrimSpace(output), "\n")
+				assert.Regexp(t, `^KEY\s+ENV\s+VALUE$`, lines[0])
+				assert.Contains(t, output, "frontend.verify_url")
+				assert.Contains(t, output, "https://jamlink.app/verify")
+				assert.NotContains(t, output, testJWTSecret)
+			},
+		},
+		{
+			name: "print-config as JSON",
+			args: append([]string{"-o", "json", "print-config"}, validConfig...),
+			check: func(t *testing.T, output string) {
+				var settings []config.Setting
+				require.NoError(t, json.Unmarshal([]byte(output), &settings))
+				assert.Contains(t, settings, config.Setting{Key: "frontend.verify_url", Env: "FRONTEND_VERIFY_URL", Value: "https://jamlink.app/verify"})
+				assert.NotContains(t, output, testJWTSecret)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolateEnv(t)
+			var stdout bytes.Buffer
+
+			err := run(tt.args, strings.NewReader(""), &stdout)
+
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.errContains != "":
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+			default:
+				require.NoError(t, err)
+			}
+			if tt.check != nil {
+				tt.check(t, stdout.String())
+			}
+		})
+	}
+}
+
+func TestReport_Print(t *testing.T) {
+	result := &report{
+		value:   map[string]int{"deleted": 3},
+		columns: []string{"EMAIL", "DELETED"},
+		rows:    [][]string{{"user@example.com", "3"}, {"a@b.c", "12"}},
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"table", "EMAIL             DELETED\nuser@example.com  3\na@b.c             12\n"},
+		{"json", "{\n  \"deleted\": 3\n}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var out bytes.Buffer
+
+			require.NoError(t, result.print(&out, tt.format))
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestSelectTemplates(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    []email.TemplateType
+		wantErr string
+	}{
+		{name: "all", want: email.Templates},
+		{name: string(email.TemplateResetPassword), want: []email.TemplateType{email.TemplateResetPassword}},
+		{name: "newsletter", wantErr: `unknown template "newsletter", want all or one of ` + string(email.Templates[0])},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templates, err := selectTemplates(tt.name)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, templates)
+		})
+	}
+}
@@ -1,145 +1,218 @@
 package http
 
 import (
+	"context"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"jamlink-backend/internal/adapter/http/cookie"
 	"jamlink-backend/internal/adapter/http/middleware"
 	"jamlink-backend/internal/modules/auth/usecase"
+	"jamlink-backend/internal/shared/apperror"
+	"jamlink-backend/internal/shared/async"
+	"jamlink-backend/internal/shared/fingerprint"
 	"jamlink-backend/internal/shared/lang"
 	"jamlink-backend/internal/shared/security"
 	"net/http"
-	"time"
 )
 
+// acceptedMessage is returned by every endpoint that may send an email, whatever happened, so the
+// response never reveals whether an account exists.
+const acceptedMessage = "If this address can receive emails, a message is on its way."
+
+// TokenTransportHeader lets clients that cannot use cookies (mobile apps) receive and send the refresh
+// token in the JSON body instead, by setting it to "body". When BFF mode is enabled, browser clients set
+// it to "session" to keep the access token in the encrypted session cookie instead of JavaScript memory.
+const TokenTransportHeader = "X-Token-Transport"
+
+// ErrNoRefreshToken is answered when a request carries no refresh token in any of the transports.
+var ErrNoRefreshToken = apperror.Unauthorized("refresh_token_missing", "no refresh token")
+
 type AuthHandler struct {
 	securitySvc                   security.SecurityService
+	dispatcher                    async.Dispatcher
+	cookiePolicy                  cookie.Policy
+	session                       *cookie.Session
+	bff                           *middleware.BFFSession
+	authMiddleware                gin.HandlerFunc
 	LangNormalizer                lang.LangNormalizer
 	CreateUserUseCase             *useCase.CreateUserUseCase
 	LoginUserUseCase              *useCase.LoginUserUseCase
 	LoginUserWithGoogleUseCase    *useCase.LoginUserWithGoogleUseCase
 	RefreshTokenUseCase           *useCase.RefreshTokenUseCase
 	VerifyUserUseCase             *useCase.VerifyUserUseCase
+	VerifyUserWithCodeUseCase     *useCase.VerifyUserWithCodeUseCase
 	RequestVerifyUserEmailUseCase *useCase.RequestVerifyUserEmailUseCase
 	RequestResetPasswordUseCase   *useCase.RequestResetPasswordUseCase
 	ResetPasswordUseCase          *useCase.ResetPasswordUseCase
 	DisconnectUserUseCase         *useCase.DisconnectUserUseCase
+	ReauthenticateUseCase         *useCase.ReauthenticateUseCase
+	RecordLoginDeviceUseCase      *useCase.RecordLoginDeviceUseCase
+	ReportSuspiciousLoginUseCase  *useCase.ReportSuspiciousLoginUseCase
+	RequestGuardianConsentUseCase *useCase.RequestGuardianConsentUseCase
+	ConfirmGuardianConsentUseCase *useCase.ConfirmGuardianConsentUseCase
 }
 
-func NewAuthHandler(router *gin.Engine, securitySvc security.SecurityService, langNormalizer lang.LangNormalizer, createUserUC *useCase.CreateUserUseCase, loginUserUC *useCase.LoginUserUseCase, loginWithGoogleUserUC *useCase.LoginUserWithGoogleUseCase, refreshTokenUC *useCase.RefreshTokenUseCase, verifyUserUC *useCase.VerifyUserUseCase, getVerificationTokenUC *useCase.RequestVerifyUserEmailUseCase, requestResetPasswordUC *useCase.RequestResetPasswordUseCase, resetPasswordUseCase *useCase.ResetPasswordUseCase, disconnectUserUseCase *useCase.DisconnectUserUseCase) {
+func NewAuthHandler(router *gin.Engine, securitySvc security.SecurityService, dispatcher async.Dispatcher, cookiePolicy cookie.Policy, session *cookie.Session, impersonationAuditor middleware.ImpersonationAuditor, langNormalizer lang.LangNormalizer, createUserUC *useCase.CreateUserUseCase, loginUserUC *useCase.LoginUserUseCase, loginWithGoogleUserUC *useCase.LoginUserWithGoogleUseCase, refreshTokenUC *useCase.RefreshTokenUseCase, verifyUserUC *useCase.VerifyUserUseCase, verifyUserWithCodeUC *useCase.VerifyUserWithCodeUseCase, getVerificationTokenUC *useCase.RequestVerifyUserEmailUseCase, requestResetPasswordUC *useCase.RequestResetPasswordUseCase, resetPasswordUseCase *useCase.ResetPasswordUseCase, disconnectUserUseCase *useCase.DisconnectUserUseCase, reauthenticateUseCase *useCase.ReauthenticateUseCase, recordLoginDeviceUseCase *useCase.RecordLoginDeviceUseCase, reportSuspiciousLoginUseCase *useCase.ReportSuspiciousLoginUseCase, requestGuardianConsentUseCase *useCase.RequestGuardianConsentUseCase, confirmGuardianConsentUseCase *useCase.ConfirmGuardianConsentUseCase) *AuthHandler {
 	handler := &AuthHandler{
 		securitySvc:                   securitySvc,
+		dispatcher:                    dispatcher,
+		cookiePolicy:                  cookiePolicy,
+		session:                       session,
 		LangNormalizer:                langNormalizer,
 		CreateUserUseCase:             createUserUC,
 		LoginUserUseCase:              loginUserUC,
 		RefreshTokenUseCase:           refreshTokenUC,
 		LoginUserWithGoogleUseCase:    loginWithGoogleUserUC,
 		VerifyUserUseCase:             verifyUserUC,
+		VerifyUserWithCodeUseCase:     verifyUserWithCodeUC,
 		RequestVerifyUserEmailUseCase: getVerificationTokenUC,
 		RequestResetPasswordUseCase:   requestResetPasswordUC,
 		ResetPasswordUseCase:          resetPasswordUseCase,
 		DisconnectUserUseCase:         disconnectUserUseCase,
+		ReauthenticateUseCase:         reauthenticateUseCase,
+		RecordLoginDeviceUseCase:      recordLoginDeviceUseCase,
+		ReportSuspiciousLoginUseCase:  reportSuspiciousLoginUseCase,
+		RequestGuardianConsentUseCase: requestGuardianConsentUseCase,
+		ConfirmGuardianConsentUseCase: confirmGuardianConsentUseCase,
 	}
 
 	router.POST("/auth/register", handler.RegisterUser)
 	router.POST("/auth/login", handler.LoginUser)
 	router.POST("/auth/login/google", handler.LoginUserWithGoogle)
-	router.POST("/auth/refresh-token", handler.RefreshToken)
+	router.POST("/auth/refresh-token", middleware.CSRFMiddleware(cookiePolicy), handler.RefreshToken)
 	router.POST("/auth/verify", handler.VerifyUser)
+	router.POST("/auth/verify/code", handler.VerifyUserWithCode)
 	router.POST("/auth/request-verify-user", handler.RequestVerifyUserEmail)
 	router.POST("/auth/request-reset-password", handler.RequestResetPassword)
 	router.POST("/auth/reset-password", handler.ResetPassword)
-	router.POST("/auth/logout", handler.LogoutUser)
+	router.POST("/auth/logout", middleware.CSRFMiddleware(cookiePolicy), handler.LogoutUser)
+	router.POST("/auth/report-login", handler.ReportSuspiciousLogin)
+	router.POST("/auth/request-guardian-consent", handler.RequestGuardianConsent)
+	router.POST("/auth/guardian-consent", handler.ConfirmGuardianConsent)
+
+	handler.bff = handler.newBFFSession()
+	handler.authMiddleware = middleware.JWTAuthMiddleware(securitySvc, handler.bff, impersonationAuditor)
 
 	// Protected routes
 	protected := router.Group("/")
-	protected.Use(middleware.JWTAuthMiddleware(securitySvc))
+	protected.Use(handler.authMiddleware, middleware.RequireUser())
+	protected.POST("/auth/reauthenticate", middleware.RejectImpersonation(), handler.Reauthenticate)
 
+	return handler
+}
+
+// RequireAuth returns the authentication middleware, so other handlers accept the same credentials
+// (Authorization header or BFF session) as the auth routes.
+func (h *AuthHandler) RequireAuth() gin.HandlerFunc {
+	return h.authMiddleware
+}
+
+// BFFSession returns the session cookie authenticator of the auth routes, or nil when BFF mode is
+// disabled.
+func (h *AuthHandler) BFFSession() *middleware.BFFSession {
+	return h.bff
 }
 
 // RegisterUser register a new user
 // @Summary Register a new user
 // @Description Create a new user account.
+// @Description The response is the same whether or not the email is already registered; the owner of an existing account is notified by email instead.
 // @Description Password must:
 // @Description - Be between 8 and 64 characters
 // @Description - Contain at least one uppercase letter
 // @Description - Contain at least one lowercase letter
 // @Description - Contain at least one digit
 // @Description - Contain at least one special character (e.g. !@#$%^&*)
+// @Description An invitation code is required when registrations are invite-only, and optional otherwise.
+// @Description The versions of the active documents listed by GET /consents/documents must be sent as termsVersion and privacyVersion.
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param input body useCase.CreateUserInput true "User credentials"
-// @Success 201 {object} user.User
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Failure 503 {object} apperror.Problem
 // @Router /auth/register [post]
 func (h *AuthHandler) RegisterUser(c *gin.Context) {
 	var input useCase.CreateUserInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondInvalidBody(c, err)
 		return
 	}
 	rawLang := c.GetHeader("Accept-Language")
 	normalizedLang := h.LangNormalizer.Normalize(rawLang)
 
 	input.PreferredLang = normalizedLang
-	user, err := h.CreateUserUseCase.Execute(input)
+	input.IP = c.ClientIP()
+	input.UserAgent = c.Request.UserAgent()
+
+	if err := h.CreateUserUseCase.Validate(c.Request.Context(), input); err != nil {
+		respondError(c, err)
+		return
+	}
 
+	err := h.dispatcher.Dispatch(c.Request.Context(), "register_user", func(ctx context.Context) error {
+		_, err := h.CreateUserUseCase.Execute(ctx, input)
+		return err
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, user)
+	c.JSON(http.StatusAccepted, gin.H{"message": acceptedMessage})
 }
 
 // LoginUser login a user
 // @Summary Login a user
-// @Description Authenticate a user with email and password and store the refresh token (stored in HttpOnly cookie named 'refresh_token')
+// @Description Authenticate a user with email and password and store the refresh token (stored in HttpOnly cookie named 'refresh_token', plus a readable 'csrf_token' cookie).
+// @Description With the header X-Token-Transport: body, no cookie is set and the refresh token is returned in the body instead.
+// @Description With the header X-Token-Transport: session (BFF mode), the tokens are stored in an encrypted HttpOnly 'session' cookie and the response is 204.
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param credentials body useCase.LoginUserInput true "Login credentials"
 // @Success 200 {object} useCase.LoginUserOutput
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
 // @Router /auth/login [post]
 func (h *AuthHandler) LoginUser(c *gin.Context) {
 	var input useCase.LoginUserInput
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondInvalidBody(c, err)
 		return
 	}
 
-	output, err := h.LoginUserUseCase.Execute(input)
+	ctx, end := traceUseCase(c, "LoginUserUseCase")
+	output, err := h.LoginUserUseCase.Execute(ctx, input)
+	end(err)
 
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	http.SetCookie(c.Writer, &http.Cookie{
-		Name:     "refresh_token",
-		Value:    output.RefreshToken,
-		Expires:  time.Now().Add(7 * 24 * time.Hour),
-		HttpOnly: true,
-		SameSite: http.SameSiteStrictMode,
-		Path:     "/",
-	})
+	h.recordLoginDevice(c, output.UserID)
 
-	c.JSON(http.StatusOK, output.Token)
+	h.writeSession(c, output.Token, output.RefreshToken, output.Token)
 }
 
 // LoginUserWithGoogle login a user with Google account
 // @Summary Login a user with Google account
-// @Description Authenticate a user with Google account and store the refresh token (stored in HttpOnly cookie named 'refresh_token')
+// @Description Authenticate a user with Google account and store the refresh token (stored in HttpOnly cookie named 'refresh_token', plus a readable 'csrf_token' cookie).
+// @Description With the header X-Token-Transport: body, no cookie is set and the refresh token is returned in the body instead.
+// @Description With the header X-Token-Transport: session (BFF mode), the tokens are stored in an encrypted HttpOnly 'session' cookie and the response is 204.
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param credentials body useCase.LoginUserWithGoogleInput true "Login credentials"
 // @Success 200 {object} useCase.LoginUserWithGoogleOutput
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
 // @Router /auth/login/google [post]
 func (h *AuthHandler) LoginUserWithGoogle(c *gin.Context) {
 	var input useCase.LoginUserWithGoogleInput
@@ -150,64 +223,56 @@ func (h *AuthHandler) LoginUserWithGoogle(c *gin.Context) {
 	input.PreferredLang = normalizedLang
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondInvalidBody(c, err)
 		return
 	}
 
-	output, err := h.LoginUserWithGoogleUseCase.Execute(input)
+	ctx, end := traceUseCase(c, "LoginUserWithGoogleUseCase")
+	output, err := h.LoginUserWithGoogleUseCase.Execute(ctx, input)
+	end(err)
 
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	http.SetCookie(c.Writer, &http.Cookie{
-		Name:     "refresh_token",
-		Value:    output.RefreshToken,
-		Expires:  time.Now().Add(7 * 24 * time.Hour),
-		HttpOnly: true,
-		SameSite: http.SameSiteStrictMode,
-		Path:     "/",
-	})
+	h.recordLoginDevice(c, output.UserID)
 
-	c.JSON(http.StatusOK, output.Token)
+	h.writeSession(c, output.Token, output.RefreshToken, output.Token)
 }
 
 // RefreshToken refresh a token for a user
 // @Summary Refresh a token
-// @Description Refresh the JWT token using the refresh token (stored in HttpOnly cookie named 'refresh_token')
+// @Description Refresh the JWT token using the refresh token (stored in HttpOnly cookie named 'refresh_token').
+// @Description Cookie-authenticated requests must send the value of the 'csrf_token' cookie in the X-CSRF-Token header.
+// @Description Cookie-less clients send {"refreshToken": "..."} with the header X-Token-Transport: body.
+// @Description Protected routes refresh a BFF 'session' cookie on their own; this endpoint renews it explicitly and answers 204.
 // @Tags Auth
 // @Produce json
 // @Success 200 {object} useCase.RefreshTokenOutput
-// @Failure 401 {object} map[string]string
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
 // @Router /auth/refresh-token [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
-	cookie, err := c.Request.Cookie("refresh_token")
+	refreshToken, ok := h.readRefreshToken(c)
 
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "No refresh token"})
+	if !ok {
+		respondError(c, ErrNoRefreshToken)
 		return
 	}
 
-	input := useCase.RefreshTokenInput{RefreshToken: cookie.Value}
+	input := useCase.RefreshTokenInput{RefreshToken: refreshToken}
 
-	output, err := h.RefreshTokenUseCase.Execute(input)
+	ctx, end := traceUseCase(c, "RefreshTokenUseCase")
+	output, err := h.RefreshTokenUseCase.Execute(ctx, input)
+	end(err)
 
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	http.SetCookie(c.Writer, &http.Cookie{
-		Name:     "refresh_token",
-		Value:    output.RefreshToken,
-		Expires:  time.Now().Add(7 * 24 * time.Hour),
-		HttpOnly: true,
-		SameSite: http.SameSiteStrictMode,
-		Path:     "/",
-	})
-
-	c.JSON(http.StatusOK, output)
+	h.writeSession(c, output.Token, output.RefreshToken, output)
 }
 
 // VerifyUser verify a user
@@ -218,23 +283,25 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 // @Produce json
 // @Param input body useCase.VerifyUserInput true "Verification token"
 // @Success 200
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
 // @Router /auth/verify [post]
 func (h *AuthHandler) VerifyUser(c *gin.Context) {
 
 	var input useCase.VerifyUserInput
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondInvalidBody(c, err)
 		return
 	}
 
-	err := h.VerifyUserUseCase.Execute(input)
+	ctx, end := traceUseCase(c, "VerifyUserUseCase")
+	err := h.VerifyUserUseCase.Execute(ctx, input)
+	end(err)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -242,62 +309,99 @@ func (h *AuthHandler) VerifyUser(c *gin.Context) {
 
 }
 
+// VerifyUserWithCode verify a user with a code
+// @Summary Verify a user with a code
+// @Description Verify a user account using the 6-digit code received in the email (for clients that cannot open the link)
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body useCase.VerifyUserWithCodeInput true "Email and verification code"
+// @Success 200
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 429 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
+// @Router /auth/verify/code [post]
+func (h *AuthHandler) VerifyUserWithCode(c *gin.Context) {
+	var input useCase.VerifyUserWithCodeInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+
+	ctx, end := traceUseCase(c, "VerifyUserWithCodeUseCase")
+	err := h.VerifyUserWithCodeUseCase.Execute(ctx, input)
+	end(err)
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
 // RequestVerifyUserEmail get a verification token
 // @Summary Get a verification token
-// @Description Get a verification token for a user
+// @Description Send a verification email (link and code) to the user. The response is the same whatever the state of the account.
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param input body useCase.RequestVerifyUserEmailInput true "User email"
-// @Success 200
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
-// @Router /auth/get-verification-token [post]
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} apperror.Problem
+// @Failure 503 {object} apperror.Problem
+// @Router /auth/request-verify-user [post]
 func (h *AuthHandler) RequestVerifyUserEmail(c *gin.Context) {
 	var input useCase.RequestVerifyUserEmailInput
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondInvalidBody(c, err)
 		return
 	}
 
-	err := h.RequestVerifyUserEmailUseCase.Execute(input)
-
+	err := h.dispatcher.Dispatch(c.Request.Context(), "request_verify_user_email", func(ctx context.Context) error {
+		return h.RequestVerifyUserEmailUseCase.Execute(ctx, input)
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.Status(http.StatusOK)
+	c.JSON(http.StatusAccepted, gin.H{"message": acceptedMessage})
 }
 
 // RequestResetPassword request a password reset email
 // @Summary Request a password reset email
-// @Description Request a password reset email for a user
+// @Description Request a password reset email for a user. The response is the same whether or not an account exists.
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param input body useCase.RequestResetPasswordInput true "User email"
-// @Success 200
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} apperror.Problem
+// @Failure 503 {object} apperror.Problem
 // @Router /auth/request-reset-password [post]
 func (h *AuthHandler) RequestResetPassword(c *gin.Context) {
 	var input useCase.RequestResetPasswordInput
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondInvalidBody(c, err)
 		return
 	}
 
-	err := h.RequestResetPasswordUseCase.Execute(input)
+	input.PreferredLang = h.LangNormalizer.Normalize(c.GetHeader("Accept-Language"))
 
+	err := h.dispatcher.Dispatch(c.Request.Context(), "request_reset_password", func(ctx context.Context) error {
+		return h.RequestResetPasswordUseCase.Execute(ctx, input)
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.Status(http.StatusOK)
+	c.JSON(http.StatusAccepted, gin.H{"message": acceptedMessage})
 }
 
 // ResetPassword reset a user password
@@ -308,22 +412,24 @@ func (h *AuthHandler) RequestResetPassword(c *gin.Context) {
 // @Produce json
 // @Param input body useCase.ResetPasswordInput true "Reset password credentials"
 // @Success 200
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 500 {object} apperror.Problem
 // @Router /auth/reset-password [post]
 func (h *AuthHandler) ResetPassword(c *gin.Context) {
 	var input useCase.ResetPasswordInput
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondInvalidBody(c, err)
 		return
 	}
 
-	err := h.ResetPasswordUseCase.Execute(input)
+	ctx, end := traceUseCase(c, "ResetPasswordUseCase")
+	err := h.ResetPasswordUseCase.Execute(ctx, input)
+	end(err)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -332,36 +438,267 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 
 // LogoutUser logout a user
 // @Summary Logout a user
-// @Description Logout a user and delete the refresh token
+// @Description Logout a user and delete the refresh token.
+// @Description Cookie-authenticated requests must send the value of the 'csrf_token' cookie in the X-CSRF-Token header; cookie-less clients send {"refreshToken": "..."}.
 // @Tags Auth
 // @Produce json
 // @Success 200
-// @Failure 401 {object} map[string]string
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
 // @Router /auth/logout [post]
 func (h *AuthHandler) LogoutUser(c *gin.Context) {
-	cookie, err := c.Request.Cookie("refresh_token")
+	refreshToken, ok := h.readRefreshToken(c)
+
+	if !ok {
+		respondError(c, ErrNoRefreshToken)
+		return
+	}
+	input := &useCase.DisconnectUserInput{RefreshToken: refreshToken}
+
+	ctx, end := traceUseCase(c, "DisconnectUserUseCase")
+	err := h.DisconnectUserUseCase.Execute(ctx, input)
+	end(err)
 
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "No refresh token"})
+		respondError(c, err)
 		return
 	}
-	input := &useCase.DisconnectUserInput{RefreshToken: cookie.Value}
 
-	err = h.DisconnectUserUseCase.Execute(input)
+	h.cookiePolicy.ClearRefreshToken(c.Writer)
+	if h.session != nil {
+		h.session.Clear(c.Writer)
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Reauthenticate grant a step-up token
+// @Summary Re-authenticate for a sensitive operation
+// @Description Prove the user's identity again (password, TOTP or passkey) and get a short-lived step-up token.
+// @Description Send it in the X-Step-Up-Token header to endpoints that require a recent authentication. The session tokens are left untouched.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body useCase.ReauthenticateInput true "Credentials"
+// @Success 200 {object} useCase.ReauthenticateOutput
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Router /auth/reauthenticate [post]
+func (h *AuthHandler) Reauthenticate(c *gin.Context) {
+	var input useCase.ReauthenticateInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		respondError(c, security.ErrInvalidToken)
+		return
+	}
+	input.UserID = userID
+
+	ctx, end := traceUseCase(c, "ReauthenticateUseCase")
+	output, err := h.ReauthenticateUseCase.Execute(ctx, input)
+	end(err)
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+// ReportSuspiciousLogin handle the "this wasn't me" link of a new sign-in email
+// @Summary Report a suspicious sign-in
+// @Description Revoke every session of the user and send a password reset email, using the token of the new sign-in email
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body useCase.ReportSuspiciousLoginInput true "Token from the new sign-in email"
+// @Success 200
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Router /auth/report-login [post]
+func (h *AuthHandler) ReportSuspiciousLogin(c *gin.Context) {
+	var input useCase.ReportSuspiciousLoginInput
 
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+
+	ctx, end := traceUseCase(c, "ReportSuspiciousLoginUseCase")
+	err := h.ReportSuspiciousLoginUseCase.Execute(ctx, input)
+	end(err)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// RequestGuardianConsent send the guardian consent email again
+// @Summary Resend the guardian consent email
+// @Description Send the consent email again to the guardian of a minor account. The response is the same whatever the state of the account.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body useCase.RequestGuardianConsentInput true "Email of the minor account"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} apperror.Problem
+// @Failure 503 {object} apperror.Problem
+// @Router /auth/request-guardian-consent [post]
+func (h *AuthHandler) RequestGuardianConsent(c *gin.Context) {
+	var input useCase.RequestGuardianConsentInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondInvalidBody(c, err)
 		return
 	}
 
-	http.SetCookie(c.Writer, &http.Cookie{
-		Name:     "refresh_token",
-		Value:    "",
-		Expires:  time.Now().Add(-1 * time.Hour),
-		HttpOnly: true,
-		SameSite: http.SameSiteStrictMode,
-		Path:     "/",
+	err := h.dispatcher.Dispatch(c.Request.Context(), "request_guardian_consent", func(ctx context.Context) error {
+		return h.RequestGuardianConsentUseCase.Execute(ctx, input)
 	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": acceptedMessage})
+}
+
+// ConfirmGuardianConsent record the consent of a guardian
+// @Summary Confirm guardian consent
+// @Description Record the consent of the guardian of a minor account, using the token of the guardian consent email
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body useCase.ConfirmGuardianConsentInput true "Token from the guardian consent email"
+// @Success 200
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Router /auth/guardian-consent [post]
+func (h *AuthHandler) ConfirmGuardianConsent(c *gin.Context) {
+	var input useCase.ConfirmGuardianConsentInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+
+	ctx, end := traceUseCase(c, "ConfirmGuardianConsentUseCase")
+	err := h.ConfirmGuardianConsentUseCase.Execute(ctx, input)
+	end(err)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
 
 	c.Status(http.StatusOK)
 }
+
+// recordLoginDevice checks the device of a successful login off the request path.
+func (h *AuthHandler) recordLoginDevice(c *gin.Context, userID uuid.UUID) {
+	input := useCase.RecordLoginDeviceInput{
+		UserID: userID,
+		Login: fingerprint.LoginContext{
+			UserAgent: c.Request.UserAgent(),
+			IP:        c.ClientIP(),
+		},
+	}
+
+	// The login itself succeeded: when the queue is full, the device goes unchecked rather than failing it.
+	_ = h.dispatcher.Dispatch(c.Request.Context(), "record_login_device", func(ctx context.Context) error {
+		return h.RecordLoginDeviceUseCase.Execute(ctx, input)
+	})
+}
+
+// writeSession hands the refresh token to the client, as cookies by default, in the body for cookie-less
+// clients or in the encrypted session cookie in BFF mode. cookieBody is the response sent along with the
+// refresh cookie.
+func (h *AuthHandler) writeSession(c *gin.Context, token string, refreshToken string, cookieBody any) {
+	if c.GetHeader(TokenTransportHeader) == "body" {
+		c.JSON(http.StatusOK, gin.H{"token": token, "refreshToken": refreshToken})
+		return
+	}
+
+	if h.usesSession(c) {
+		if err := h.session.Set(c.Writer, cookie.SessionTokens{Token: token, RefreshToken: refreshToken}); err != nil {
+			respondError(c, err)
+			return
+		}
+		if _, err := h.cookiePolicy.SetCSRFToken(c.Writer); err != nil {
+			respondError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	if _, err := h.cookiePolicy.SetRefreshToken(c.Writer, refreshToken); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cookieBody)
+}
+
+// readRefreshToken reads the refresh cookie, the BFF session cookie, or the JSON body of cookie-less
+// clients.
+func (h *AuthHandler) readRefreshToken(c *gin.Context) (string, bool) {
+	if refreshCookie, err := c.Request.Cookie(h.cookiePolicy.RefreshTokenName()); err == nil && refreshCookie.Value != "" {
+		return refreshCookie.Value, true
+	}
+
+	if h.session != nil {
+		if tokens, err := h.session.Read(c.Request); err == nil {
+			return tokens.RefreshToken, true
+		}
+	}
+
+	var input useCase.RefreshTokenInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		return "", false
+	}
+
+	return input.RefreshToken, true
+}
+
+// usesSession is true for BFF clients: those asking for it at login, and those already holding a session.
+func (h *AuthHandler) usesSession(c *gin.Context) bool {
+	if h.session == nil {
+		return false
+	}
+	if c.GetHeader(TokenTransportHeader) == "session" {
+		return true
+	}
+	_, err := c.Request.Cookie(h.session.Name())
+	return err == nil
+}
+
+// newBFFSession returns nil when BFF mode is disabled, so JWTAuthMiddleware only accepts the
+// Authorization header.
+func (h *AuthHandler) newBFFSession() *middleware.BFFSession {
+	if h.session == nil {
+		return nil
+	}
+
+	return &middleware.BFFSession{
+		Cookie: h.session,
+		Refresh: func(c *gin.Context, refreshToken string) (string, string, error) {
+			ctx, end := traceUseCase(c, "RefreshTokenUseCase")
+			output, err := h.RefreshTokenUseCase.Execute(ctx, useCase.RefreshTokenInput{RefreshToken: refreshToken})
+			end(err)
+			if err != nil {
+				return "", "", err
+			}
+			return output.Token, output.RefreshToken, nil
+		},
+	}
+}
@@ -0,0 +1,242 @@
+package http
+
+import (
+	"jamlink-backend/internal/adapter/http/middleware"
+	"jamlink-backend/internal/modules/consent/domain/consent"
+	"jamlink-backend/internal/modules/consent/usecase"
+	"jamlink-backend/internal/shared/security"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ConsentHandler struct {
+	ListDocumentsUseCase       *consentUseCase.ListDocumentsUseCase
+	PublishDocumentUseCase     *consentUseCase.PublishDocumentUseCase
+	GetConsentStatusUseCase    *consentUseCase.GetConsentStatusUseCase
+	AcceptDocumentsUseCase     *consentUseCase.AcceptDocumentsUseCase
+	SetMarketingConsentUseCase *consentUseCase.SetMarketingConsentUseCase
+	ListConsentHistoryUseCase  *consentUseCase.ListConsentHistoryUseCase
+}
+
+// NewConsentHandler registers the consent routes on authenticated routers that are not behind the
+// consent gate, since they are how a user gets through it.
+func NewConsentHandler(router *gin.Engine, authenticated gin.IRoutes, listDocumentsUC *consentUseCase.ListDocumentsUseCase, publishDocumentUC *consentUseCase.PublishDocumentUseCase, getConsentStatusUC *consentUseCase.GetConsentStatusUseCase, acceptDocumentsUC *consentUseCase.AcceptDocumentsUseCase, setMarketingConsentUC *consentUseCase.SetMarketingConsentUseCase, listConsentHistoryUC *consentUseCase.ListConsentHistoryUseCase) {
+	handler := &ConsentHandler{
+		ListDocumentsUseCase:       listDocumentsUC,
+		PublishDocumentUseCase:     publishDocumentUC,
+		GetConsentStatusUseCase:    getConsentStatusUC,
+		AcceptDocumentsUseCase:     acceptDocumentsUC,
+		SetMarketingConsentUseCase: setMarketingConsentUC,
+		ListConsentHistoryUseCase:  listConsentHistoryUC,
+	}
+
+	router.GET("/consents/documents", handler.ListDocuments)
+
+	authenticated.POST("/consents/documents", handler.PublishDocument)
+	authenticated.GET("/consents", handler.GetConsentStatus)
+	authenticated.GET("/consents/history", handler.ListConsentHistory)
+	authenticated.POST("/consents/accept", middleware.RejectImpersonation(), handler.AcceptDocuments)
+	authenticated.PUT("/consents/marketing", middleware.RejectImpersonation(), handler.SetMarketingConsent)
+}
+
+// ListDocuments list the active legal documents
+// @Summary List the active legal documents
+// @Description Return the terms of service and privacy policy versions in effect. Their versions must be sent back on registration.
+// @Tags Consents
+// @Produce json
+// @Success 200 {array} consent.Document
+// @Router /consents/documents [get]
+func (h *ConsentHandler) ListDocuments(c *gin.Context) {
+	ctx, end := traceUseCase(c, "ListDocumentsUseCase")
+	documents, err := h.ListDocumentsUseCase.Execute(ctx)
+	end(err)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, documents)
+}
+
+// PublishDocument publish a new document version
+// @Summary Publish a new document version (admin)
+// @Description Publish a version of the terms of service or privacy policy. Once it is in effect, users must accept it before using protected routes.
+// @Tags Consents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body consentUseCase.PublishDocumentInput true "Document version"
+// @Success 201 {object} consent.Document
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Router /consents/documents [post]
+func (h *ConsentHandler) PublishDocument(c *gin.Context) {
+	var input consentUseCase.PublishDocumentInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		respondError(c, security.ErrInvalidToken)
+		return
+	}
+	input.PublishedBy = userID
+
+	ctx, end := traceUseCase(c, "PublishDocumentUseCase")
+	document, err := h.PublishDocumentUseCase.Execute(ctx, input)
+	end(err)
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, document)
+}
+
+// GetConsentStatus get the consents of the user
+// @Summary Get my consents
+// @Description Return the active documents with the versions the user accepted, the marketing opt-in, and whether a new acceptance is required
+// @Tags Consents
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} consentUseCase.ConsentStatusOutput
+// @Failure 401 {object} apperror.Problem
+// @Router /consents [get]
+func (h *ConsentHandler) GetConsentStatus(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		respondError(c, security.ErrInvalidToken)
+		return
+	}
+
+	ctx, end := traceUseCase(c, "GetConsentStatusUseCase")
+	output, err := h.GetConsentStatusUseCase.Execute(ctx, userID)
+	end(err)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+// ListConsentHistory list the consent history of the user
+// @Summary Get my consent history
+// @Description Return every acceptance and marketing choice of the user, most recent first
+// @Tags Consents
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} consent.Record
+// @Failure 401 {object} apperror.Problem
+// @Router /consents/history [get]
+func (h *ConsentHandler) ListConsentHistory(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		respondError(c, security.ErrInvalidToken)
+		return
+	}
+
+	ctx, end := traceUseCase(c, "ListConsentHistoryUseCase")
+	records, err := h.ListConsentHistoryUseCase.Execute(ctx, userID)
+	end(err)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, records)
+}
+
+// AcceptDocuments accept the active documents
+// @Summary Accept the terms of service and privacy policy
+// @Description Record the acceptance of the active versions. Protected routes answer 403 with the code "consent_required" until this is done for every active document.
+// @Tags Consents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body consentUseCase.AcceptDocumentsInput true "Accepted versions"
+// @Success 200
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Router /consents/accept [post]
+func (h *ConsentHandler) AcceptDocuments(c *gin.Context) {
+	var input consentUseCase.AcceptDocumentsInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		respondError(c, security.ErrInvalidToken)
+		return
+	}
+	input.UserID = userID
+	input.Source = consentSource(c)
+
+	ctx, end := traceUseCase(c, "AcceptDocumentsUseCase")
+	err = h.AcceptDocumentsUseCase.Execute(ctx, input)
+	end(err)
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// SetMarketingConsent opt in or out of marketing emails
+// @Summary Set my marketing email preference
+// @Tags Consents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body consentUseCase.SetMarketingConsentInput true "Marketing opt-in"
+// @Success 200
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Router /consents/marketing [put]
+func (h *ConsentHandler) SetMarketingConsent(c *gin.Context) {
+	var input consentUseCase.SetMarketingConsentInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		respondError(c, security.ErrInvalidToken)
+		return
+	}
+	input.UserID = userID
+	input.Source = consentSource(c)
+
+	ctx, end := traceUseCase(c, "SetMarketingConsentUseCase")
+	err = h.SetMarketingConsentUseCase.Execute(ctx, input)
+	end(err)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// consentSource keeps where a consent was given as evidence.
+func consentSource(c *gin.Context) consent.Source {
+	return consent.Source{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
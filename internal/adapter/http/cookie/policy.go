@@ -0,0 +1,147 @@
+package cookie
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	refreshTokenName = "refresh_token"
+	csrfTokenName    = "csrf_token"
+	sessionName      = "session"
+	hostPrefix       = "__Host-"
+
+	// RefreshTokenPath scopes the refresh cookie to the only endpoints that read it.
+	RefreshTokenPath = "/auth"
+	// DefaultRefreshTokenTTL is the lifetime of the cookies when Policy.RefreshTokenTTL is not set.
+	DefaultRefreshTokenTTL = 7 * 24 * time.Hour
+)
+
+var (
+	ErrHostPrefixRequiresSecure   = errors.New("cookie: the __Host- prefix requires COOKIE_SECURE=true")
+	ErrHostPrefixForbidsDomain    = errors.New("cookie: the __Host- prefix cannot be combined with COOKIE_DOMAIN")
+	ErrSameSiteNoneRequiresSecure = errors.New("cookie: COOKIE_SAMESITE=none requires COOKIE_SECURE=true")
+	ErrInvalidSameSite            = errors.New("cookie: COOKIE_SAMESITE must be strict, lax or none")
+)
+
+// Policy holds the attributes shared by every auth cookie, so they are defined in a single place.
+// RefreshTokenTTL should match the lifetime of the refresh tokens.
+type Policy struct {
+	Secure          bool
+	Domain          string
+	SameSite        http.SameSite
+	HostPrefix      bool
+	RefreshTokenTTL time.Duration
+}
+
+// ParseSameSite parses "strict" (the default when empty), "lax" or "none".
+func ParseSameSite(value string) (http.SameSite, error) {
+	switch strings.ToLower(value) {
+	case "", "strict":
+		return http.SameSiteStrictMode, nil
+	case "lax":
+		return http.SameSiteLaxMode, nil
+	case "none":
+		return http.SameSiteNoneMode, nil
+	default:
+		return 0, ErrInvalidSameSite
+	}
+}
+
+func (p Policy) Validate() error {
+	if p.HostPrefix && !p.Secure {
+		return ErrHostPrefixRequiresSecure
+	}
+	if p.HostPrefix && p.Domain != "" {
+		return ErrHostPrefixForbidsDomain
+	}
+	if p.SameSite == http.SameSiteNoneMode && !p.Secure {
+		return ErrSameSiteNoneRequiresSecure
+	}
+	return nil
+}
+
+func (p Policy) maxAge() int {
+	if p.RefreshTokenTTL <= 0 {
+		return int(DefaultRefreshTokenTTL.Seconds())
+	}
+	return int(p.RefreshTokenTTL.Seconds())
+}
+
+func (p Policy) RefreshTokenName() string {
+	return p.name(refreshTokenName)
+}
+
+func (p Policy) CSRFTokenName() string {
+	return p.name(csrfTokenName)
+}
+
+func (p Policy) SessionName() string {
+	return p.name(sessionName)
+}
+
+// SetRefreshToken sets the refresh cookie and a matching CSRF cookie, and returns the CSRF token.
+func (p Policy) SetRefreshToken(w http.ResponseWriter, value string) (string, error) {
+	http.SetCookie(w, p.cookie(refreshTokenName, value, p.refreshPath(), p.maxAge(), true))
+
+	return p.SetCSRFToken(w)
+}
+
+// SetCSRFToken sets a fresh CSRF cookie and returns its value. The cookie must be readable by the
+// frontend so it can echo it in the X-CSRF-Token header.
+func (p Policy) SetCSRFToken(w http.ResponseWriter) (string, error) {
+	csrfToken, err := newCSRFToken()
+	if err != nil {
+		return "", err
+	}
+
+	http.SetCookie(w, p.cookie(csrfTokenName, csrfToken, "/", p.maxAge(), false))
+
+	return csrfToken, nil
+}
+
+func (p Policy) ClearRefreshToken(w http.ResponseWriter) {
+	http.SetCookie(w, p.cookie(refreshTokenName, "", p.refreshPath(), -1, true))
+	http.SetCookie(w, p.cookie(csrfTokenName, "", "/", -1, false))
+}
+
+// refreshPath is /auth, except with the __Host- prefix which browsers only accept with Path=/.
+func (p Policy) refreshPath() string {
+	if p.HostPrefix {
+		return "/"
+	}
+	return RefreshTokenPath
+}
+
+func (p Policy) name(base string) string {
+	if p.HostPrefix {
+		return hostPrefix + base
+	}
+	return base
+}
+
+func (p Policy) cookie(base, value, path string, maxAge int, httpOnly bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     p.name(base),
+		Value:    value,
+		Path:     path,
+		Domain:   p.Domain,
+		MaxAge:   maxAge,
+		Secure:   p.Secure,
+		HttpOnly: httpOnly,
+		SameSite: p.SameSite,
+	}
+}
+
+func newCSRFToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("cookie: generate csrf token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
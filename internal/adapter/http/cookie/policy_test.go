@@ -0,0 +1,67 @@
+package cookie
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		policy      Policy
+		expectedErr error
+	}{
+		{Policy{SameSite: http.SameSiteStrictMode}, nil},
+		{Policy{Secure: true, HostPrefix: true, SameSite: http.SameSiteLaxMode}, nil},
+		{Policy{HostPrefix: true, SameSite: http.SameSiteStrictMode}, ErrHostPrefixRequiresSecure},
+		{Policy{Secure: true, HostPrefix: true, Domain: "jamlink.app"}, ErrHostPrefixForbidsDomain},
+		{Policy{SameSite: http.SameSiteNoneMode}, ErrSameSiteNoneRequiresSecure},
+	}
+
+	for _, tt := range tests {
+		err := tt.policy.Validate()
+		if tt.expectedErr == nil {
+			assert.NoError(t, err, "Expected no error for policy: %+v", tt.policy)
+		} else {
+			assert.ErrorIs(t, err, tt.expectedErr, "Expected %v for policy: %+v", tt.expectedErr, tt.policy)
+		}
+	}
+}
+
+func TestPolicy_SetRefreshToken(t *testing.T) {
+	policy := Policy{Secure: true, Domain: "jamlink.app", SameSite: http.SameSiteLaxMode}
+	recorder := httptest.NewRecorder()
+
+	csrfToken, err := policy.SetRefreshToken(recorder, "refresh.jwt")
+
+	assert.NoError(t, err)
+	cookies := recorder.Result().Cookies()
+	if assert.Len(t, cookies, 2) {
+		assert.Equal(t, "refresh_token", cookies[0].Name)
+		assert.Equal(t, "/auth", cookies[0].Path)
+		assert.True(t, cookies[0].HttpOnly)
+		assert.True(t, cookies[0].Secure)
+		assert.Equal(t, "jamlink.app", cookies[0].Domain)
+
+		assert.Equal(t, "csrf_token", cookies[1].Name)
+		assert.Equal(t, csrfToken, cookies[1].Value)
+		assert.False(t, cookies[1].HttpOnly)
+	}
+}
+
+func TestPolicy_HostPrefix(t *testing.T) {
+	policy := Policy{Secure: true, HostPrefix: true, SameSite: http.SameSiteStrictMode}
+	recorder := httptest.NewRecorder()
+
+	_, err := policy.SetRefreshToken(recorder, "refresh.jwt")
+
+	assert.NoError(t, err)
+	cookies := recorder.Result().Cookies()
+	if assert.Len(t, cookies, 2) {
+		assert.Equal(t, "__Host-refresh_token", cookies[0].Name)
+		assert.Equal(t, "/", cookies[0].Path)
+		assert.Equal(t, "__Host-csrf_token", cookies[1].Name)
+	}
+}
@@ -0,0 +1,121 @@
+package cookie
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	ErrInvalidSessionKey = errors.New("cookie: BFF_SESSION_KEY must be 32 bytes encoded in base64")
+	ErrInvalidSession    = errors.New("cookie: invalid session cookie")
+)
+
+// SessionTokens is the content of the encrypted session cookie used in BFF mode. The refresh token is
+// kept next to the access token so the server can refresh the session without the browser's help.
+type SessionTokens struct {
+	Token        string `json:"t"`
+	RefreshToken string `json:"r"`
+}
+
+// Session stores the tokens of browser clients in an AES-GCM encrypted HttpOnly cookie, so the access
+// token never reaches JavaScript.
+type Session struct {
+	policy Policy
+	aead   cipher.AEAD
+}
+
+func NewSession(policy Policy, key []byte) (*Session, error) {
+	if len(key) != 32 {
+		return nil, ErrInvalidSessionKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("cookie: session cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("cookie: session cipher: %w", err)
+	}
+
+	return &Session{policy: policy, aead: aead}, nil
+}
+
+// SessionFromKey returns a nil Session, i.e. BFF mode disabled, when rawKey, the base64 session key,
+// is empty.
+func SessionFromKey(policy Policy, rawKey string) (*Session, error) {
+	if rawKey == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(rawKey)
+	if err != nil {
+		return nil, ErrInvalidSessionKey
+	}
+
+	return NewSession(policy, key)
+}
+
+func (s *Session) Name() string {
+	return s.policy.SessionName()
+}
+
+func (s *Session) Set(w http.ResponseWriter, tokens SessionTokens) error {
+	plaintext, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("cookie: session nonce: %w", err)
+	}
+
+	sealed := s.aead.Seal(nonce, nonce, plaintext, []byte(s.Name()))
+
+	http.SetCookie(w, s.policy.cookie(sessionName, base64.RawURLEncoding.EncodeToString(sealed), "/", s.policy.maxAge(), true))
+	return nil
+}
+
+// Read returns http.ErrNoCookie when the request has no session, and ErrInvalidSession when the cookie
+// cannot be decrypted.
+func (s *Session) Read(r *http.Request) (SessionTokens, error) {
+	sessionCookie, err := r.Cookie(s.Name())
+	if err != nil {
+		return SessionTokens{}, err
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(sessionCookie.Value)
+	if err != nil || len(sealed) < s.aead.NonceSize() {
+		return SessionTokens{}, ErrInvalidSession
+	}
+
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, []byte(s.Name()))
+	if err != nil {
+		return SessionTokens{}, ErrInvalidSession
+	}
+
+	var tokens SessionTokens
+	if err := json.Unmarshal(plaintext, &tokens); err != nil {
+		return SessionTokens{}, ErrInvalidSession
+	}
+
+	return tokens, nil
+}
+
+func (s *Session) Clear(w http.ResponseWriter) {
+	http.SetCookie(w, s.policy.cookie(sessionName, "", "/", -1, true))
+	http.SetCookie(w, s.policy.cookie(csrfTokenName, "", "/", -1, false))
+}
+
+func (s *Session) Policy() Policy {
+	return s.policy
+}
@@ -0,0 +1,54 @@
+package cookie
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSession_RoundTrip(t *testing.T) {
+	session, err := NewSession(Policy{SameSite: http.SameSiteStrictMode}, bytes.Repeat([]byte{1}, 32))
+	assert.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	tokens := SessionTokens{Token: "access.jwt", RefreshToken: "refresh.jwt"}
+	assert.NoError(t, session.Set(recorder, tokens))
+
+	cookies := recorder.Result().Cookies()
+	if assert.Len(t, cookies, 1) {
+		assert.Equal(t, "session", cookies[0].Name)
+		assert.True(t, cookies[0].HttpOnly)
+		assert.NotContains(t, cookies[0].Value, "access.jwt")
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.AddCookie(cookies[0])
+
+	read, err := session.Read(request)
+	assert.NoError(t, err)
+	assert.Equal(t, tokens, read)
+}
+
+func TestSession_RejectsForeignCookie(t *testing.T) {
+	policy := Policy{SameSite: http.SameSiteStrictMode}
+	session, _ := NewSession(policy, bytes.Repeat([]byte{1}, 32))
+	other, _ := NewSession(policy, bytes.Repeat([]byte{2}, 32))
+
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, other.Set(recorder, SessionTokens{Token: "access.jwt"}))
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.AddCookie(recorder.Result().Cookies()[0])
+
+	_, err := session.Read(request)
+	assert.ErrorIs(t, err, ErrInvalidSession)
+}
+
+func TestNewSession_InvalidKey(t *testing.T) {
+	_, err := NewSession(Policy{}, []byte("short"))
+
+	assert.ErrorIs(t, err, ErrInvalidSessionKey)
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"context"
+	"jamlink-backend/internal/infra/health"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout keeps a hanging dependency from outliving the orchestrator's probe timeout.
+const readinessTimeout = 2 * time.Second
+
+type HealthHandler struct {
+	Probe *health.Probe
+}
+
+// NewHealthHandler registers the liveness and readiness probes used by Docker and Kubernetes.
+func NewHealthHandler(router *gin.Engine, probe *health.Probe) {
+	handler := &HealthHandler{Probe: probe}
+
+	router.GET("/healthz", handler.Live)
+	router.GET("/readyz", handler.Ready)
+}
+
+// Live report that the process is running
+// @Summary Liveness probe
+// @Description Always answers 200 while the process can serve requests. It checks no dependency, so a database outage does not get the container restarted.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /healthz [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Ready report whether the API can take traffic
+// @Summary Readiness probe
+// @Description Checks the database connection and migrations, and reports the email provider status. Answers 503 when a required check fails or the server is shutting down.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} health.Result
+// @Failure 503 {object} health.Result
+// @Router /readyz [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	result := h.Probe.Ready(ctx)
+	if !result.Ready {
+		c.JSON(http.StatusServiceUnavailable, result)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
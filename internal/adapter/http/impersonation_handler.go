@@ -0,0 +1,68 @@
+package http
+
+import (
+	"jamlink-backend/internal/adapter/http/middleware"
+	"jamlink-backend/internal/modules/auth/usecase"
+	"jamlink-backend/internal/shared/security"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ImpersonationHandler struct {
+	ImpersonateUserUseCase *useCase.ImpersonateUserUseCase
+}
+
+// NewImpersonationHandler registers the impersonation routes on a router that already authenticates
+// requests. Starting an impersonation requires a recent authentication, which impersonation tokens
+// never have.
+func NewImpersonationHandler(router gin.IRouter, securitySvc security.SecurityService, impersonateUserUC *useCase.ImpersonateUserUseCase) {
+	handler := &ImpersonationHandler{
+		ImpersonateUserUseCase: impersonateUserUC,
+	}
+
+	router.POST("/admin/impersonations", middleware.RequireRecentAuth(securitySvc, middleware.RecentAuthMaxAge), handler.ImpersonateUser)
+}
+
+// ImpersonateUser start an impersonation session
+// @Summary Impersonate a user
+// @Description Admin only, with an authentication of less than 10 minutes or a step-up token. Issue a 15-minute access token acting as the user, with the admin in the RFC 8693 "act" claim. The token cannot be refreshed, is rejected by sensitive endpoints, every request made with it is written to the audit log, and the user is notified once it has expired.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param X-Step-Up-Token header string false "Token from POST /auth/reauthenticate, when the session is older than 10 minutes"
+// @Param input body useCase.ImpersonateUserInput true "User to impersonate and reason"
+// @Success 201 {object} useCase.ImpersonateUserOutput
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /admin/impersonations [post]
+func (h *ImpersonationHandler) ImpersonateUser(c *gin.Context) {
+	var input useCase.ImpersonateUserInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+
+	actorID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		respondError(c, security.ErrInvalidToken)
+		return
+	}
+	input.ActorID = actorID
+
+	ctx, end := traceUseCase(c, "ImpersonateUserUseCase")
+	output, err := h.ImpersonateUserUseCase.Execute(ctx, input)
+	end(err)
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, output)
+}
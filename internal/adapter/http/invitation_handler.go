@@ -0,0 +1,150 @@
+package http
+
+import (
+	"errors"
+	"jamlink-backend/internal/modules/invitation/domain/invitation"
+	"jamlink-backend/internal/modules/invitation/usecase"
+	"jamlink-backend/internal/shared/lang"
+	"jamlink-backend/internal/shared/security"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type InvitationHandler struct {
+	LangNormalizer          lang.LangNormalizer
+	CreateInvitationUseCase *invitationUseCase.CreateInvitationUseCase
+	SendInvitationUseCase   *invitationUseCase.SendInvitationUseCase
+	ListInvitationsUseCase  *invitationUseCase.ListInvitationsUseCase
+}
+
+// NewInvitationHandler registers the invitation routes on a router that already authenticates requests.
+func NewInvitationHandler(router gin.IRouter, langNormalizer lang.LangNormalizer, createInvitationUC *invitationUseCase.CreateInvitationUseCase, sendInvitationUC *invitationUseCase.SendInvitationUseCase, listInvitationsUC *invitationUseCase.ListInvitationsUseCase) {
+	handler := &InvitationHandler{
+		LangNormalizer:          langNormalizer,
+		CreateInvitationUseCase: createInvitationUC,
+		SendInvitationUseCase:   sendInvitationUC,
+		ListInvitationsUseCase:  listInvitationsUC,
+	}
+
+	protected := router.Group("/invitations")
+	protected.POST("", handler.CreateInvitation)
+	protected.GET("", handler.ListInvitations)
+	protected.POST("/:code/send", handler.SendInvitation)
+}
+
+// CreateInvitation create an invite code
+// @Summary Create an invitation
+// @Description Generate an invite code. Regular users are limited in uses per code, active codes and expiry (7 days by default, 30 at most); admins are not, and may omit the expiry.
+// @Tags Invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body invitationUseCase.CreateInvitationInput true "Limits of the invitation"
+// @Success 201 {object} invitation.Invitation
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Router /invitations [post]
+func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
+	var input invitationUseCase.CreateInvitationInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		respondError(c, security.ErrInvalidToken)
+		return
+	}
+	input.CreatedBy = userID
+
+	ctx, end := traceUseCase(c, "CreateInvitationUseCase")
+	inv, err := h.CreateInvitationUseCase.Execute(ctx, input)
+	end(err)
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, inv)
+}
+
+// ListInvitations list the invitations of the user
+// @Summary List my invitations
+// @Description List the invitations created by the user and the users who registered with them
+// @Tags Invitations
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} invitationUseCase.ListInvitationsOutput
+// @Failure 401 {object} apperror.Problem
+// @Router /invitations [get]
+func (h *InvitationHandler) ListInvitations(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		respondError(c, security.ErrInvalidToken)
+		return
+	}
+
+	ctx, end := traceUseCase(c, "ListInvitationsUseCase")
+	output, err := h.ListInvitationsUseCase.Execute(ctx, userID)
+	end(err)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+// SendInvitation email an invitation
+// @Summary Send an invitation by email
+// @Description Email one of the user's invitations, in the language of the Accept-Language header
+// @Tags Invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "Invitation code"
+// @Param input body invitationUseCase.SendInvitationInput true "Recipient"
+// @Success 200
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /invitations/{code}/send [post]
+func (h *InvitationHandler) SendInvitation(c *gin.Context) {
+	var input invitationUseCase.SendInvitationInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		respondError(c, security.ErrInvalidToken)
+		return
+	}
+	input.SenderID = userID
+	input.Code = c.Param("code")
+	input.Lang = h.LangNormalizer.Normalize(c.GetHeader("Accept-Language"))
+
+	ctx, end := traceUseCase(c, "SendInvitationUseCase")
+	err = h.SendInvitationUseCase.Execute(ctx, input)
+	end(err)
+
+	switch {
+	case errors.Is(err, invitation.ErrNotInvitationOwner):
+		// The invitations of others are reported as missing, so that their codes cannot be probed.
+		respondError(c, errors.Join(invitation.ErrInvitationNotFound, err))
+		return
+	case err != nil:
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
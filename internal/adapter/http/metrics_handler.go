@@ -0,0 +1,45 @@
+package http
+
+import (
+	"crypto/subtle"
+	"jamlink-backend/internal/shared/apperror"
+	"jamlink-backend/internal/shared/metrics"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var ErrInvalidMetricsToken = apperror.Unauthorized("invalid_metrics_token", "invalid metrics token")
+
+type MetricsHandler struct {
+	Token string
+}
+
+// NewMetricsHandler registers the Prometheus endpoint. With a token, scrapers must send it as a bearer
+// token; without one, the router must only be reachable from the internal network.
+func NewMetricsHandler(router gin.IRoutes, token string) {
+	handler := &MetricsHandler{Token: token}
+
+	router.GET("/metrics", handler.Metrics)
+}
+
+// Metrics expose the Prometheus metrics
+// @Summary Prometheus metrics
+// @Description HTTP, database, password hashing, login, token rotation, funnel and email metrics in the Prometheus text format. Served on the internal listener, or on the main one with the metrics token.
+// @Tags Health
+// @Produce plain
+// @Security BearerAuth
+// @Success 200 {string} string
+// @Failure 401 {object} apperror.Problem
+// @Router /metrics [get]
+func (h *MetricsHandler) Metrics(c *gin.Context) {
+	if h.Token != "" {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.Token)) != 1 {
+			respondError(c, ErrInvalidMetricsToken)
+			return
+		}
+	}
+
+	metrics.Handler().ServeHTTP(c.Writer, c.Request)
+}
@@ -1,43 +1,126 @@
 package middleware
 
 import (
+	"context"
 	"jamlink-backend/internal/shared/security"
-	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-func JWTAuthMiddleware(securitySvc security.SecurityService) gin.HandlerFunc {
+// GuardianConsentPendingCode lets clients tell a minor waiting for their guardian apart from an
+// account whose email is not verified yet.
+const GuardianConsentPendingCode = "guardian_consent_pending"
+
+// ImpersonationAuditor writes every request made with an impersonation token to the audit log.
+type ImpersonationAuditor interface {
+	RecordImpersonatedRequest(ctx context.Context, sessionID uuid.UUID, method, path string, status int, ip string) error
+}
+
+// JWTAuthMiddleware authenticates the request with the Bearer token of the Authorization header or,
+// when bff is not nil and no header is sent, with the encrypted session cookie. It accepts both user and
+// service principals, see RequireUser and RequireService, but only with their access tokens.
+// Impersonation tokens are rejected when auditor is nil, as their requests could not be audited. The
+// token is a JWT or an opaque token depending on the security.TokenStrategy; both resolve to the same
+// claims, so the principal does not depend on it.
+func JWTAuthMiddleware(securitySvc security.SecurityService, bff *BFFSession, auditor ImpersonationAuditor) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
+		claims, ok := authenticate(c, securitySvc, bff)
 
-		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header missing or invalid"})
-			c.Abort()
+		if !ok {
 			return
 		}
 
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+		principal := security.PrincipalFromClaims(claims)
+		if !isAccessToken(claims, principal) {
+			AbortWithError(c, security.ErrInvalidToken)
+			return
+		}
 
-		claims, err := securitySvc.ValidateJWT(tokenString)
+		c.Request = c.Request.WithContext(security.WithPrincipal(c.Request.Context(), principal))
 
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
-			c.Abort()
+		// Service tokens have no account to verify, and never set user_id.
+		if principal.IsService() {
+			WithLogAttrs(c, "client_id", principal.ID)
+			c.Next()
 			return
 		}
 
 		isVerified, ok := claims["isVerified"].(bool)
 
 		if !ok || !isVerified {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "your account is not verified"})
-			c.Abort()
+			if claims[security.PendingVerificationClaim] == security.PendingGuardianConsent {
+				AbortWithError(c, ErrGuardianConsentPending)
+				return
+			}
+
+			AbortWithError(c, ErrAccountNotVerified)
 			return
 		}
 
 		c.Set("user_id", claims["id"])
+		c.Set("auth_context", security.AuthContextFromClaims(claims))
+		WithLogAttrs(c, "user_id", claims["id"])
+
+		actor := security.ActorFromClaims(claims)
+		if actor == nil {
+			c.Next()
+			return
+		}
 
+		if auditor == nil {
+			AbortWithError(c, security.ErrInvalidToken)
+			return
+		}
+
+		c.Set("actor", actor)
+		WithLogAttrs(c, "actor_id", actor.ID, "impersonation_session_id", actor.SessionID)
 		c.Next()
+
+		// The request context may be cancelled by now, by the client or the request timeout, and those
+		// requests must be audited too.
+		auditCtx := context.WithoutCancel(c.Request.Context())
+		if err := auditor.RecordImpersonatedRequest(auditCtx, actor.SessionID, c.Request.Method, c.FullPath(), c.Writer.Status(), c.ClientIP()); err != nil {
+			Logger(c).Error("impersonation audit failed", "error", err)
+		}
 	}
 }
+
+// isAccessToken rejects the other tokens signed with the same key, such as refresh, step-up, password
+// reset or OpenID Connect partner tokens, which would otherwise authenticate requests as their subject.
+func isAccessToken(claims jwt.MapClaims, principal *security.Principal) bool {
+	switch claims["type"] {
+	case security.AccessTokenType:
+		return !principal.IsService()
+	case security.ClientTokenType:
+		return principal.IsService()
+	default:
+		return false
+	}
+}
+
+func authenticate(c *gin.Context, securitySvc security.SecurityService, bff *BFFSession) (jwt.MapClaims, bool) {
+	authHeader := c.GetHeader("Authorization")
+
+	if authHeader == "" && bff != nil && hasCookie(c, bff.Cookie.Name()) {
+		return bff.authenticate(c, securitySvc)
+	}
+
+	if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
+		AbortWithError(c, ErrAuthorizationMissing)
+		return nil, false
+	}
+
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	claims, err := securitySvc.ValidateJWT(c.Request.Context(), tokenString)
+
+	if err != nil {
+		AbortWithError(c, security.ErrInvalidToken)
+		return nil, false
+	}
+
+	return claims, true
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"jamlink-backend/internal/shared/security"
+)
+
+func TestJWTAuthMiddleware_OnlyAcceptsAccessTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := []byte("a-test-secret-of-at-least-32-characters")
+	securitySvc := security.NewSecurityService(secret, security.NewJWTStrategy(secret))
+	userID := uuid.New()
+	auth := security.NewAuthContext(security.AuthMethodPassword)
+
+	tests := []struct {
+		tokenType string
+		want      int
+	}{
+		{security.AccessTokenType, http.StatusOK},
+		{security.RefreshTokenType, http.StatusUnauthorized},
+		{security.StepUpTokenType, http.StatusUnauthorized},
+		{security.PartnerAccessTokenType, http.StatusUnauthorized},
+		{security.ClientTokenType, http.StatusUnauthorized},
+		{"reset_password", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tokenType, func(t *testing.T) {
+			token, err := securitySvc.GenerateJWT(t.Context(), &userID, nil, time.Minute, tt.tokenType, true, auth)
+			require.NoError(t, err)
+
+			router := gin.New()
+			router.Use(Problems())
+			router.GET("/me", JWTAuthMiddleware(securitySvc, nil, nil), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
+
+type recordingAuditor struct {
+	ctxErr    error
+	sessionID uuid.UUID
+	status    int
+}
+
+func (a *recordingAuditor) RecordImpersonatedRequest(ctx context.Context, sessionID uuid.UUID, _, _ string, status int, _ string) error {
+	a.ctxErr = ctx.Err()
+	a.sessionID = sessionID
+	a.status = status
+	return nil
+}
+
+func TestJWTAuthMiddleware_AuditsCancelledImpersonatedRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := []byte("a-test-secret-of-at-least-32-characters")
+	securitySvc := security.NewSecurityService(secret, security.NewJWTStrategy(secret))
+	userID, actorID, sessionID := uuid.New(), uuid.New(), uuid.New()
+	auth := security.NewAuthContext(security.AuthMethodPassword)
+
+	token, err := securitySvc.GenerateJWT(t.Context(), &userID, nil, time.Minute, security.AccessTokenType, true, auth, security.WithActor(actorID, sessionID))
+	require.NoError(t, err)
+
+	auditor := &recordingAuditor{}
+	ctx, cancel := context.WithCancel(t.Context())
+	router := gin.New()
+	router.Use(Problems())
+	router.GET("/me", JWTAuthMiddleware(securitySvc, nil, auditor), func(c *gin.Context) {
+		// The client goes away, or the request times out, before the audit runs.
+		cancel()
+		c.Status(http.StatusGatewayTimeout)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil).WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer "+token)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.NoError(t, auditor.ctxErr)
+	assert.Equal(t, sessionID, auditor.sessionID)
+	assert.Equal(t, http.StatusGatewayTimeout, auditor.status)
+}
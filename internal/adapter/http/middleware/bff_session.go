@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"jamlink-backend/internal/adapter/http/cookie"
+	"jamlink-backend/internal/shared/security"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SessionRefreshWindow is how long before its expiry the access token of a BFF session is renewed.
+const SessionRefreshWindow = 2 * time.Minute
+
+// SessionRefresher exchanges a refresh token for a new access and refresh token pair, on behalf of c.
+type SessionRefresher func(c *gin.Context, refreshToken string) (token string, newRefreshToken string, err error)
+
+// BFFSession lets JWTAuthMiddleware authenticate browser clients with the encrypted session cookie
+// instead of the Authorization header.
+type BFFSession struct {
+	Cookie  *cookie.Session
+	Refresh SessionRefresher
+}
+
+// authenticate validates the session cookie and transparently refreshes it when the access token is
+// expired or about to be. It aborts the request itself when it returns false.
+func (b *BFFSession) authenticate(c *gin.Context, securitySvc security.SecurityService) (jwt.MapClaims, bool) {
+	// The browser sends the cookie on its own, so state-changing requests must prove they come from the
+	// frontend.
+	if !isSafeMethod(c.Request.Method) && !validCSRFToken(c, b.Cookie.Policy()) {
+		AbortWithError(c, ErrInvalidCSRFToken)
+		return nil, false
+	}
+
+	claims, err := b.resolve(c, securitySvc)
+	if err != nil {
+		AbortWithError(c, err)
+		return nil, false
+	}
+
+	return claims, true
+}
+
+// Claims returns the claims of the session cookie, refreshing it like authenticate does, or nil when
+// there is no valid session. It never writes a response, for pages that redirect anonymous visitors
+// instead. It does not check the CSRF token, so its callers must not change state on their own.
+func (b *BFFSession) Claims(c *gin.Context, securitySvc security.SecurityService) jwt.MapClaims {
+	if !hasCookie(c, b.Cookie.Name()) {
+		return nil
+	}
+
+	claims, _ := b.resolve(c, securitySvc)
+	return claims
+}
+
+// resolve returns the error to answer with when the session is not valid.
+func (b *BFFSession) resolve(c *gin.Context, securitySvc security.SecurityService) (jwt.MapClaims, error) {
+	tokens, err := b.Cookie.Read(c.Request)
+	if err != nil {
+		b.Cookie.Clear(c.Writer)
+		return nil, ErrInvalidSession
+	}
+
+	claims, err := securitySvc.ValidateJWT(c.Request.Context(), tokens.Token)
+	if err == nil && !expiresWithin(claims, SessionRefreshWindow) {
+		return claims, nil
+	}
+	stillValid := err == nil
+
+	token, refreshToken, err := b.Refresh(c, tokens.RefreshToken)
+	if err != nil {
+		// A concurrent request may already have rotated the refresh token: keep going while the
+		// access token is valid, the next request will carry the new cookie.
+		if stillValid {
+			return claims, nil
+		}
+		b.Cookie.Clear(c.Writer)
+		return nil, ErrSessionExpired
+	}
+
+	if err := b.Cookie.Set(c.Writer, cookie.SessionTokens{Token: token, RefreshToken: refreshToken}); err != nil {
+		return nil, err
+	}
+
+	claims, err = securitySvc.ValidateJWT(c.Request.Context(), token)
+	if err != nil {
+		return nil, security.ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+func expiresWithin(claims jwt.MapClaims, window time.Duration) bool {
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return true
+	}
+	return time.Until(exp.Time) < window
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+	"jamlink-backend/internal/shared/security"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ConsentRequiredCode tells clients to show the current terms and privacy policy and call
+// POST /consents/accept before retrying.
+const ConsentRequiredCode = "consent_required"
+
+type ConsentChecker interface {
+	RequiresReaccept(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// RequireCurrentConsent must be used after JWTAuthMiddleware. It blocks users who have not accepted the
+// active version of the required legal documents.
+func RequireCurrentConsent(checker ConsentChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("user_id"))
+		if err != nil {
+			AbortWithError(c, security.ErrInvalidToken)
+			return
+		}
+
+		required, err := checker.RequiresReaccept(c.Request.Context(), userID)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+
+		if required {
+			AbortWithError(c, ErrConsentRequired)
+			return
+		}
+
+		c.Next()
+	}
+}
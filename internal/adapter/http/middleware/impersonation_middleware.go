@@ -0,0 +1,25 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// ImpersonationForbiddenCode tells clients that an admin acting as the user cannot reach the endpoint.
+const ImpersonationForbiddenCode = "impersonation_forbidden"
+
+// RejectImpersonation must be used after JWTAuthMiddleware, on the endpoints only the user themselves
+// may reach (credentials, legal consents, starting another impersonation).
+func RejectImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if IsImpersonated(c) {
+			AbortWithError(c, ErrImpersonationForbidden)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// IsImpersonated reports whether the request was authenticated with an impersonation token.
+func IsImpersonated(c *gin.Context) bool {
+	_, ok := c.Get("actor")
+	return ok
+}
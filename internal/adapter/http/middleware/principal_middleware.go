@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"jamlink-backend/internal/shared/security"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CurrentPrincipal returns the principal set by JWTAuthMiddleware, or nil on unauthenticated routes.
+func CurrentPrincipal(c *gin.Context) *security.Principal {
+	return security.PrincipalFromContext(c.Request.Context())
+}
+
+// RequireUser must be used after JWTAuthMiddleware, on the routes acting for a user account.
+func RequireUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if principal := CurrentPrincipal(c); principal == nil || principal.IsService() {
+			AbortWithError(c, ErrUserRequired)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireService must be used after JWTAuthMiddleware, on the routes reserved to OAuth clients. The
+// token must carry every given scope; failures follow the "insufficient_scope" error of RFC 6750.
+func RequireService(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := CurrentPrincipal(c)
+		if principal == nil || !principal.IsService() {
+			AbortWithError(c, ErrServiceRequired)
+			return
+		}
+
+		for _, scope := range scopes {
+			if !principal.HasScope(scope) {
+				c.Header("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+scope+`"`)
+				AbortWithError(c, ErrInsufficientScope.With("scope", scope))
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"jamlink-backend/internal/shared/apperror"
+	"jamlink-backend/internal/shared/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Errors of the middlewares of this package. The codes of the constants below predate the catalogue
+// and are kept for the clients that switch on them.
+var (
+	ErrAuthorizationMissing   = apperror.Unauthorized("authorization_missing", "authorization header missing or invalid")
+	ErrAccountNotVerified     = apperror.Unauthorized("account_not_verified", "your account is not verified")
+	ErrGuardianConsentPending = apperror.Unauthorized(GuardianConsentPendingCode, "your account is waiting for the consent of your guardian")
+	ErrInvalidSession         = apperror.Unauthorized("invalid_session", "invalid session")
+	ErrSessionExpired         = apperror.Unauthorized("session_expired", "session expired")
+	ErrStepUpRequired         = apperror.Unauthorized("step_up_required", "recent authentication required")
+	ErrInvalidCSRFToken       = apperror.Forbidden("invalid_csrf_token", "missing or invalid CSRF token")
+	ErrConsentRequired        = apperror.Forbidden(ConsentRequiredCode, "the terms of service or privacy policy changed and must be accepted again")
+	ErrImpersonationForbidden = apperror.Forbidden(ImpersonationForbiddenCode, "this action is not allowed while impersonating a user")
+	ErrUserRequired           = apperror.Forbidden("user_required", "this endpoint requires a user token")
+	ErrServiceRequired        = apperror.Forbidden("service_required", "this endpoint requires a client token")
+	// ErrInsufficientScope carries the missing scope in its "scope" param.
+	ErrInsufficientScope = apperror.Forbidden("insufficient_scope", "the token lacks a scope required by this endpoint")
+)
+
+// Problems answers a request that failed with an RFC 7807 problem describing the last error attached
+// to it, see apperror.From. Handlers and middlewares attach their error with AbortWithError and
+// write nothing: the status, code and message come from the catalogue, so that every endpoint reports
+// the same error the same way. The error itself is only logged, see RequestLogger.
+func Problems() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		last := c.Errors.Last()
+		if last == nil || c.Writer.Written() {
+			return
+		}
+
+		problem := apperror.From(last.Err).Problem(c.Request.URL.Path, logging.RequestID(c.Request.Context()))
+		// c.JSON keeps a Content-Type already set.
+		c.Header("Content-Type", apperror.ProblemContentType)
+		c.JSON(problem.Status, problem)
+	}
+}
+
+// AbortWithError stops the request with err, which Problems reports.
+func AbortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"jamlink-backend/internal/shared/security"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// StepUpTokenHeader carries the token returned by POST /auth/reauthenticate.
+	StepUpTokenHeader = "X-Step-Up-Token"
+
+	// RecentAuthMaxAge is how recent the authentication must be for the sensitive admin endpoints.
+	RecentAuthMaxAge = time.Minute * 10
+)
+
+// RequireRecentAuth must be used after JWTAuthMiddleware. It lets the request through when the session
+// itself was authenticated less than maxAge ago, or when a valid step-up token for the same user is sent
+// in the X-Step-Up-Token header. Impersonation tokens never pass.
+func RequireRecentAuth(securitySvc security.SecurityService, maxAge time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if IsImpersonated(c) {
+			AbortWithError(c, ErrImpersonationForbidden)
+			return
+		}
+
+		if auth, ok := c.Get("auth_context"); ok && auth.(*security.AuthContext).IsRecent(maxAge) {
+			c.Next()
+			return
+		}
+
+		if stepUpToken := c.GetHeader(StepUpTokenHeader); stepUpToken != "" {
+			claims, err := securitySvc.ValidateJWT(c.Request.Context(), stepUpToken)
+
+			if err == nil && claims["type"] == security.StepUpTokenType && claims["id"] == c.GetString("user_id") &&
+				security.AuthContextFromClaims(claims).IsRecent(maxAge) {
+				c.Next()
+				return
+			}
+		}
+
+		AbortWithError(c, ErrStepUpRequired)
+	}
+}
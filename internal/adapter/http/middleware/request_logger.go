@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"errors"
+	"jamlink-backend/internal/shared/logging"
+	"log/slog"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader carries the ID that ties a request to its log records, for support requests.
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDPattern accepts the IDs of the usual proxies and tracers, and nothing that could forge a
+// log record.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:\-]{1,128}$`)
+
+// RequestLogger stores in the request context a logger carrying the request ID, method and route, see
+// Logger, along with the trace ID when the request is traced, and the request ID itself, see
+// logging.RequestID. The ID is propagated from X-Request-ID when the caller sent a valid one, and
+// echoed in the response. Once the request is served, its
+// outcome is logged with the errors the handler attached.
+func RequestLogger(base *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), requestID))
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		requestAttrs := []any{"request_id", requestID, "method", c.Request.Method, "route", route}
+		if span := trace.SpanContextFromContext(c.Request.Context()); span.IsValid() {
+			requestAttrs = append(requestAttrs, "trace_id", span.TraceID().String())
+		}
+		setLogger(c, base.With(requestAttrs...))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []any{"status", status, "duration_ms", time.Since(start).Milliseconds(), "ip", c.ClientIP()}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "error", strings.Join(c.Errors.Errors(), "; "))
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		Logger(c).Log(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// errPanic is attached to a request whose handler panicked, which Problems reports as an internal error.
+var errPanic = errors.New("panic")
+
+// Recovery answers 500 to a request whose handler panicked, logging the panic with the request logger.
+// It must come after Problems, which writes the response.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, recovered any) {
+		Logger(c).Error("panic", "panic", recovered, "stack", string(debug.Stack()))
+		AbortWithError(c, errPanic)
+	})
+}
+
+// Logger returns the logger of the request, which use cases and repositories also get from its context.
+func Logger(c *gin.Context) *slog.Logger {
+	return logging.FromContext(c.Request.Context())
+}
+
+// WithLogAttrs adds attributes known once the request is authenticated, such as the user ID, to the
+// request logger.
+func WithLogAttrs(c *gin.Context, args ...any) {
+	setLogger(c, Logger(c).With(args...))
+}
+
+func setLogger(c *gin.Context, logger *slog.Logger) {
+	c.Request = c.Request.WithContext(logging.WithLogger(c.Request.Context(), logger))
+}
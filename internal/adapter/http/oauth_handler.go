@@ -0,0 +1,334 @@
+package http
+
+import (
+	"errors"
+	"jamlink-backend/internal/adapter/http/middleware"
+	"jamlink-backend/internal/modules/auth/domain/deviceauth"
+	"jamlink-backend/internal/modules/auth/domain/oauthclient"
+	"jamlink-backend/internal/modules/auth/domain/oidc"
+	"jamlink-backend/internal/modules/auth/usecase"
+	"jamlink-backend/internal/shared/security"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TokenRequest is the body of POST /oauth/token. Which fields are required depends on grant_type.
+type TokenRequest struct {
+	GrantType    string `form:"grant_type" json:"grant_type" binding:"required" example:"urn:ietf:params:oauth:grant-type:device_code"`
+	DeviceCode   string `form:"device_code" json:"device_code"`
+	Code         string `form:"code" json:"code"`
+	RedirectURI  string `form:"redirect_uri" json:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier" json:"code_verifier"`
+	ClientID     string `form:"client_id" json:"client_id" example:"jamlink-cli"`
+	ClientSecret string `form:"client_secret" json:"client_secret"`
+	Scope        string `form:"scope" json:"scope" example:"events:write"`
+}
+
+type OAuthHandler struct {
+	RequestDeviceAuthorizationUseCase *useCase.RequestDeviceAuthorizationUseCase
+	DecideDeviceAuthorizationUseCase  *useCase.DecideDeviceAuthorizationUseCase
+	ExchangeDeviceCodeUseCase         *useCase.ExchangeDeviceCodeUseCase
+	ClientCredentialsUseCase          *useCase.ClientCredentialsUseCase
+	RegisterOAuthClientUseCase        *useCase.RegisterOAuthClientUseCase
+	ExchangeAuthorizationCodeUseCase  *useCase.ExchangeAuthorizationCodeUseCase
+}
+
+// NewOAuthHandler registers the OAuth 2.0 endpoints on router, the browser-side approval of devices on
+// consented, as it hands out a session, and the registration of clients on authenticated. Registering a
+// client requires a recent authentication. exchangeAuthorizationCodeUC is nil when the OpenID provider
+// is disabled, and the authorization_code grant is then unsupported.
+func NewOAuthHandler(router *gin.Engine, authenticated gin.IRoutes, consented gin.IRoutes, securitySvc security.SecurityService, requestDeviceAuthorizationUC *useCase.RequestDeviceAuthorizationUseCase, decideDeviceAuthorizationUC *useCase.DecideDeviceAuthorizationUseCase, exchangeDeviceCodeUC *useCase.ExchangeDeviceCodeUseCase, clientCredentialsUC *useCase.ClientCredentialsUseCase, registerOAuthClientUC *useCase.RegisterOAuthClientUseCase, exchangeAuthorizationCodeUC *useCase.ExchangeAuthorizationCodeUseCase) {
+	handler := &OAuthHandler{
+		RequestDeviceAuthorizationUseCase: requestDeviceAuthorizationUC,
+		DecideDeviceAuthorizationUseCase:  decideDeviceAuthorizationUC,
+		ExchangeDeviceCodeUseCase:         exchangeDeviceCodeUC,
+		ClientCredentialsUseCase:          clientCredentialsUC,
+		RegisterOAuthClientUseCase:        registerOAuthClientUC,
+		ExchangeAuthorizationCodeUseCase:  exchangeAuthorizationCodeUC,
+	}
+
+	router.POST("/oauth/device_authorization", handler.RequestDeviceAuthorization)
+	router.POST("/oauth/token", handler.Token)
+
+	consented.POST("/device", middleware.RejectImpersonation(), handler.DecideDeviceAuthorization)
+	authenticated.POST("/admin/oauth-clients", middleware.RequireRecentAuth(securitySvc, middleware.RecentAuthMaxAge), handler.RegisterClient)
+}
+
+// RequestDeviceAuthorization start the device authorization grant
+// @Summary Request a device code
+// @Description RFC 8628 device authorization request, for devices that cannot show a password form. The device shows user_code and verification_uri, then polls POST /oauth/token every interval seconds.
+// @Tags OAuth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param client_id formData string true "Client identifier"
+// @Success 200 {object} useCase.RequestDeviceAuthorizationOutput
+// @Failure 400 {object} map[string]string
+// @Router /oauth/device_authorization [post]
+func (h *OAuthHandler) RequestDeviceAuthorization(c *gin.Context) {
+	var input useCase.RequestDeviceAuthorizationInput
+
+	if err := c.ShouldBind(&input); err != nil {
+		oauthError(c, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	ctx, end := traceUseCase(c, "RequestDeviceAuthorizationUseCase")
+	output, err := h.RequestDeviceAuthorizationUseCase.Execute(ctx, input)
+	end(err)
+	if err != nil {
+		oauthServerError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, output)
+}
+
+// Token issue tokens
+// @Summary OAuth 2.0 token endpoint
+// @Description Supported grants: urn:ietf:params:oauth:grant-type:device_code (RFC 8628), client_credentials and, when the OpenID provider is enabled, authorization_code.
+// @Description Device code: while the user has not decided, the error is "authorization_pending", or "slow_down" when polling faster than the interval, which then grows by 5 seconds. An expired device code answers "expired_token" and a denied one "access_denied".
+// @Description Client credentials: the client authenticates with HTTP Basic or client_id and client_secret, and gets a token with sub "client:<client_id>" and the requested scopes (all allowed scopes by default). No refresh token is issued.
+// @Description Authorization code: the client authenticates the same way and sends the code, the redirect_uri of the authorization request and the PKCE code_verifier. It gets an ID token and an access token only accepted by /oauth/userinfo.
+// @Tags OAuth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "Grant type"
+// @Param device_code formData string false "Device code, for the device_code grant"
+// @Param client_id formData string false "Client identifier"
+// @Param client_secret formData string false "Client secret, for the client_credentials grant without HTTP Basic"
+// @Param scope formData string false "Space-delimited scopes, for the client_credentials grant"
+// @Param code formData string false "Authorization code, for the authorization_code grant"
+// @Param redirect_uri formData string false "Redirect URI of the authorization request, for the authorization_code grant"
+// @Param code_verifier formData string false "PKCE verifier, for the authorization_code grant"
+// @Success 200 {object} useCase.TokenOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /oauth/token [post]
+func (h *OAuthHandler) Token(c *gin.Context) {
+	var input TokenRequest
+
+	if err := c.ShouldBind(&input); err != nil {
+		oauthError(c, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+
+	switch input.GrantType {
+	case deviceauth.GrantType:
+		h.exchangeDeviceCode(c, input)
+	case oauthclient.GrantType:
+		h.clientCredentials(c, input)
+	case oidc.GrantType:
+		if h.ExchangeAuthorizationCodeUseCase == nil {
+			oauthError(c, http.StatusBadRequest, "unsupported_grant_type", "")
+			return
+		}
+		h.exchangeAuthorizationCode(c, input)
+	default:
+		oauthError(c, http.StatusBadRequest, "unsupported_grant_type", "")
+	}
+}
+
+func (h *OAuthHandler) exchangeDeviceCode(c *gin.Context, input TokenRequest) {
+	if input.DeviceCode == "" || input.ClientID == "" {
+		oauthError(c, http.StatusBadRequest, "invalid_request", "device_code and client_id are required")
+		return
+	}
+
+	ctx, end := traceUseCase(c, "ExchangeDeviceCodeUseCase")
+	output, err := h.ExchangeDeviceCodeUseCase.Execute(ctx, useCase.ExchangeDeviceCodeInput{
+		DeviceCode: input.DeviceCode,
+		ClientID:   input.ClientID,
+	})
+	end(err)
+
+	switch {
+	case errors.Is(err, deviceauth.ErrAuthorizationPending), errors.Is(err, deviceauth.ErrSlowDown),
+		errors.Is(err, deviceauth.ErrExpiredToken), errors.Is(err, deviceauth.ErrAccessDenied):
+		oauthError(c, http.StatusBadRequest, err.Error(), "")
+		return
+	case errors.Is(err, deviceauth.ErrInvalidDeviceCode):
+		oauthError(c, http.StatusBadRequest, "invalid_grant", err.Error())
+		return
+	case err != nil:
+		oauthServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+func (h *OAuthHandler) clientCredentials(c *gin.Context, input TokenRequest) {
+	clientID, clientSecret, basic := clientAuthentication(c, input)
+
+	ctx, end := traceUseCase(c, "ClientCredentialsUseCase")
+	output, err := h.ClientCredentialsUseCase.Execute(ctx, useCase.ClientCredentialsInput{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scope:        input.Scope,
+	})
+	end(err)
+
+	switch {
+	case errors.Is(err, oauthclient.ErrInvalidClient):
+		invalidClient(c, basic)
+		return
+	case errors.Is(err, oauthclient.ErrInvalidScope):
+		oauthError(c, http.StatusBadRequest, "invalid_scope", "")
+		return
+	case err != nil:
+		oauthServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+func (h *OAuthHandler) exchangeAuthorizationCode(c *gin.Context, input TokenRequest) {
+	clientID, clientSecret, basic := clientAuthentication(c, input)
+
+	ctx, end := traceUseCase(c, "ExchangeAuthorizationCodeUseCase")
+	output, err := h.ExchangeAuthorizationCodeUseCase.Execute(ctx, useCase.ExchangeAuthorizationCodeInput{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Code:         input.Code,
+		RedirectURI:  input.RedirectURI,
+		CodeVerifier: input.CodeVerifier,
+	})
+	end(err)
+
+	switch {
+	case errors.Is(err, oauthclient.ErrInvalidClient):
+		invalidClient(c, basic)
+		return
+	case errors.Is(err, oidc.ErrInvalidCode):
+		oauthError(c, http.StatusBadRequest, "invalid_grant", err.Error())
+		return
+	case err != nil:
+		oauthServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+// clientAuthentication reads the client credentials from the Authorization header first, as RFC 6749
+// section 2.3.1 recommends, then from the body.
+func clientAuthentication(c *gin.Context, input TokenRequest) (clientID string, clientSecret string, basic bool) {
+	clientID, clientSecret, basic = c.Request.BasicAuth()
+	if !basic {
+		clientID, clientSecret = input.ClientID, input.ClientSecret
+	}
+	return clientID, clientSecret, basic
+}
+
+func invalidClient(c *gin.Context, basic bool) {
+	if basic {
+		c.Header("WWW-Authenticate", `Basic realm="jamlink"`)
+	}
+	oauthError(c, http.StatusUnauthorized, "invalid_client", "")
+}
+
+// RegisterClient register an OAuth client
+// @Summary Register an OAuth client
+// @Description Admin only, with an authentication of less than 10 minutes or a step-up token. Register a service allowed to use the client_credentials grant with the given scopes, or a relying party of the OpenID provider with the openid scopes and its redirect URIs (https, or http on localhost). The client secret is only returned in this response.
+// @Tags OAuth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param X-Step-Up-Token header string false "Token from POST /auth/reauthenticate, when the session is older than 10 minutes"
+// @Param input body useCase.RegisterOAuthClientInput true "Client name and allowed scopes"
+// @Success 201 {object} useCase.RegisterOAuthClientOutput
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Router /admin/oauth-clients [post]
+func (h *OAuthHandler) RegisterClient(c *gin.Context) {
+	var input useCase.RegisterOAuthClientInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		respondError(c, security.ErrInvalidToken)
+		return
+	}
+	input.CreatedBy = userID
+
+	ctx, end := traceUseCase(c, "RegisterOAuthClientUseCase")
+	output, err := h.RegisterOAuthClientUseCase.Execute(ctx, input)
+	end(err)
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, output)
+}
+
+// DecideDeviceAuthorization approve or deny a device
+// @Summary Approve or deny a device
+// @Description Called from the /device page of the browser, where the logged-in user types the code shown by the device.
+// @Tags OAuth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body useCase.DecideDeviceAuthorizationInput true "User code and decision"
+// @Success 200 {object} useCase.DecideDeviceAuthorizationOutput
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Router /device [post]
+func (h *OAuthHandler) DecideDeviceAuthorization(c *gin.Context) {
+	var input useCase.DecideDeviceAuthorizationInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		respondError(c, security.ErrInvalidToken)
+		return
+	}
+	input.UserID = userID
+
+	ctx, end := traceUseCase(c, "DecideDeviceAuthorizationUseCase")
+	output, err := h.DecideDeviceAuthorizationUseCase.Execute(ctx, input)
+	end(err)
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+// oauthError writes the error response of RFC 6749 section 5.2.
+func oauthError(c *gin.Context, status int, code string, description string) {
+	body := gin.H{"error": code}
+	if description != "" {
+		body["error_description"] = description
+	}
+
+	c.JSON(status, body)
+}
+
+// oauthServerError answers server_error without describing err, which is only logged.
+func oauthServerError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	oauthError(c, http.StatusInternalServerError, "server_error", "")
+}
@@ -0,0 +1,420 @@
+package http
+
+import (
+	"errors"
+	"jamlink-backend/internal/adapter/http/middleware"
+	"jamlink-backend/internal/modules/auth/domain/deviceauth"
+	"jamlink-backend/internal/modules/auth/domain/oauthclient"
+	"jamlink-backend/internal/modules/auth/domain/oidc"
+	"jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/auth/usecase"
+	"jamlink-backend/internal/shared/security"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// OIDCConfig holds the URLs of the OpenID provider: the issuer, i.e. the public URL of this API, and
+// the frontend pages where users log in and consent.
+type OIDCConfig struct {
+	Issuer     string
+	LoginURL   string
+	ConsentURL string
+}
+
+type OIDCHandler struct {
+	securitySvc             security.SecurityService
+	bff                     *middleware.BFFSession
+	consent                 middleware.ConsentChecker
+	keys                    *security.KeySet
+	config                  OIDCConfig
+	AuthorizeUseCase        *useCase.AuthorizeUseCase
+	GetOAuthClientUseCase   *useCase.GetOAuthClientUseCase
+	GetUserInfoUseCase      *useCase.GetUserInfoUseCase
+	ListOAuthGrantsUseCase  *useCase.ListOAuthGrantsUseCase
+	RevokeOAuthGrantUseCase *useCase.RevokeOAuthGrantUseCase
+}
+
+// NewOIDCHandler registers the OpenID Connect endpoints. The token endpoint is shared with the other
+// grants, see OAuthHandler. bff may be nil, in which case GET /oauth/authorize always sends the
+// browser to the frontend, which then calls POST /oauth/authorize with its access token. Grants are
+// managed on consented, and consent tells GET /oauth/authorize whether the user owes a re-accept.
+func NewOIDCHandler(router *gin.Engine, consented gin.IRoutes, consent middleware.ConsentChecker, securitySvc security.SecurityService, bff *middleware.BFFSession, keys *security.KeySet, config OIDCConfig, authorizeUC *useCase.AuthorizeUseCase, getOAuthClientUC *useCase.GetOAuthClientUseCase, getUserInfoUC *useCase.GetUserInfoUseCase, listOAuthGrantsUC *useCase.ListOAuthGrantsUseCase, revokeOAuthGrantUC *useCase.RevokeOAuthGrantUseCase) {
+	handler := &OIDCHandler{
+		securitySvc:             securitySvc,
+		bff:                     bff,
+		consent:                 consent,
+		keys:                    keys,
+		config:                  config,
+		AuthorizeUseCase:        authorizeUC,
+		GetOAuthClientUseCase:   getOAuthClientUC,
+		GetUserInfoUseCase:      getUserInfoUC,
+		ListOAuthGrantsUseCase:  listOAuthGrantsUC,
+		RevokeOAuthGrantUseCase: revokeOAuthGrantUC,
+	}
+
+	router.GET("/.well-known/openid-configuration", handler.Discovery)
+	router.GET("/.well-known/jwks.json", handler.JWKS)
+	router.GET("/oauth/authorize", handler.Authorize)
+	router.GET("/oauth/clients/:client_id", handler.GetClient)
+	router.GET("/oauth/userinfo", handler.UserInfo)
+	router.POST("/oauth/userinfo", handler.UserInfo)
+
+	consented.POST("/oauth/authorize", middleware.RejectImpersonation(), handler.Consent)
+	consented.GET("/oauth/grants", handler.ListGrants)
+	consented.DELETE("/oauth/grants/:client_id", middleware.RejectImpersonation(), handler.RevokeGrant)
+}
+
+// Discovery describe the OpenID provider
+// @Summary OpenID Connect discovery document
+// @Tags OpenID Connect
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /.well-known/openid-configuration [get]
+func (h *OIDCHandler) Discovery(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"issuer":                                h.config.Issuer,
+		"authorization_endpoint":                h.config.Issuer + "/oauth/authorize",
+		"token_endpoint":                        h.config.Issuer + "/oauth/token",
+		"userinfo_endpoint":                     h.config.Issuer + "/oauth/userinfo",
+		"jwks_uri":                              h.config.Issuer + "/.well-known/jwks.json",
+		"device_authorization_endpoint":         h.config.Issuer + "/oauth/device_authorization",
+		"response_types_supported":              []string{oidc.ResponseTypeCode},
+		"grant_types_supported":                 []string{oidc.GrantType, oauthclient.GrantType, deviceauth.GrantType},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"scopes_supported":                      oidc.SupportedScopes,
+		"claims_supported":                      []string{"sub", "email", "email_verified", "locale", "auth_time", "amr", "nonce"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post"},
+		"code_challenge_methods_supported":      []string{oidc.CodeChallengeMethod},
+	})
+}
+
+// JWKS publish the ID token signing keys
+// @Summary JSON Web Key Set
+// @Description Public keys of the ID tokens. The key replaced by the last rotation stays listed until the next one.
+// @Tags OpenID Connect
+// @Produce json
+// @Success 200 {object} security.JWKS
+// @Router /.well-known/jwks.json [get]
+func (h *OIDCHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
+
+// Authorize start the authorization code flow
+// @Summary OpenID Connect authorization endpoint
+// @Description Browser navigation from a client. An unknown client or a redirect_uri that is not registered answers 400; any other error is sent back to redirect_uri.
+// @Description Without a session cookie the browser is sent to the login page with a return_to parameter. When the user has not yet granted the requested scopes to the client, it is sent to the consent page with the parameters of the request, which then calls POST /oauth/authorize.
+// @Description Only the authorization code flow with PKCE (S256) is supported, and scope must include openid.
+// @Tags OpenID Connect
+// @Param response_type query string true "code"
+// @Param client_id query string true "Client identifier"
+// @Param redirect_uri query string true "Registered redirect URI"
+// @Param scope query string true "Space-delimited scopes, including openid"
+// @Param state query string false "Opaque value returned to the client"
+// @Param nonce query string false "Value copied into the ID token"
+// @Param code_challenge query string true "PKCE challenge"
+// @Param code_challenge_method query string true "S256"
+// @Success 302
+// @Failure 400 {object} map[string]string
+// @Router /oauth/authorize [get]
+func (h *OIDCHandler) Authorize(c *gin.Context) {
+	var input useCase.AuthorizeInput
+
+	if err := c.ShouldBindQuery(&input); err != nil {
+		oauthError(c, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	if _, err := h.AuthorizeUseCase.ResolveClient(c.Request.Context(), input.ClientID, input.RedirectURI); err != nil {
+		h.clientError(c, err)
+		return
+	}
+
+	userID, auth, ok := h.sessionUser(c)
+	if !ok {
+		returnTo := h.config.Issuer + c.Request.URL.RequestURI()
+		c.Redirect(http.StatusFound, withQuery(h.config.LoginURL, url.Values{"return_to": {returnTo}}))
+		return
+	}
+	input.UserID = userID
+	input.Auth = auth
+
+	// A user who owes a re-accept of the terms gets no code silently: the consent page is shown, and its
+	// POST /oauth/authorize answers consent_required.
+	required, err := h.consent.RequiresReaccept(c.Request.Context(), userID)
+	if err != nil {
+		c.Redirect(http.StatusFound, authorizationErrorRedirect(input, err))
+		return
+	}
+	if required {
+		c.Redirect(http.StatusFound, withQuery(h.config.ConsentURL, c.Request.URL.Query()))
+		return
+	}
+
+	ctx, end := traceUseCase(c, "AuthorizeUseCase")
+	output, err := h.AuthorizeUseCase.Execute(ctx, input)
+	end(err)
+	if errors.Is(err, oidc.ErrConsentRequired) {
+		c.Redirect(http.StatusFound, withQuery(h.config.ConsentURL, c.Request.URL.Query()))
+		return
+	}
+	if err != nil {
+		c.Redirect(http.StatusFound, authorizationErrorRedirect(input, err))
+		return
+	}
+
+	c.Redirect(http.StatusFound, output.RedirectTo)
+}
+
+// Consent approve or deny an authorization request
+// @Summary Answer the consent screen
+// @Description Called by the consent page with the parameters of the authorization request. The browser must then be sent to redirect_to, which carries either the code or the error for the client.
+// @Tags OpenID Connect
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body useCase.AuthorizeInput true "Parameters of the authorization request and decision"
+// @Success 200 {object} useCase.AuthorizeOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /oauth/authorize [post]
+func (h *OIDCHandler) Consent(c *gin.Context) {
+	var input useCase.AuthorizeInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+
+	if _, err := h.AuthorizeUseCase.ResolveClient(c.Request.Context(), input.ClientID, input.RedirectURI); err != nil {
+		h.clientError(c, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		respondError(c, security.ErrInvalidToken)
+		return
+	}
+	input.UserID = userID
+	if auth, ok := c.Get("auth_context"); ok {
+		input.Auth, _ = auth.(*security.AuthContext)
+	}
+
+	if !input.Approve {
+		c.JSON(http.StatusOK, useCase.AuthorizeOutput{RedirectTo: authorizationErrorRedirect(input, oidc.ErrAccessDenied)})
+		return
+	}
+
+	ctx, end := traceUseCase(c, "AuthorizeUseCase")
+	output, err := h.AuthorizeUseCase.Execute(ctx, input)
+	end(err)
+	if err != nil {
+		c.JSON(http.StatusOK, useCase.AuthorizeOutput{RedirectTo: authorizationErrorRedirect(input, err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+// GetClient describe a client for the consent screen
+// @Summary Get the public details of an OAuth client
+// @Tags OpenID Connect
+// @Produce json
+// @Param client_id path string true "Client identifier"
+// @Param redirect_uri query string true "Redirect URI of the authorization request"
+// @Success 200 {object} useCase.OAuthClientOutput
+// @Failure 400 {object} map[string]string
+// @Router /oauth/clients/{client_id} [get]
+func (h *OIDCHandler) GetClient(c *gin.Context) {
+	ctx, end := traceUseCase(c, "GetOAuthClientUseCase")
+	output, err := h.GetOAuthClientUseCase.Execute(ctx, c.Param("client_id"), c.Query("redirect_uri"))
+	end(err)
+	if err != nil {
+		h.clientError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+// UserInfo return the claims of the user
+// @Summary OpenID Connect userinfo endpoint
+// @Description Requires an access token issued by the authorization code grant. The claims depend on its scopes: email and email_verified for email, locale for profile.
+// @Tags OpenID Connect
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Router /oauth/userinfo [get]
+func (h *OIDCHandler) UserInfo(c *gin.Context) {
+	authHeader := c.GetHeader("Authorization")
+	claims, err := h.securitySvc.ValidateJWT(c.Request.Context(), strings.TrimPrefix(authHeader, "Bearer "))
+
+	if !strings.HasPrefix(authHeader, "Bearer ") || err != nil || claims["type"] != security.PartnerAccessTokenType {
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		oauthError(c, http.StatusUnauthorized, "invalid_token", "")
+		return
+	}
+
+	rawUserID, _ := claims["id"].(string)
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		oauthError(c, http.StatusUnauthorized, "invalid_token", "")
+		return
+	}
+	scope, _ := claims["scope"].(string)
+
+	ctx, end := traceUseCase(c, "GetUserInfoUseCase")
+	output, err := h.GetUserInfoUseCase.Execute(ctx, userID, strings.Fields(scope))
+	end(err)
+
+	switch {
+	case errors.Is(err, user.ErrUserNotFound):
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		oauthError(c, http.StatusUnauthorized, "invalid_token", "")
+		return
+	case err != nil:
+		oauthServerError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, output)
+}
+
+// ListGrants list the applications the user consented to
+// @Summary List authorized applications
+// @Tags OpenID Connect
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} useCase.OAuthGrantOutput
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Router /oauth/grants [get]
+func (h *OIDCHandler) ListGrants(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		respondError(c, security.ErrInvalidToken)
+		return
+	}
+
+	ctx, end := traceUseCase(c, "ListOAuthGrantsUseCase")
+	output, err := h.ListOAuthGrantsUseCase.Execute(ctx, userID)
+	end(err)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+// RevokeGrant revoke the consent given to an application
+// @Summary Revoke an authorized application
+// @Description The next authorization request of the client shows the consent screen again.
+// @Tags OpenID Connect
+// @Security BearerAuth
+// @Param client_id path string true "Client identifier"
+// @Success 204
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /oauth/grants/{client_id} [delete]
+func (h *OIDCHandler) RevokeGrant(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		respondError(c, security.ErrInvalidToken)
+		return
+	}
+
+	clientID, err := uuid.Parse(c.Param("client_id"))
+	if err != nil {
+		respondError(c, oidc.ErrGrantNotFound)
+		return
+	}
+
+	ctx, end := traceUseCase(c, "RevokeOAuthGrantUseCase")
+	err = h.RevokeOAuthGrantUseCase.Execute(ctx, userID, clientID)
+	end(err)
+
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// sessionUser returns the user of the session cookie when it may authorize clients: a verified user,
+// not impersonated.
+func (h *OIDCHandler) sessionUser(c *gin.Context) (uuid.UUID, *security.AuthContext, bool) {
+	if h.bff == nil {
+		return uuid.Nil, nil, false
+	}
+
+	claims := h.bff.Claims(c, h.securitySvc)
+	if claims == nil || claims["type"] != security.AccessTokenType || security.ActorFromClaims(claims) != nil {
+		return uuid.Nil, nil, false
+	}
+	if security.PrincipalFromClaims(claims).IsService() {
+		return uuid.Nil, nil, false
+	}
+	if isVerified, _ := claims["isVerified"].(bool); !isVerified {
+		return uuid.Nil, nil, false
+	}
+
+	rawUserID, _ := claims["id"].(string)
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		return uuid.Nil, nil, false
+	}
+
+	return userID, security.AuthContextFromClaims(claims), true
+}
+
+// clientError answers the errors of ResolveClient, which must not redirect to the unverified redirect URI.
+func (h *OIDCHandler) clientError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, oidc.ErrUnknownClient), errors.Is(err, oidc.ErrInvalidRedirectURI):
+		oauthError(c, http.StatusBadRequest, "invalid_request", err.Error())
+	default:
+		oauthServerError(c, err)
+	}
+}
+
+// authorizationErrorRedirect returns the redirect URI of the client with the error of RFC 6749 section
+// 4.1.2.1.
+func authorizationErrorRedirect(input useCase.AuthorizeInput, err error) string {
+	code := "server_error"
+	switch {
+	case errors.Is(err, oidc.ErrUnsupportedResponseType), errors.Is(err, oidc.ErrInvalidRequest),
+		errors.Is(err, oidc.ErrInvalidScope), errors.Is(err, oidc.ErrAccessDenied), errors.Is(err, oidc.ErrConsentRequired):
+		code = err.Error()
+	}
+
+	return useCase.AuthorizationRedirect(input.RedirectURI, url.Values{"error": {code}}, input.State)
+}
+
+func withQuery(base string, params url.Values) string {
+	u, err := url.Parse(base)
+	if err != nil {
+		return base
+	}
+
+	query := u.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
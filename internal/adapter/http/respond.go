@@ -0,0 +1,38 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"jamlink-backend/internal/adapter/http/middleware"
+	"jamlink-backend/internal/shared/apperror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// respondError fails the request with err. The response is written by middleware.Problems, from the
+// catalogue entry of err; err itself is only logged.
+func respondError(c *gin.Context, err error) {
+	middleware.AbortWithError(c, err)
+}
+
+// respondInvalidBody fails a request whose body could not be bound. The invalid fields, when known,
+// are listed in the "fields" param by their Go name.
+func respondInvalidBody(c *gin.Context, err error) {
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+
+	problem := apperror.ErrInvalidRequest
+	switch {
+	case errors.As(err, &validationErrs):
+		fields := make([]string, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			fields = append(fields, fieldErr.Field())
+		}
+		problem = problem.With("fields", fields)
+	case errors.As(err, &typeErr):
+		problem = problem.With("fields", []string{typeErr.Field})
+	}
+
+	respondError(c, errors.Join(problem, err))
+}
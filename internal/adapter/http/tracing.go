@@ -0,0 +1,26 @@
+package http
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var tracer = otel.Tracer("jamlink-backend/internal/adapter/http")
+
+// traceUseCase starts the span of a use case run by the request, under the request span. It returns
+// the context to run the use case with, so that its queries and calls are traced under its span, and
+// the function ending the span with the use case's error.
+func traceUseCase(c *gin.Context, useCase string) (context.Context, func(error)) {
+	ctx, span := tracer.Start(c.Request.Context(), useCase+".Execute")
+
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
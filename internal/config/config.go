@@ -0,0 +1,230 @@
+// Package config holds the settings of the API. They are loaded in layers, each overriding the
+// previous one: defaults, the YAML file, environment variables, then command-line flags. See Load.
+package config
+
+import (
+	"log/slog"
+	"time"
+
+	"jamlink-backend/internal/shared/security"
+)
+
+// Config is built once at startup and passed to the constructors that need it; no other package
+// reads the environment. Every setting has a YAML key (the yaml tags, dot-separated), which is also
+// its flag name, and an environment variable (the env tag).
+type Config struct {
+	Server       ServerConfig       `yaml:"server"`
+	Log          LogConfig          `yaml:"log"`
+	Database     DatabaseConfig     `yaml:"database"`
+	Security     SecurityConfig     `yaml:"security"`
+	Tokens       TokenConfig        `yaml:"tokens"`
+	Cookie       CookieConfig       `yaml:"cookie"`
+	Google       GoogleConfig       `yaml:"google"`
+	Email        EmailConfig        `yaml:"email"`
+	Frontend     FrontendConfig     `yaml:"frontend"`
+	OIDC         OIDCConfig         `yaml:"oidc"`
+	Registration RegistrationConfig `yaml:"registration"`
+	GeoIP        GeoIPConfig        `yaml:"geoip"`
+	Maintenance  MaintenanceConfig  `yaml:"maintenance"`
+	Metrics      MetricsConfig      `yaml:"metrics"`
+	Tracing      TracingConfig      `yaml:"tracing"`
+}
+
+type ServerConfig struct {
+	Addr              string        `yaml:"addr" env:"SERVER_ADDR"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"SERVER_READ_HEADER_TIMEOUT"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"SERVER_READ_TIMEOUT"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"SERVER_WRITE_TIMEOUT"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
+	// RequestTimeout is the deadline of the context of a request, which cancels its queries and calls
+	// once the response could no longer be written.
+	RequestTimeout time.Duration `yaml:"request_timeout" env:"SERVER_REQUEST_TIMEOUT"`
+	// ShutdownTimeout bounds how long in-flight requests and background jobs are drained on SIGTERM.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT"`
+	// BackgroundWorkers run the jobs requests hand off, such as sending emails, from a queue of
+	// BackgroundQueueSize jobs. Requests are answered 503 when the queue is full.
+	BackgroundWorkers   int `yaml:"background_workers" env:"SERVER_BACKGROUND_WORKERS"`
+	BackgroundQueueSize int `yaml:"background_queue_size" env:"SERVER_BACKGROUND_QUEUE_SIZE"`
+}
+
+type LogConfig struct {
+	// Level is "debug", "info", "warn" or "error". Queries are only logged at debug level.
+	Level string `yaml:"level" env:"LOG_LEVEL"`
+}
+
+// SlogLevel returns the parsed Level, which Load has validated.
+func (c LogConfig) SlogLevel() slog.Level {
+	var level slog.Level
+	_ = level.UnmarshalText([]byte(c.Level))
+	return level
+}
+
+type DatabaseConfig struct {
+	Host     string `yaml:"host" env:"DB_HOST"`
+	Port     int    `yaml:"port" env:"DB_PORT"`
+	User     string `yaml:"user" env:"DB_USER"`
+	Password Secret `yaml:"password" env:"DB_PASSWORD"`
+	Name     string `yaml:"name" env:"DB_NAME"`
+	SSLMode  string `yaml:"sslmode" env:"DB_SSLMODE"`
+	// MigrateOnStart applies the pending migrations when the server starts. Without it, the server
+	// refuses to start until "jamlink migrate up" has been run.
+	MigrateOnStart bool `yaml:"migrate_on_start" env:"DB_MIGRATE_ON_START"`
+}
+
+type SecurityConfig struct {
+	// JWTSecret signs the JWTs and keys the hashes of one-time codes and opaque tokens.
+	JWTSecret Secret `yaml:"jwt_secret" env:"JWT_SECRET"`
+	// TokenStrategy is "jwt" or "opaque", see security.TokenStrategy.
+	TokenStrategy string `yaml:"token_strategy" env:"TOKEN_STRATEGY"`
+}
+
+type TokenConfig struct {
+	AccessTTL            time.Duration `yaml:"access_ttl" env:"ACCESS_TOKEN_TTL"`
+	RefreshTTL           time.Duration `yaml:"refresh_ttl" env:"REFRESH_TOKEN_TTL"`
+	ResetPasswordTTL     time.Duration `yaml:"reset_password_ttl" env:"RESET_PASSWORD_TOKEN_TTL"`
+	ImpersonationTTL     time.Duration `yaml:"impersonation_ttl" env:"IMPERSONATION_TOKEN_TTL"`
+	ClientCredentialsTTL time.Duration `yaml:"client_credentials_ttl" env:"CLIENT_CREDENTIALS_TOKEN_TTL"`
+}
+
+type CookieConfig struct {
+	Secure bool   `yaml:"secure" env:"COOKIE_SECURE"`
+	Domain string `yaml:"domain" env:"COOKIE_DOMAIN"`
+	// SameSite is "strict", "lax" or "none".
+	SameSite   string `yaml:"samesite" env:"COOKIE_SAMESITE"`
+	HostPrefix bool   `yaml:"host_prefix" env:"COOKIE_HOST_PREFIX"`
+	// SessionKey enables BFF mode: 32 random bytes in base64.
+	SessionKey Secret `yaml:"session_key" env:"BFF_SESSION_KEY"`
+}
+
+type GoogleConfig struct {
+	ClientID string `yaml:"client_id" env:"GOOGLE_CLIENT_ID"`
+}
+
+type EmailConfig struct {
+	BrevoAPIKey Secret `yaml:"brevo_api_key" env:"BREVO_API_KEY"`
+	SenderName  string `yaml:"sender_name" env:"BREVOS_SENDER_NAME"`
+	SenderEmail string `yaml:"sender_email" env:"BREVO_SENDER_EMAIL"`
+}
+
+// FrontendConfig holds the pages linked from emails and OAuth redirects.
+type FrontendConfig struct {
+	VerifyURL          string `yaml:"verify_url" env:"FRONTEND_VERIFY_URL"`
+	ReportLoginURL     string `yaml:"report_login_url" env:"FRONTEND_REPORT_LOGIN_URL"`
+	InviteURL          string `yaml:"invite_url" env:"FRONTEND_INVITE_URL"`
+	GuardianConsentURL string `yaml:"guardian_consent_url" env:"FRONTEND_GUARDIAN_CONSENT_URL"`
+	DeviceURL          string `yaml:"device_url" env:"FRONTEND_DEVICE_URL"`
+	LoginURL           string `yaml:"login_url" env:"FRONTEND_LOGIN_URL"`
+	OAuthConsentURL    string `yaml:"oauth_consent_url" env:"FRONTEND_OAUTH_CONSENT_URL"`
+}
+
+// OIDCConfig enables the OpenID provider when SigningKeyFile is set.
+type OIDCConfig struct {
+	Issuer                 string `yaml:"issuer" env:"OIDC_ISSUER"`
+	SigningKeyFile         string `yaml:"signing_key_file" env:"OIDC_SIGNING_KEY_FILE"`
+	PreviousSigningKeyFile string `yaml:"previous_signing_key_file" env:"OIDC_PREVIOUS_SIGNING_KEY_FILE"`
+}
+
+type RegistrationConfig struct {
+	// Mode is "open", "invite-only" or "closed".
+	Mode     string `yaml:"mode" env:"REGISTRATION_MODE"`
+	MinorAge int    `yaml:"minor_age" env:"MINOR_AGE_THRESHOLD"`
+}
+
+type GeoIPConfig struct {
+	// DBPath is an optional CSV file of "network,country,city" lines used by login alerts.
+	DBPath string `yaml:"db_path" env:"GEOIP_DB_PATH"`
+}
+
+type MaintenanceConfig struct {
+	Interval                time.Duration `yaml:"interval" env:"MAINTENANCE_INTERVAL"`
+	TokenBatchSize          int           `yaml:"token_batch_size" env:"MAINTENANCE_TOKEN_BATCH_SIZE"`
+	UserBatchSize           int           `yaml:"user_batch_size" env:"MAINTENANCE_USER_BATCH_SIZE"`
+	UnverifiedRetentionDays int           `yaml:"unverified_retention_days" env:"UNVERIFIED_RETENTION_DAYS"`
+	UnverifiedWarningDays   int           `yaml:"unverified_warning_days" env:"UNVERIFIED_WARNING_DAYS"`
+}
+
+// MetricsConfig exposes GET /metrics, either on its own listener, which should only be reachable from
+// the internal network, or on the main one behind a bearer token. Metrics are off when neither is set.
+type MetricsConfig struct {
+	Addr  string `yaml:"addr" env:"METRICS_ADDR"`
+	Token Secret `yaml:"token" env:"METRICS_TOKEN"`
+}
+
+type TracingConfig struct {
+	// Exporter is "otlp", "stdout" (one JSON span per line, for local debugging) or "off". Incoming
+	// trace contexts are propagated even when off.
+	Exporter string `yaml:"exporter" env:"TRACING_EXPORTER"`
+	// OTLPEndpoint is the URL of the collector's OTLP/HTTP endpoint, such as http://localhost:4318.
+	// When empty, the OTEL_EXPORTER_OTLP_* variables of the OpenTelemetry SDK apply.
+	OTLPEndpoint string `yaml:"otlp_endpoint" env:"TRACING_OTLP_ENDPOINT"`
+	ServiceName  string `yaml:"service_name" env:"TRACING_SERVICE_NAME"`
+}
+
+// Default returns the settings used when nothing overrides them. Secrets and deployment-specific
+// URLs have no default.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Addr:                ":8080",
+			ReadHeaderTimeout:   5 * time.Second,
+			ReadTimeout:         10 * time.Second,
+			WriteTimeout:        30 * time.Second,
+			IdleTimeout:         2 * time.Minute,
+			RequestTimeout:      25 * time.Second,
+			ShutdownTimeout:     30 * time.Second,
+			BackgroundWorkers:   16,
+			BackgroundQueueSize: 512,
+		},
+		Log: LogConfig{Level: "info"},
+		Database: DatabaseConfig{
+			Host:           "localhost",
+			Port:           5432,
+			User:           "jamlink",
+			Name:           "jamlink",
+			SSLMode:        "disable",
+			MigrateOnStart: true,
+		},
+		Security: SecurityConfig{TokenStrategy: security.TokenStrategyJWT},
+		Tokens: TokenConfig{
+			AccessTTL:            15 * time.Minute,
+			RefreshTTL:           7 * 24 * time.Hour,
+			ResetPasswordTTL:     15 * time.Minute,
+			ImpersonationTTL:     15 * time.Minute,
+			ClientCredentialsTTL: time.Hour,
+		},
+		Cookie:       CookieConfig{SameSite: "strict"},
+		Email:        EmailConfig{SenderName: "Jamlink"},
+		Registration: RegistrationConfig{Mode: "open", MinorAge: 18},
+		Maintenance: MaintenanceConfig{
+			Interval:                time.Hour,
+			TokenBatchSize:          1000,
+			UserBatchSize:           100,
+			UnverifiedRetentionDays: 30,
+			UnverifiedWarningDays:   7,
+		},
+		Tracing: TracingConfig{Exporter: "off", ServiceName: "jamlink-api"},
+	}
+}
+
+// OIDCEnabled reports whether the OpenID provider is configured.
+func (c *Config) OIDCEnabled() bool {
+	return c.OIDC.SigningKeyFile != ""
+}
+
+// Secret is a setting that must never be printed: its String method redacts it. Use Value to read it.
+type Secret string
+
+func (s Secret) Value() string {
+	return string(s)
+}
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return "[redacted]"
+}
+
+func (s Secret) GoString() string {
+	return s.String()
+}
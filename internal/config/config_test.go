@@ -0,0 +1,185 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testJWTSecret = "0123456789abcdef0123456789abcdef"
+
+// isolateEnv blanks every setting's environment variable, which Load ignores, so the tests do not
+// depend on the environment they run in.
+func isolateEnv(t *testing.T) {
+	cfg := Default()
+	for _, field := range fields(&cfg) {
+		t.Setenv(field.env, "")
+	}
+	t.Setenv(FileEnv, "")
+}
+
+func writeFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "jamlink.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_LayersOverrideEachOther(t *testing.T) {
+	isolateEnv(t)
+	path := writeFile(t, `
+server:
+  addr: ":7000"
+database:
+  port: 6543
+tokens:
+  access_ttl: 5m
+frontend:
+  verify_url: https://jamlink.app/verify
+`)
+	t.Setenv("JWT_SECRET", testJWTSecret)
+	t.Setenv("SERVER_ADDR", ":8000")
+	t.Setenv("DB_HOST", "db")
+
+	cfg, err := Load([]string{"-config", path, "-server.addr=:9000"})
+
+	require.NoError(t, err)
+	assert.Equal(t, ":9000", cfg.Server.Addr)
+	assert.Equal(t, "db", cfg.Database.Host)
+	assert.Equal(t, 6543, cfg.Database.Port)
+	assert.Equal(t, 5*time.Minute, cfg.Tokens.AccessTTL)
+	assert.Equal(t, 7*24*time.Hour, cfg.Tokens.RefreshTTL)
+	assert.Equal(t, "https://jamlink.app/verify", cfg.Frontend.VerifyURL)
+}
+
+func TestLoad_FileFromEnv(t *testing.T) {
+	isolateEnv(t)
+	t.Setenv(FileEnv, writeFile(t, "registration:\n  mode: closed\n"))
+	t.Setenv("JWT_SECRET", testJWTSecret)
+	t.Setenv("FRONTEND_VERIFY_URL", "https://jamlink.app/verify")
+
+	cfg, err := Load(nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "closed", cfg.Registration.Mode)
+}
+
+func TestLoad_RejectsUnknownFileKeys(t *testing.T) {
+	isolateEnv(t)
+	path := writeFile(t, "server:\n  adress: \":7000\"\n")
+
+	_, err := Load([]string{"-config", path})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "adress")
+}
+
+func TestLoad_ReportsEveryProblem(t *testing.T) {
+	isolateEnv(t)
+	t.Setenv("JWT_SECRET", "short")
+	t.Setenv("ACCESS_TOKEN_TTL", "soon")
+	t.Setenv("COOKIE_SAMESITE", "none")
+
+	_, err := Load([]string{"-database.port=0"})
+
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{
+		`ACCESS_TOKEN_TTL: invalid duration "soon"`,
+		"database.port must be between 1 and 65535",
+		"security.jwt_secret (JWT_SECRET) must be at least 32 characters",
+		"cookie.samesite none requires cookie.secure",
+		"frontend.verify_url is required",
+	}, validationErr.Problems)
+}
+
+func TestConfig_StringRedactsSecrets(t *testing.T) {
+	isolateEnv(t)
+	t.Setenv("JWT_SECRET", testJWTSecret)
+	t.Setenv("DB_PASSWORD", "hunter2")
+	t.Setenv("FRONTEND_VERIFY_URL", "https://jamlink.app/verify")
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+
+	printed := cfg.String()
+	assert.NotContains(t, printed, testJWTSecret)
+	assert.NotContains(t, printed, "hunter2")
+	assert.Contains(t, printed, "security.jwt_secret = [redacted]")
+	assert.Contains(t, printed, "server.addr = :8080")
+	assert.Equal(t, "hunter2", cfg.Database.Password.Value())
+	assert.NotContains(t, fmt.Sprintf("%#v", cfg.Database), "hunter2")
+}
+
+func TestConfig_SettingsRedactSecrets(t *testing.T) {
+	isolateEnv(t)
+	t.Setenv("JWT_SECRET", testJWTSecret)
+	t.Setenv("FRONTEND_VERIFY_URL", "https://jamlink.app/verify")
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+
+	settings := map[string]Setting{}
+	for _, setting := range cfg.Settings() {
+		settings[setting.Key] = setting
+	}
+	assert.Equal(t, Setting{Key: "security.jwt_secret", Env: "JWT_SECRET", Value: "[redacted]"}, settings["security.jwt_secret"])
+	assert.Equal(t, Setting{Key: "frontend.verify_url", Env: "FRONTEND_VERIFY_URL", Value: "https://jamlink.app/verify"}, settings["frontend.verify_url"])
+}
+
+func TestLoad_RejectsMetricsOnTheServerAddr(t *testing.T) {
+	isolateEnv(t)
+	t.Setenv("JWT_SECRET", testJWTSecret)
+	t.Setenv("FRONTEND_VERIFY_URL", "https://jamlink.app/verify")
+	t.Setenv("METRICS_ADDR", ":8080")
+
+	_, err := Load(nil)
+
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{"metrics.addr must differ from server.addr"}, validationErr.Problems)
+}
+
+func TestLoad_RejectsUnknownTracingExporter(t *testing.T) {
+	isolateEnv(t)
+	t.Setenv("JWT_SECRET", testJWTSecret)
+	t.Setenv("FRONTEND_VERIFY_URL", "https://jamlink.app/verify")
+	t.Setenv("TRACING_EXPORTER", "jaeger")
+
+	_, err := Load(nil)
+
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{"tracing.exporter must be otlp, stdout or off"}, validationErr.Problems)
+}
+
+func TestLoad_RejectsRequestTimeoutBeyondWriteTimeout(t *testing.T) {
+	isolateEnv(t)
+	t.Setenv("JWT_SECRET", testJWTSecret)
+	t.Setenv("FRONTEND_VERIFY_URL", "https://jamlink.app/verify")
+	t.Setenv("SERVER_REQUEST_TIMEOUT", "30s")
+
+	_, err := Load(nil)
+
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{"server.request_timeout must be shorter than server.write_timeout"}, validationErr.Problems)
+}
+
+func TestLoad_RejectsEmptyBackgroundQueue(t *testing.T) {
+	isolateEnv(t)
+	t.Setenv("JWT_SECRET", testJWTSecret)
+	t.Setenv("FRONTEND_VERIFY_URL", "https://jamlink.app/verify")
+	t.Setenv("SERVER_BACKGROUND_QUEUE_SIZE", "0")
+
+	_, err := Load(nil)
+
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{"server.background_workers and server.background_queue_size must be positive"}, validationErr.Problems)
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the YAML file to load when the -config flag is not given.
+const FileEnv = "CONFIG_FILE"
+
+// ErrHelp is returned by Load when -h or -help is given, after printing the usage.
+var ErrHelp = flag.ErrHelp
+
+// Load builds the configuration from the defaults, the YAML file named by -config or CONFIG_FILE if
+// any, the environment, then args, which accepts one flag per setting named after its YAML key (for
+// example -server.addr=:9090). The result is validated: the returned *ValidationError lists every
+// problem at once, so a misconfigured deployment can be fixed in one go.
+func Load(args []string) (*Config, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet("jamlink", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	path := fs.String("config", os.Getenv(FileEnv), "YAML configuration file")
+
+	overrides := map[string]string{}
+	var order []string
+	for _, field := range fields(&cfg) {
+		name := field.path
+		fs.Func(name, "overrides the "+field.env+" environment variable", func(value string) error {
+			if _, seen := overrides[name]; !seen {
+				order = append(order, name)
+			}
+			overrides[name] = value
+			return nil
+		})
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.SetOutput(os.Stderr)
+			fs.PrintDefaults()
+			return nil, ErrHelp
+		}
+		return nil, err
+	}
+
+	if *path != "" {
+		raw, err := os.ReadFile(*path)
+		if err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(raw))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("config: %s: %w", *path, err)
+		}
+	}
+
+	var problems []string
+	for _, field := range fields(&cfg) {
+		if value, ok := os.LookupEnv(field.env); ok && value != "" {
+			if err := field.set(value); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", field.env, err))
+			}
+		}
+	}
+
+	byPath := map[string]field{}
+	for _, field := range fields(&cfg) {
+		byPath[field.path] = field
+	}
+	for _, name := range order {
+		if err := byPath[name].set(overrides[name]); err != nil {
+			problems = append(problems, fmt.Sprintf("-%s: %v", name, err))
+		}
+	}
+
+	problems = append(problems, cfg.problems()...)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	return &cfg, nil
+}
+
+// field is a setting of Config, found by walking its structs.
+type field struct {
+	path  string
+	env   string
+	value reflect.Value
+}
+
+func fields(cfg *Config) []field {
+	var result []field
+	walk(reflect.ValueOf(cfg).Elem(), "", &result)
+	return result
+}
+
+func walk(v reflect.Value, prefix string, result *[]field) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		path := prefix + structField.Tag.Get("yaml")
+
+		if structField.Type.Kind() == reflect.Struct && structField.Type != reflect.TypeOf(time.Duration(0)) {
+			walk(v.Field(i), path+".", result)
+			continue
+		}
+
+		*result = append(*result, field{path: path, env: structField.Tag.Get("env"), value: v.Field(i)})
+	}
+}
+
+func (f field) set(raw string) error {
+	switch {
+	case f.value.Type() == reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		f.value.SetInt(int64(d))
+	case f.value.Kind() == reflect.String:
+		f.value.SetString(raw)
+	case f.value.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		f.value.SetBool(b)
+	case f.value.Kind() == reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		f.value.SetInt(int64(n))
+	default:
+		return fmt.Errorf("unsupported setting type %s", f.value.Type())
+	}
+	return nil
+}
+
+// Setting is the effective value of a setting, as printed: secrets are redacted.
+type Setting struct {
+	Key   string `json:"key"`
+	Env   string `json:"env"`
+	Value string `json:"value"`
+}
+
+// Settings lists every setting in declaration order, with secrets redacted.
+func (c *Config) Settings() []Setting {
+	var settings []Setting
+	for _, field := range fields(c) {
+		settings = append(settings, Setting{Key: field.path, Env: field.env, Value: fmt.Sprint(field.value.Interface())})
+	}
+	return settings
+}
+
+// String prints every setting as "key = value", with secrets redacted, for startup logs.
+func (c *Config) String() string {
+	var b strings.Builder
+	for _, setting := range c.Settings() {
+		fmt.Fprintf(&b, "%s = %s\n", setting.Key, setting.Value)
+	}
+	return b.String()
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"jamlink-backend/internal/modules/invitation/domain/invitation"
+	"jamlink-backend/internal/shared/security"
+)
+
+// minJWTSecretLength is the size of the SHA-256 output, the minimum key size for HS256 (RFC 7518).
+const minJWTSecretLength = 32
+
+// ValidationError lists every invalid setting found by Load.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+func (c *Config) problems() []string {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.Server.Addr == "" {
+		add("server.addr is required")
+	}
+	for _, timeout := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"server.read_header_timeout", c.Server.ReadHeaderTimeout},
+		{"server.read_timeout", c.Server.ReadTimeout},
+		{"server.write_timeout", c.Server.WriteTimeout},
+		{"server.idle_timeout", c.Server.IdleTimeout},
+		{"server.request_timeout", c.Server.RequestTimeout},
+		{"server.shutdown_timeout", c.Server.ShutdownTimeout},
+	} {
+		if timeout.value <= 0 {
+			add("%s must be positive", timeout.name)
+		}
+	}
+	if c.Server.RequestTimeout >= c.Server.WriteTimeout {
+		add("server.request_timeout must be shorter than server.write_timeout")
+	}
+	if c.Server.BackgroundWorkers <= 0 || c.Server.BackgroundQueueSize <= 0 {
+		add("server.background_workers and server.background_queue_size must be positive")
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		add("log.level must be debug, info, warn or error")
+	}
+
+	if c.Database.Host == "" || c.Database.User == "" || c.Database.Name == "" {
+		add("database.host, database.user and database.name are required")
+	}
+	if c.Database.Port <= 0 || c.Database.Port > 65535 {
+		add("database.port must be between 1 and 65535")
+	}
+	switch c.Database.SSLMode {
+	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+	default:
+		add("database.sslmode must be disable, allow, prefer, require, verify-ca or verify-full")
+	}
+
+	if len(c.Security.JWTSecret) < minJWTSecretLength {
+		add("security.jwt_secret (JWT_SECRET) must be at least %d characters", minJWTSecretLength)
+	}
+	if c.Security.TokenStrategy != security.TokenStrategyJWT && c.Security.TokenStrategy != security.TokenStrategyOpaque {
+		add("security.token_strategy must be %s or %s", security.TokenStrategyJWT, security.TokenStrategyOpaque)
+	}
+
+	for _, ttl := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"tokens.access_ttl", c.Tokens.AccessTTL},
+		{"tokens.refresh_ttl", c.Tokens.RefreshTTL},
+		{"tokens.reset_password_ttl", c.Tokens.ResetPasswordTTL},
+		{"tokens.impersonation_ttl", c.Tokens.ImpersonationTTL},
+		{"tokens.client_credentials_ttl", c.Tokens.ClientCredentialsTTL},
+	} {
+		if ttl.value <= 0 {
+			add("%s must be positive", ttl.name)
+		}
+	}
+	if c.Tokens.RefreshTTL <= c.Tokens.AccessTTL {
+		add("tokens.refresh_ttl must be longer than tokens.access_ttl")
+	}
+
+	switch c.Cookie.SameSite {
+	case "strict", "lax":
+	case "none":
+		if !c.Cookie.Secure {
+			add("cookie.samesite none requires cookie.secure")
+		}
+	default:
+		add("cookie.samesite must be strict, lax or none")
+	}
+	if c.Cookie.HostPrefix && !c.Cookie.Secure {
+		add("cookie.host_prefix requires cookie.secure")
+	}
+	if c.Cookie.HostPrefix && c.Cookie.Domain != "" {
+		add("cookie.host_prefix cannot be combined with cookie.domain")
+	}
+	if c.Cookie.SessionKey != "" {
+		if key, err := base64.StdEncoding.DecodeString(c.Cookie.SessionKey.Value()); err != nil || len(key) != 32 {
+			add("cookie.session_key (BFF_SESSION_KEY) must be 32 bytes encoded in base64")
+		}
+	}
+
+	if c.Frontend.VerifyURL == "" {
+		add("frontend.verify_url is required")
+	}
+	for _, setting := range []struct{ name, value string }{
+		{"frontend.verify_url", c.Frontend.VerifyURL},
+		{"frontend.report_login_url", c.Frontend.ReportLoginURL},
+		{"frontend.invite_url", c.Frontend.InviteURL},
+		{"frontend.guardian_consent_url", c.Frontend.GuardianConsentURL},
+		{"frontend.device_url", c.Frontend.DeviceURL},
+		{"frontend.login_url", c.Frontend.LoginURL},
+		{"frontend.oauth_consent_url", c.Frontend.OAuthConsentURL},
+		{"oidc.issuer", c.OIDC.Issuer},
+	} {
+		if setting.value != "" && !absoluteURL(setting.value) {
+			add("%s must be an absolute URL", setting.name)
+		}
+	}
+
+	if c.OIDCEnabled() {
+		if c.OIDC.Issuer == "" {
+			add("oidc.issuer is required when oidc.signing_key_file is set")
+		}
+		if c.Frontend.LoginURL == "" || c.Frontend.OAuthConsentURL == "" {
+			add("frontend.login_url and frontend.oauth_consent_url are required when oidc.signing_key_file is set")
+		}
+	}
+
+	if _, err := invitation.ParseRegistrationMode(c.Registration.Mode); err != nil {
+		add("registration.mode must be open, invite-only or closed")
+	}
+	if c.Registration.MinorAge <= 0 {
+		add("registration.minor_age must be positive")
+	}
+
+	if c.Maintenance.Interval <= 0 {
+		add("maintenance.interval must be positive")
+	}
+	if c.Maintenance.TokenBatchSize <= 0 || c.Maintenance.UserBatchSize <= 0 {
+		add("maintenance.token_batch_size and maintenance.user_batch_size must be positive")
+	}
+	if c.Maintenance.UnverifiedWarningDays <= 0 || c.Maintenance.UnverifiedRetentionDays <= c.Maintenance.UnverifiedWarningDays {
+		add("maintenance.unverified_warning_days must be positive and less than maintenance.unverified_retention_days")
+	}
+
+	switch c.Tracing.Exporter {
+	case "otlp", "stdout", "off":
+	default:
+		add("tracing.exporter must be otlp, stdout or off")
+	}
+	if c.Tracing.OTLPEndpoint != "" && !absoluteURL(c.Tracing.OTLPEndpoint) {
+		add("tracing.otlp_endpoint must be an absolute URL")
+	}
+	if c.Tracing.ServiceName == "" {
+		add("tracing.service_name is required")
+	}
+
+	if c.Metrics.Addr != "" && c.Metrics.Addr == c.Server.Addr {
+		add("metrics.addr must differ from server.addr")
+	}
+
+	return problems
+}
+
+func absoluteURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
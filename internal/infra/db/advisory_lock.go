@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// TryAdvisoryLock takes a session-level Postgres advisory lock on a dedicated connection so that only
+// one replica runs a given job at a time. When ok is true, the caller must call unlock to release both
+// the lock and the connection.
+func TryAdvisoryLock(ctx context.Context, database *gorm.DB, key int64) (unlock func(), ok bool, err error) {
+	sqlDB, err := database.DB()
+	if err != nil {
+		return nil, false, err
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&ok); err != nil || !ok {
+		_ = conn.Close()
+		return nil, false, err
+	}
+
+	unlock = func() {
+		_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key)
+		_ = conn.Close()
+	}
+
+	return unlock, true, nil
+}
@@ -1,30 +1,57 @@
 package db
 
 import (
+	"context"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"jamlink-backend/internal/config"
 )
 
-func ConnectDB() *gorm.DB {
+func ConnectDB(cfg config.DatabaseConfig) (*gorm.DB, error) {
 	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
-		os.Getenv("DB_LOCALHOST"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"),
-		os.Getenv("DB_PORT"),
-		os.Getenv("DB_SSLMODE"),
+		"host=%s user=%s password=%s dbname=%s port=%d sslmode=%s",
+		cfg.Host,
+		cfg.User,
+		cfg.Password.Value(),
+		cfg.Name,
+		cfg.Port,
+		cfg.SSLMode,
 	)
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: queryLogger{}})
 	if err != nil {
-		log.Fatal("❌ Failed to connect to database:", err)
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	if err := db.Use(queryMetrics{}); err != nil {
+		return nil, fmt.Errorf("failed to register the query metrics: %w", err)
+	}
+	if err := db.Use(queryTracing{}); err != nil {
+		return nil, fmt.Errorf("failed to register the query tracing: %w", err)
 	}
 
-	fmt.Println("✅ Connected to database")
-	return db
+	slog.Info("connected to database", "host", cfg.Host, "name", cfg.Name)
+	return db, nil
+}
+
+// Ping returns a readiness check of the connection pool.
+func Ping(database *gorm.DB) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		sqlDB, err := database.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+// Close closes the connection pool once the server and the workers are done with it.
+func Close(database *gorm.DB) error {
+	sqlDB, err := database.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
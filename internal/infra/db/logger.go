@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"jamlink-backend/internal/shared/logging"
+)
+
+// slowQueryThreshold is the duration above which a query is logged as a warning.
+const slowQueryThreshold = 200 * time.Millisecond
+
+// queryLogger sends gorm's logs to the logger of the query's context, so that the queries of a request
+// are logged with its request ID. Failed and slow queries are logged; the others only at debug level.
+type queryLogger struct{}
+
+func (l queryLogger) LogMode(gormlogger.LogLevel) gormlogger.Interface {
+	return l
+}
+
+func (queryLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	logging.FromContext(ctx).InfoContext(ctx, fmt.Sprintf(msg, data...))
+}
+
+func (queryLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	logging.FromContext(ctx).WarnContext(ctx, fmt.Sprintf(msg, data...))
+}
+
+func (queryLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	logging.FromContext(ctx).ErrorContext(ctx, fmt.Sprintf(msg, data...))
+}
+
+func (queryLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	logger := logging.FromContext(ctx)
+	elapsed := time.Since(begin)
+
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		logger.ErrorContext(ctx, "query failed", "sql", sql, "rows", rows, "duration_ms", elapsed.Milliseconds(), "error", err)
+	case elapsed > slowQueryThreshold:
+		sql, rows := fc()
+		logger.WarnContext(ctx, "slow query", "sql", sql, "rows", rows, "duration_ms", elapsed.Milliseconds())
+	case logger.Enabled(ctx, slog.LevelDebug):
+		sql, rows := fc()
+		logger.DebugContext(ctx, "query", "sql", sql, "rows", rows, "duration_ms", elapsed.Milliseconds())
+	}
+}
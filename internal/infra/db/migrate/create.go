@@ -0,0 +1,48 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// versionLayout makes versions from the creation time, so that migrations written on different
+// branches rarely collide and sort in the order they were written.
+const versionLayout = "20060102150405"
+
+var migrationName = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// Create writes an empty pair of scripts for a new migration of source and returns their paths. Paths
+// are relative to the repository root, where the command must be run.
+func Create(source Source, name string, now time.Time) ([]string, error) {
+	if !migrationName.MatchString(name) {
+		return nil, fmt.Errorf("invalid migration name %q: use lowercase letters, digits and underscores", name)
+	}
+	if err := os.MkdirAll(source.Dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	version := now.UTC().Format(versionLayout)
+	var paths []string
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(source.Dir, fmt.Sprintf("%s_%s.%s.sql", version, name, direction))
+		content := fmt.Sprintf("-- %s: %s (%s)\n", direction, name, source.Module)
+
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return paths, err
+		}
+		_, err = file.WriteString(content)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,351 @@
+// Package migrate applies versioned SQL migrations. Each module contributes a Source of
+// <version>_<name>.up.sql and <version>_<name>.down.sql files embedded in the binary. Migrations run in
+// version order across modules, each in its own transaction, and are recorded in the schema_migrations
+// table with the checksum of their up script, so that a script edited after being applied is detected.
+// A Postgres advisory lock keeps replicas starting together from applying the same migration twice.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lockKey identifies the migrations among Postgres advisory locks.
+const lockKey int64 = 0x6a616d6d6967
+
+// noTransaction, as the first line of an up or down script, runs it outside a transaction, for
+// statements such as CREATE INDEX CONCURRENTLY. A failure then leaves the migration half applied.
+const noTransaction = "-- migrate:no-transaction"
+
+var (
+	ErrModified     = errors.New("migration modified after being applied")
+	ErrUnknown      = errors.New("applied migration unknown to this binary")
+	ErrNoneToRevert = errors.New("no migration to revert")
+)
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Source holds the migrations of a module.
+type Source struct {
+	Module string
+	// Dir is the directory of the files in the repository, where Create writes new migrations.
+	Dir string
+	// Files holds the migration files at its root.
+	Files fs.FS
+}
+
+type Migration struct {
+	Version  int64
+	Name     string
+	Module   string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%d_%s (%s)", m.Version, m.Name, m.Module)
+}
+
+// Load reads the migrations of sources, sorted by version. Versions must be unique across modules and
+// every migration needs both scripts.
+func Load(sources ...Source) ([]Migration, error) {
+	byVersion := map[int64]*Migration{}
+
+	for _, source := range sources {
+		entries, err := fs.ReadDir(source.Files, ".")
+		if err != nil {
+			return nil, fmt.Errorf("%s migrations: %w", source.Module, err)
+		}
+
+		for _, entry := range entries {
+			match := fileName.FindStringSubmatch(entry.Name())
+			if entry.IsDir() || match == nil {
+				return nil, fmt.Errorf("%s migrations: unexpected file %s, want <version>_<name>.up.sql or .down.sql", source.Module, entry.Name())
+			}
+			version, err := strconv.ParseInt(match[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s migrations: %s: %w", source.Module, entry.Name(), err)
+			}
+			content, err := fs.ReadFile(source.Files, entry.Name())
+			if err != nil {
+				return nil, fmt.Errorf("%s migrations: %w", source.Module, err)
+			}
+
+			migration, ok := byVersion[version]
+			if !ok {
+				migration = &Migration{Version: version, Name: match[2], Module: source.Module}
+				byVersion[version] = migration
+			}
+			if migration.Name != match[2] || migration.Module != source.Module {
+				return nil, fmt.Errorf("migration version %d is used by both %s and %s_%s (%s)", version, migration, match[1], match[2], source.Module)
+			}
+
+			if match[3] == "up" {
+				migration.Up = string(content)
+				sum := sha256.Sum256(content)
+				migration.Checksum = hex.EncodeToString(sum[:])
+			} else {
+				migration.Down = string(content)
+			}
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %s needs both an up and a down script", migration)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// State of a migration: "applied", "pending", "modified" when its up script changed since it was
+// applied, or "unknown" when it was applied by another version of the binary.
+type State struct {
+	Version   int64
+	Name      string
+	Module    string
+	Status    string
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func New(db *sql.DB, sources ...Source) (*Migrator, error) {
+	migrations, err := Load(sources...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// applied is a row of schema_migrations.
+type applied struct {
+	version   int64
+	module    string
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// Up applies the pending migrations in version order and returns them. It applies nothing when an
+// applied migration was modified.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+
+	err := m.locked(ctx, func(conn *sql.Conn, history map[int64]applied) error {
+		for _, migration := range m.migrations {
+			if row, ok := history[migration.Version]; ok && row.checksum != migration.Checksum {
+				return fmt.Errorf("%w: %s", ErrModified, migration)
+			}
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := history[migration.Version]; ok {
+				continue
+			}
+
+			err := run(ctx, conn, migration.Up, func(exec execer) error {
+				_, err := exec.ExecContext(ctx,
+					"INSERT INTO schema_migrations (version, module, name, checksum, applied_at) VALUES ($1, $2, $3, $4, $5)",
+					migration.Version, migration.Module, migration.Name, migration.Checksum, time.Now())
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %s: %w", migration, err)
+			}
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Down reverts the last steps applied migrations, most recent first, and returns them.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+
+	byVersion := map[int64]Migration{}
+	for _, migration := range m.migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	err := m.locked(ctx, func(conn *sql.Conn, history map[int64]applied) error {
+		versions := make([]int64, 0, len(history))
+		for version := range history {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+		if len(versions) == 0 {
+			return ErrNoneToRevert
+		}
+
+		for _, version := range versions[:min(steps, len(versions))] {
+			migration, ok := byVersion[version]
+			if !ok {
+				row := history[version]
+				return fmt.Errorf("%w: %d_%s (%s)", ErrUnknown, row.version, row.name, row.module)
+			}
+
+			err := run(ctx, conn, migration.Down, func(exec execer) error {
+				_, err := exec.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %s: %w", migration, err)
+			}
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Status lists the migrations of the binary and those applied by other versions of it, by version.
+func (m *Migrator) Status(ctx context.Context) ([]State, error) {
+	var states []State
+
+	err := m.locked(ctx, func(_ *sql.Conn, history map[int64]applied) error {
+		for _, migration := range m.migrations {
+			state := State{Version: migration.Version, Name: migration.Name, Module: migration.Module, Status: "pending"}
+			if row, ok := history[migration.Version]; ok {
+				appliedAt := row.appliedAt
+				state.AppliedAt = &appliedAt
+				state.Status = "applied"
+				if row.checksum != migration.Checksum {
+					state.Status = "modified"
+				}
+				delete(history, migration.Version)
+			}
+			states = append(states, state)
+		}
+
+		for _, row := range history {
+			appliedAt := row.appliedAt
+			states = append(states, State{Version: row.version, Name: row.name, Module: row.module, Status: "unknown", AppliedAt: &appliedAt})
+		}
+		sort.Slice(states, func(i, j int) bool { return states[i].Version < states[j].Version })
+
+		return nil
+	})
+
+	return states, err
+}
+
+// Pending counts the migrations that Up would apply.
+func Pending(states []State) int {
+	pending := 0
+	for _, state := range states {
+		if state.Status == "pending" {
+			pending++
+		}
+	}
+	return pending
+}
+
+// locked runs fn on a dedicated connection holding the migration lock, with the applied migrations.
+// The lock is session-level, so every statement must go through conn.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn, history map[int64]applied) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		return fmt.Errorf("failed to take the migration lock: %w", err)
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey)
+	}()
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version bigint PRIMARY KEY,
+	module varchar(64) NOT NULL,
+	name varchar(255) NOT NULL,
+	checksum char(64) NOT NULL,
+	applied_at timestamptz NOT NULL
+)`)
+	if err != nil {
+		return fmt.Errorf("failed to create the schema_migrations table: %w", err)
+	}
+
+	history, err := readHistory(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	return fn(conn, history)
+}
+
+func readHistory(ctx context.Context, conn *sql.Conn) (map[int64]applied, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, module, name, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := map[int64]applied{}
+	for rows.Next() {
+		var row applied
+		if err := rows.Scan(&row.version, &row.module, &row.name, &row.checksum, &row.appliedAt); err != nil {
+			return nil, err
+		}
+		history[row.version] = row
+	}
+
+	return history, rows.Err()
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// run executes script then record, which updates schema_migrations, in one transaction unless the
+// script opts out of it.
+func run(ctx context.Context, conn *sql.Conn, script string, record func(exec execer) error) error {
+	if strings.HasPrefix(strings.TrimSpace(script), noTransaction) {
+		if _, err := conn.ExecContext(ctx, script); err != nil {
+			return err
+		}
+		return record(conn)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Without arguments, the script is sent with the simple protocol, which allows several statements.
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if err := record(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,86 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func file(content string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(content)}
+}
+
+func TestLoad_OrdersMigrationsAcrossModules(t *testing.T) {
+	auth := Source{Module: "auth", Files: fstest.MapFS{
+		"3_add_nickname.up.sql":   file("ALTER TABLE users ADD COLUMN nickname text;"),
+		"3_add_nickname.down.sql": file("ALTER TABLE users DROP COLUMN nickname;"),
+		"1_create_users.up.sql":   file("CREATE TABLE users ();"),
+		"1_create_users.down.sql": file("DROP TABLE users;"),
+	}}
+	consent := Source{Module: "consent", Files: fstest.MapFS{
+		"2_create_documents.up.sql":   file("CREATE TABLE documents ();"),
+		"2_create_documents.down.sql": file("DROP TABLE documents;"),
+	}}
+
+	migrations, err := Load(auth, consent)
+
+	require.NoError(t, err)
+	require.Len(t, migrations, 3)
+	assert.Equal(t, []string{"1_create_users (auth)", "2_create_documents (consent)", "3_add_nickname (auth)"},
+		[]string{migrations[0].String(), migrations[1].String(), migrations[2].String()})
+	assert.Equal(t, "ALTER TABLE users DROP COLUMN nickname;", migrations[2].Down)
+	assert.Len(t, migrations[0].Checksum, 64)
+}
+
+func TestLoad_ChecksumFollowsTheUpScript(t *testing.T) {
+	load := func(up, down string) string {
+		migrations, err := Load(Source{Module: "auth", Files: fstest.MapFS{
+			"1_create_users.up.sql":   file(up),
+			"1_create_users.down.sql": file(down),
+		}})
+		require.NoError(t, err)
+		return migrations[0].Checksum
+	}
+
+	original := load("CREATE TABLE users ();", "DROP TABLE users;")
+
+	assert.Equal(t, original, load("CREATE TABLE users ();", "DROP TABLE IF EXISTS users;"))
+	assert.NotEqual(t, original, load("CREATE TABLE users (id uuid);", "DROP TABLE users;"))
+}
+
+func TestLoad_RejectsInvalidSources(t *testing.T) {
+	tests := map[string][]Source{
+		"missing down script": {{Module: "auth", Files: fstest.MapFS{
+			"1_create_users.up.sql": file("CREATE TABLE users ();"),
+		}}},
+		"unexpected file": {{Module: "auth", Files: fstest.MapFS{
+			"create_users.sql": file("CREATE TABLE users ();"),
+		}}},
+		"version used twice": {
+			{Module: "auth", Files: fstest.MapFS{
+				"1_create_users.up.sql":   file("CREATE TABLE users ();"),
+				"1_create_users.down.sql": file("DROP TABLE users;"),
+			}},
+			{Module: "consent", Files: fstest.MapFS{
+				"1_create_documents.up.sql":   file("CREATE TABLE documents ();"),
+				"1_create_documents.down.sql": file("DROP TABLE documents;"),
+			}},
+		},
+	}
+
+	for name, sources := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Load(sources...)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPending(t *testing.T) {
+	states := []State{{Status: "applied"}, {Status: "pending"}, {Status: "unknown"}, {Status: "pending"}}
+
+	assert.Equal(t, 2, Pending(states))
+}
@@ -2,15 +2,27 @@ package db
 
 import (
 	"gorm.io/gorm"
+	"jamlink-backend/internal/infra/db/migrate"
 	userinfra "jamlink-backend/internal/modules/auth/infra"
-	"log"
+	consentinfra "jamlink-backend/internal/modules/consent/infra"
+	invitationinfra "jamlink-backend/internal/modules/invitation/infra"
 )
 
-func MigrateDB(db *gorm.DB) {
-	log.Println("🚀 Running global database migrations...")
+// MigrationSources lists the migrations of every module. Their versions are ordered across modules.
+func MigrationSources() []migrate.Source {
+	return []migrate.Source{
+		userinfra.Migrations(),
+		invitationinfra.Migrations(),
+		consentinfra.Migrations(),
+	}
+}
 
-	userinfra.MigrateUserTable(db)
-	userinfra.MigrateTokenTable(db)
+// NewMigrator returns the migrator of the migrations of every module.
+func NewMigrator(database *gorm.DB) (*migrate.Migrator, error) {
+	sqlDB, err := database.DB()
+	if err != nil {
+		return nil, err
+	}
 
-	log.Println("✅ All migrations completed successfully!")
+	return migrate.New(sqlDB, MigrationSources()...)
 }
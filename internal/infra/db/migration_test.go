@@ -0,0 +1,164 @@
+package db
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"jamlink-backend/internal/infra/db/migrate"
+	"jamlink-backend/internal/modules/auth/domain/device"
+	"jamlink-backend/internal/modules/auth/domain/user"
+	userRepository "jamlink-backend/internal/modules/auth/repository"
+)
+
+// baselineSchema is the schema gorm's AutoMigrate created at the baseline release, before versioned
+// migrations.
+const baselineSchema = `
+CREATE TABLE users (
+	id uuid DEFAULT gen_random_uuid(),
+	email varchar(255) NOT NULL,
+	password varchar(255) NOT NULL,
+	created_at timestamptz,
+	updated_at timestamptz,
+	preferred_lang varchar(5) DEFAULT 'fr',
+	is_verified boolean DEFAULT false,
+	verified_at timestamptz DEFAULT null,
+	provider text DEFAULT 'local',
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX idx_users_email ON users (email);
+CREATE TABLE tokens (
+	id uuid DEFAULT gen_random_uuid(),
+	user_id uuid NOT NULL,
+	token text NOT NULL,
+	expires_at timestamptz NOT NULL,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX idx_tokens_token ON tokens (token);
+CREATE INDEX idx_tokens_user_id ON tokens (user_id);
+`
+
+// testDatabase connects to TEST_DATABASE_DSN, a key/value Postgres DSN, in a schema of its own that
+// is dropped at the end of the test. The test is skipped when the variable is not set.
+func testDatabase(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	admin, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: queryLogger{}})
+	require.NoError(t, err)
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	require.NoError(t, admin.Exec("CREATE SCHEMA "+schema).Error)
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		_ = Close(admin)
+	})
+
+	database, err := gorm.Open(postgres.Open(dsn+" search_path="+schema), &gorm.Config{Logger: queryLogger{}})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = Close(database) })
+
+	return database
+}
+
+func TestMigrations_UpgradeBaselineSchema(t *testing.T) {
+	database := testDatabase(t)
+	require.NoError(t, database.Exec(baselineSchema).Error)
+
+	userID := uuid.New()
+	require.NoError(t, database.Exec(
+		"INSERT INTO users (id, email, password, created_at, updated_at, is_verified) VALUES (?, ?, ?, now(), now(), true)",
+		userID, "jane@example.com", "hash").Error)
+
+	migrator, err := NewMigrator(database)
+	require.NoError(t, err)
+	_, err = migrator.Up(t.Context())
+	require.NoError(t, err)
+
+	var upgraded user.User
+	require.NoError(t, database.First(&upgraded, "id = ?", userID).Error)
+	assert.Equal(t, user.RoleUser, upgraded.Role)
+	assert.True(t, upgraded.IsVerified())
+	assert.False(t, upgraded.Minor.IsMinor)
+}
+
+func TestMigrations_CreateEmptyDatabase(t *testing.T) {
+	database := testDatabase(t)
+
+	migrator, err := NewMigrator(database)
+	require.NoError(t, err)
+	_, err = migrator.Up(t.Context())
+	require.NoError(t, err)
+
+	created, err := user.CreateUser("jane@example.com", "hash", "fr", "local")
+	require.NoError(t, err)
+	require.NoError(t, database.Create(created).Error)
+
+	migrations, err := migrate.Load(MigrationSources()...)
+	require.NoError(t, err)
+	_, err = migrator.Down(t.Context(), len(migrations))
+	require.NoError(t, err)
+}
+
+func TestMigrations_UserDeletionCascades(t *testing.T) {
+	database := testDatabase(t)
+
+	migrator, err := NewMigrator(database)
+	require.NoError(t, err)
+	_, err = migrator.Up(t.Context())
+	require.NoError(t, err)
+
+	created, err := user.CreateUser("jane@example.com", "hash", "fr", "local")
+	require.NoError(t, err)
+	require.NoError(t, database.Create(created).Error)
+	require.NoError(t, database.Exec("INSERT INTO tokens (user_id, token, expires_at) VALUES (?, 'refresh', now())", created.ID).Error)
+	require.NoError(t, database.Exec("INSERT INTO verification_codes (user_id, code_hash, expires_at) VALUES (?, 'hash', now())", created.ID).Error)
+	require.NoError(t, database.Exec("INSERT INTO known_devices (user_id, fingerprint, last_seen_at) VALUES (?, 'fp', now())", created.ID).Error)
+	require.NoError(t, database.Exec("INSERT INTO consent_records (user_id, kind, granted, ip, recorded_at) VALUES (?, 'terms', true, '192.0.2.1', now())", created.ID).Error)
+
+	require.NoError(t, database.Delete(&user.User{}, "id = ?", created.ID).Error)
+
+	for _, table := range []string{"tokens", "verification_codes", "known_devices", "consent_records"} {
+		var count int64
+		require.NoError(t, database.Table(table).Where("user_id = ?", created.ID).Count(&count).Error)
+		assert.Zero(t, count, table)
+	}
+}
+
+func TestKnownDevices_ReportTokenIsSingleUse(t *testing.T) {
+	database := testDatabase(t)
+
+	migrator, err := NewMigrator(database)
+	require.NoError(t, err)
+	_, err = migrator.Up(t.Context())
+	require.NoError(t, err)
+
+	created, err := user.CreateUser("jane@example.com", "hash", "fr", "local")
+	require.NoError(t, err)
+	require.NoError(t, database.Create(created).Error)
+
+	now := time.Now()
+	repo := userRepository.NewPostgresKnownDeviceRepository(database)
+	knownDevice := device.CreateKnownDevice(created.ID, "fp", "Firefox / Windows", "", "", "")
+	knownDevice.IssueReportToken("report-hash", now.Add(time.Hour))
+	require.NoError(t, repo.Create(t.Context(), knownDevice))
+
+	_, err = repo.ConsumeReportToken(t.Context(), "report-hash", now.Add(2*time.Hour))
+	assert.ErrorIs(t, err, device.ErrInvalidReportToken)
+
+	userID, err := repo.ConsumeReportToken(t.Context(), "report-hash", now)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, userID)
+
+	_, err = repo.ConsumeReportToken(t.Context(), "report-hash", now)
+	assert.ErrorIs(t, err, device.ErrInvalidReportToken)
+}
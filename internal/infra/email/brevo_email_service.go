@@ -2,40 +2,99 @@ package emailinfra
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"html/template"
 	"net/http"
-	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"jamlink-backend/internal/config"
 	"jamlink-backend/internal/shared/email"
+	"jamlink-backend/internal/shared/logging"
+	"jamlink-backend/internal/shared/metrics"
 )
 
+// sendTimeout bounds a call to Brevo, so that a slow provider cannot hold a shutdown forever.
+const sendTimeout = 10 * time.Second
+
 type BrevoEmailService struct {
 	apiKey      string
 	senderName  string
 	senderEmail string
-	baseURL     string
 	templateDir string
+	client      *http.Client
+
+	mu      sync.RWMutex
+	lastErr error
 }
 
-func NewBrevoEmailService() *BrevoEmailService {
+func NewBrevoEmailService(cfg config.EmailConfig) *BrevoEmailService {
 	return &BrevoEmailService{
-		apiKey:      os.Getenv("BREVO_API_KEY"),
-		senderName:  os.Getenv("BREVOS_SENDER_NAME"),
-		senderEmail: os.Getenv("BREVO_SENDER_EMAIL"),
-		baseURL:     os.Getenv("FRONTEND_VERIFY_URL"),
+		apiKey:      cfg.BrevoAPIKey.Value(),
+		senderName:  cfg.SenderName,
+		senderEmail: cfg.SenderEmail,
 		templateDir: "internal/shared/email/templates",
+		client:      &http.Client{Timeout: sendTimeout, Transport: otelhttp.NewTransport(http.DefaultTransport)},
 	}
 }
 
-func (s *BrevoEmailService) Send(to string, templateType email.TemplateType, lang string, data map[string]string) error {
-	htmlContent, err := s.renderTemplate(templateType, data, lang)
+// Status reports whether the last call to Brevo succeeded, for the readiness probe. It does not call
+// Brevo itself, so probes cost nothing against the API quota.
+func (s *BrevoEmailService) Status(context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastErr
+}
+
+func (s *BrevoEmailService) record(err error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastErr = err
+	return err
+}
+
+// Send logs the outcome of every call with the message ID assigned by Brevo, so that a missing email
+// can be looked up in its logs, with the logger of ctx so the line carries the request it was sent
+// for. The call to Brevo is abandoned when ctx is cancelled.
+func (s *BrevoEmailService) Send(ctx context.Context, to string, templateType email.TemplateType, lang string, data map[string]string) error {
+	start := time.Now()
+	messageID, status, err := s.send(ctx, to, templateType, lang, data)
+
+	attrs := []any{"to", to, "template", string(templateType), "lang", lang, "status", status, "duration_ms", time.Since(start).Milliseconds()}
+	metrics.RecordEmail(string(templateType), lang, sendOutcome(status, err))
+	logger := logging.FromContext(ctx)
 	if err != nil {
+		logger.Error("email not sent", append(attrs, "error", err)...)
 		return err
 	}
 
+	logger.Info("email sent", append(attrs, "message_id", messageID)...)
+	return nil
+}
+
+// sendOutcome tells a recipient refused by Brevo apart from a failure of Brevo or of our own setup.
+func sendOutcome(status int, err error) string {
+	switch {
+	case err == nil:
+		return metrics.EmailSent
+	case status >= 400 && status < 500 && status != http.StatusUnauthorized && status != http.StatusForbidden:
+		return metrics.EmailRejected
+	default:
+		return metrics.EmailFailed
+	}
+}
+
+// send returns the message ID and the HTTP status of Brevo's response, 0 when there was none.
+func (s *BrevoEmailService) send(ctx context.Context, to string, templateType email.TemplateType, lang string, data map[string]string) (string, int, error) {
+	htmlContent, err := s.renderTemplate(templateType, data, lang)
+	if err != nil {
+		return "", 0, err
+	}
+
 	subject := email.GetSubject(templateType, lang)
 
 	payload := map[string]interface{}{
@@ -52,26 +111,36 @@ func (s *BrevoEmailService) Send(to string, templateType email.TemplateType, lan
 
 	body, _ := json.Marshal(payload)
 
-	req, err := http.NewRequest("POST", "https://api.brevo.com/v3/smtp/email", bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.brevo.com/v3/smtp/email", bytes.NewBuffer(body))
 	if err != nil {
-		return err
+		return "", 0, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("api-key", s.apiKey)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := s.client.Do(req)
 	if err != nil {
-		return err
+		return "", 0, s.record(fmt.Errorf("brevo unreachable: %w", err))
 	}
 	defer resp.Body.Close()
 
+	// A rejected recipient says nothing about whether emails can be sent; a rejected API key does.
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return "", resp.StatusCode, s.record(fmt.Errorf("brevo error: %s", resp.Status))
+	}
+	s.record(nil)
+
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("brevo error: %s", resp.Status)
+		return "", resp.StatusCode, fmt.Errorf("brevo error: %s", resp.Status)
 	}
 
-	return nil
+	var sent struct {
+		MessageID string `json:"messageId"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&sent)
+
+	return sent.MessageID, resp.StatusCode, nil
 }
 
 func (s *BrevoEmailService) renderTemplate(templateType email.TemplateType, data any, lang string) (string, error) {
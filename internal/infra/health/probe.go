@@ -0,0 +1,115 @@
+// Package health tells the orchestrator whether the API can take traffic.
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	ErrDraining = errors.New("shutting down")
+	ErrPending  = errors.New("pending")
+)
+
+// Check returns why a dependency is not usable, or nil.
+type Check func(ctx context.Context) error
+
+type check struct {
+	name     string
+	run      Check
+	required bool
+}
+
+// Probe runs the readiness checks. Required checks make the API unready when they fail; reported
+// ones only show up in the result, for dependencies such as the email provider whose outage should
+// not take every replica out of the load balancer.
+type Probe struct {
+	mu       sync.RWMutex
+	checks   []check
+	draining atomic.Bool
+}
+
+func NewProbe() *Probe {
+	return &Probe{}
+}
+
+func (p *Probe) Require(name string, run Check) {
+	p.add(check{name: name, run: run, required: true})
+}
+
+func (p *Probe) Report(name string, run Check) {
+	p.add(check{name: name, run: run})
+}
+
+func (p *Probe) add(c check) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.checks = append(p.checks, c)
+}
+
+// Drain makes the API unready for good, so the load balancer stops routing to it while in-flight
+// requests finish.
+func (p *Probe) Drain() {
+	p.draining.Store(true)
+}
+
+// Result maps every check to "ok" or the reason it failed.
+type Result struct {
+	Ready  bool              `json:"ready"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Ready runs every check concurrently.
+func (p *Probe) Ready(ctx context.Context) Result {
+	p.mu.RLock()
+	checks := append([]check(nil), p.checks...)
+	p.mu.RUnlock()
+
+	errs := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = c.run(ctx)
+		}()
+	}
+	wg.Wait()
+
+	result := Result{Ready: true, Checks: map[string]string{}}
+	if p.draining.Load() {
+		result.Ready = false
+		result.Checks["server"] = ErrDraining.Error()
+	}
+	for i, c := range checks {
+		if errs[i] == nil {
+			result.Checks[c.name] = "ok"
+			continue
+		}
+		result.Checks[c.name] = errs[i].Error()
+		if c.required {
+			result.Ready = false
+		}
+	}
+
+	return result
+}
+
+// Flag is a check that fails with ErrPending until Set is called, for startup steps such as the
+// database migrations.
+type Flag struct {
+	done atomic.Bool
+}
+
+func (f *Flag) Set() {
+	f.done.Store(true)
+}
+
+func (f *Flag) Check(context.Context) error {
+	if !f.done.Load() {
+		return ErrPending
+	}
+	return nil
+}
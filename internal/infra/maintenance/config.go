@@ -0,0 +1,25 @@
+package maintenance
+
+import (
+	"time"
+
+	"jamlink-backend/internal/config"
+)
+
+type Config struct {
+	Interval              time.Duration
+	TokenBatchSize        int
+	UserBatchSize         int
+	UnverifiedRetention   time.Duration
+	UnverifiedWarningLead time.Duration
+}
+
+func NewConfig(cfg config.MaintenanceConfig) Config {
+	return Config{
+		Interval:              cfg.Interval,
+		TokenBatchSize:        cfg.TokenBatchSize,
+		UserBatchSize:         cfg.UserBatchSize,
+		UnverifiedRetention:   time.Duration(cfg.UnverifiedRetentionDays) * 24 * time.Hour,
+		UnverifiedWarningLead: time.Duration(cfg.UnverifiedWarningDays) * 24 * time.Hour,
+	}
+}
@@ -0,0 +1,17 @@
+package maintenance
+
+import "jamlink-backend/internal/shared/metrics"
+
+func recordRun(report *RunReport) {
+	if report.Skipped {
+		metrics.RecordMaintenanceRunSkipped()
+		return
+	}
+
+	metrics.RecordMaintenanceRun(map[string]int64{
+		"tokens_deleted":          report.TokensDeleted,
+		"users_warned":            int64(report.UsersWarned),
+		"users_deleted":           int64(report.UsersDeleted),
+		"impersonations_notified": int64(report.ImpersonationsNotified),
+	}, report.Failures, report.Duration)
+}
@@ -0,0 +1,152 @@
+package maintenance
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
+	"jamlink-backend/internal/infra/db"
+	useCase "jamlink-backend/internal/modules/auth/usecase"
+)
+
+// advisoryLockKey identifies the maintenance job among Postgres advisory locks.
+const advisoryLockKey int64 = 0x6a616d6c696e6b
+
+var tracer = otel.Tracer("jamlink-backend/internal/infra/maintenance")
+
+type RunReport struct {
+	Skipped       bool
+	TokensDeleted int64
+	UsersWarned   int
+	UsersDeleted  int
+	// ImpersonationsNotified counts the users told about an impersonation session that ended.
+	ImpersonationsNotified int
+	Failures               int
+	Duration               time.Duration
+}
+
+type Worker struct {
+	database             *gorm.DB
+	config               Config
+	purgeExpiredTokens   *useCase.PurgeExpiredTokensUseCase
+	purgeUnverifiedUsers *useCase.PurgeUnverifiedUsersUseCase
+	notifyImpersonated   *useCase.NotifyImpersonatedUsersUseCase
+}
+
+func NewWorker(database *gorm.DB, config Config, purgeExpiredTokensUC *useCase.PurgeExpiredTokensUseCase, purgeUnverifiedUsersUC *useCase.PurgeUnverifiedUsersUseCase, notifyImpersonatedUC *useCase.NotifyImpersonatedUsersUseCase) *Worker {
+	return &Worker{
+		database:             database,
+		config:               config,
+		purgeExpiredTokens:   purgeExpiredTokensUC,
+		purgeUnverifiedUsers: purgeUnverifiedUsersUC,
+		notifyImpersonated:   notifyImpersonatedUC,
+	}
+}
+
+// Start runs the maintenance jobs every Interval until ctx is cancelled.
+func (w *Worker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.RunOnce(ctx); err != nil {
+			slog.Error("maintenance run failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce runs every maintenance job once, unless another replica already holds the lock. Each run is
+// the root span of its own trace.
+func (w *Worker) RunOnce(ctx context.Context) (*RunReport, error) {
+	report := &RunReport{}
+	start := time.Now()
+
+	ctx, span := tracer.Start(ctx, "maintenance.RunOnce")
+	defer span.End()
+
+	unlock, ok, err := db.TryAdvisoryLock(ctx, w.database, advisoryLockKey)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		report.Skipped = true
+		span.SetAttributes(attribute.Bool("maintenance.skipped", true))
+		recordRun(report)
+		return report, nil
+	}
+	defer unlock()
+
+	tokens, tokensErr := w.purgeExpiredTokens.Execute(ctx, useCase.PurgeExpiredTokensInput{
+		Now:       start,
+		BatchSize: w.config.TokenBatchSize,
+	})
+	if tokens != nil {
+		report.TokensDeleted = tokens.Deleted
+	}
+	if tokensErr != nil {
+		report.Failures++
+	}
+
+	users, usersErr := w.purgeUnverifiedUsers.Execute(ctx, useCase.PurgeUnverifiedUsersInput{
+		Now:         start,
+		Retention:   w.config.UnverifiedRetention,
+		WarningLead: w.config.UnverifiedWarningLead,
+		BatchSize:   w.config.UserBatchSize,
+	})
+	if users != nil {
+		report.UsersWarned = users.Warned
+		report.UsersDeleted = users.Deleted
+		report.Failures += users.Failed
+	}
+	if usersErr != nil && (users == nil || users.Failed == 0) {
+		report.Failures++
+	}
+
+	impersonations, impersonationsErr := w.notifyImpersonated.Execute(ctx, useCase.NotifyImpersonatedUsersInput{
+		Now:       start,
+		BatchSize: w.config.UserBatchSize,
+	})
+	if impersonations != nil {
+		report.ImpersonationsNotified = impersonations.Notified
+		report.Failures += impersonations.Failed
+	}
+	if impersonationsErr != nil && (impersonations == nil || impersonations.Failed == 0) {
+		report.Failures++
+	}
+
+	report.Duration = time.Since(start)
+	recordRun(report)
+	span.SetAttributes(
+		attribute.Int64("maintenance.tokens_deleted", report.TokensDeleted),
+		attribute.Int("maintenance.users_warned", report.UsersWarned),
+		attribute.Int("maintenance.users_deleted", report.UsersDeleted),
+		attribute.Int("maintenance.impersonations_notified", report.ImpersonationsNotified),
+		attribute.Int("maintenance.failures", report.Failures),
+	)
+
+	slog.Info("maintenance run",
+		"tokens_deleted", report.TokensDeleted,
+		"users_warned", report.UsersWarned,
+		"users_deleted", report.UsersDeleted,
+		"impersonations_notified", report.ImpersonationsNotified,
+		"failures", report.Failures,
+		"duration_ms", report.Duration.Milliseconds(),
+	)
+
+	if tokensErr != nil {
+		return report, tokensErr
+	}
+	if usersErr != nil {
+		return report, usersErr
+	}
+	return report, impersonationsErr
+}
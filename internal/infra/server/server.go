@@ -0,0 +1,52 @@
+// Package server runs the HTTP server with the timeouts of config.ServerConfig.
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+
+	"jamlink-backend/internal/config"
+)
+
+type Server struct {
+	http *http.Server
+}
+
+func New(cfg config.ServerConfig, handler http.Handler) *Server {
+	return &Server{http: &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}}
+}
+
+// Start listens before returning, so that a port already in use fails the startup, then serves in
+// the background. The channel receives the error that stops the server early, if any.
+func (s *Server) Start() (<-chan error, error) {
+	listener, err := net.Listen("tcp", s.http.Addr)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("listening", "addr", listener.Addr().String())
+
+	errs := make(chan error, 1)
+	go func() {
+		if err := s.http.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errs <- err
+		}
+		close(errs)
+	}()
+
+	return errs, nil
+}
+
+// Shutdown stops accepting connections and waits for in-flight requests until ctx expires.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.http.Shutdown(ctx)
+}
@@ -0,0 +1,45 @@
+package device
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type KnownDevice struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_known_device_user_fingerprint"`
+	Fingerprint string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_known_device_user_fingerprint"`
+	Description string    `gorm:"type:varchar(255)"`
+	IPPrefix    string    `gorm:"type:varchar(64)"`
+	Country     string    `gorm:"type:varchar(64)"`
+	City        string    `gorm:"type:varchar(128)"`
+	LastSeenAt  time.Time `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	// ReportTokenHash is the keyed hash of the "this wasn't me" link sent when the device first signed in.
+	// It is empty when no link was sent or once it has been used.
+	ReportTokenHash string `gorm:"type:varchar(64);not null;default:''"`
+	ReportExpiresAt *time.Time
+}
+
+func CreateKnownDevice(userID uuid.UUID, fingerprint, description, ipPrefix, country, city string) *KnownDevice {
+	now := time.Now()
+
+	return &KnownDevice{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Fingerprint: fingerprint,
+		Description: description,
+		IPPrefix:    ipPrefix,
+		Country:     country,
+		City:        city,
+		LastSeenAt:  now,
+		CreatedAt:   now,
+	}
+}
+
+// IssueReportToken attaches the "this wasn't me" link of the new sign-in email to the device.
+func (d *KnownDevice) IssueReportToken(tokenHash string, expiresAt time.Time) {
+	d.ReportTokenHash = tokenHash
+	d.ReportExpiresAt = &expiresAt
+}
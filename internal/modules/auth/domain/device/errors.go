@@ -0,0 +1,8 @@
+package device
+
+import "jamlink-backend/internal/shared/apperror"
+
+var (
+	ErrDeviceNotFound     = apperror.NotFound("device_not_found", "device not found")
+	ErrInvalidReportToken = apperror.Unauthorized("invalid_report_token", "the link is invalid, expired or already used")
+)
@@ -0,0 +1,19 @@
+package device
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type KnownDeviceRepository interface {
+	Create(ctx context.Context, device *KnownDevice) error
+	FindByFingerprint(ctx context.Context, userID uuid.UUID, fingerprint string) (*KnownDevice, error)
+	CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	Touch(ctx context.Context, id uuid.UUID, seenAt time.Time) error
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+	// ConsumeReportToken clears the unexpired report token with this hash and returns the user it was
+	// sent to, or ErrInvalidReportToken. A token can only be consumed once.
+	ConsumeReportToken(ctx context.Context, tokenHash string, now time.Time) (uuid.UUID, error)
+}
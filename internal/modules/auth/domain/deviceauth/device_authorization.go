@@ -0,0 +1,103 @@
+package deviceauth
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// GrantType is the grant_type of the token requests of RFC 8628.
+	GrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	AuthorizationTTL = time.Minute * 10
+	// PollInterval is the minimum number of seconds between two token requests, raised by SlowDownStep
+	// every time a device polls too fast.
+	PollInterval = 5
+	SlowDownStep = 5
+
+	// userCodeAlphabet has no vowels, so codes never spell words, and no look-alike characters.
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength   = 8
+)
+
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusDenied   Status = "denied"
+)
+
+// DeviceAuthorization is a pending RFC 8628 request. The device keeps the device code, of which only a
+// hash is stored; the user types the short user code in the browser to approve it.
+type DeviceAuthorization struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	DeviceCodeHash string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	UserCode       string     `gorm:"type:varchar(16);not null;uniqueIndex"`
+	ClientID       string     `gorm:"type:varchar(100);not null"`
+	Status         Status     `gorm:"type:varchar(20);not null;default:pending"`
+	UserID         *uuid.UUID `gorm:"type:uuid"`
+	DecidedAt      *time.Time
+	Interval       int `gorm:"not null"`
+	LastPolledAt   *time.Time
+	ExpiresAt      time.Time `gorm:"not null;index"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+}
+
+func CreateDeviceAuthorization(deviceCodeHash, userCode, clientID string) *DeviceAuthorization {
+	now := time.Now()
+
+	return &DeviceAuthorization{
+		ID:             uuid.New(),
+		DeviceCodeHash: deviceCodeHash,
+		UserCode:       userCode,
+		ClientID:       clientID,
+		Status:         StatusPending,
+		Interval:       PollInterval,
+		ExpiresAt:      now.Add(AuthorizationTTL),
+		CreatedAt:      now,
+	}
+}
+
+func (a *DeviceAuthorization) IsExpired(now time.Time) bool {
+	return now.After(a.ExpiresAt)
+}
+
+// PolledTooFast reports whether the device polled again before the end of its interval.
+func (a *DeviceAuthorization) PolledTooFast(now time.Time) bool {
+	return a.LastPolledAt != nil && now.Sub(*a.LastPolledAt) < time.Duration(a.Interval)*time.Second
+}
+
+// GenerateUserCode returns a code such as "WDJB-MJHT", short enough to be typed from a TV screen.
+func GenerateUserCode() (string, error) {
+	var code strings.Builder
+	max := big.NewInt(int64(len(userCodeAlphabet)))
+
+	for i := 0; i < userCodeLength; i++ {
+		if i == userCodeLength/2 {
+			code.WriteByte('-')
+		}
+
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code.WriteByte(userCodeAlphabet[n.Int64()])
+	}
+
+	return code.String(), nil
+}
+
+// NormalizeUserCode accepts the code as typed by the user: lower case, with or without the dash.
+func NormalizeUserCode(userCode string) string {
+	code := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(userCode))
+	if len(code) != userCodeLength {
+		return code
+	}
+
+	return code[:userCodeLength/2] + "-" + code[userCodeLength/2:]
+}
@@ -0,0 +1,22 @@
+package deviceauth
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DeviceAuthorizationRepository interface {
+	Create(ctx context.Context, authorization *DeviceAuthorization) error
+	FindByDeviceCodeHash(ctx context.Context, deviceCodeHash string) (*DeviceAuthorization, error)
+	FindByUserCode(ctx context.Context, userCode string) (*DeviceAuthorization, error)
+	// Decide moves a pending authorization to approved or denied, and returns ErrAlreadyDecided when it
+	// is no longer pending.
+	Decide(ctx context.Context, id uuid.UUID, status Status, userID uuid.UUID, at time.Time) error
+	RecordPoll(ctx context.Context, id uuid.UUID, at time.Time, interval int) error
+	// Consume deletes an approved authorization, and returns ErrInvalidDeviceCode when another request
+	// already did, so tokens are issued once.
+	Consume(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,18 @@
+package deviceauth
+
+import (
+	"errors"
+
+	"jamlink-backend/internal/shared/apperror"
+)
+
+// The polling errors carry the error codes of RFC 8628 section 3.5.
+var (
+	ErrAuthorizationPending = errors.New("authorization_pending")
+	ErrSlowDown             = errors.New("slow_down")
+	ErrExpiredToken         = errors.New("expired_token")
+	ErrAccessDenied         = errors.New("access_denied")
+	ErrInvalidDeviceCode    = errors.New("invalid device code")
+	ErrUserCodeNotFound     = apperror.NotFound("device_user_code_not_found", "unknown or expired user code")
+	ErrAlreadyDecided       = apperror.Conflict("device_request_already_decided", "this device request was already approved or denied")
+)
@@ -0,0 +1,12 @@
+package impersonation
+
+import "jamlink-backend/internal/shared/apperror"
+
+var (
+	ErrSessionNotFound        = apperror.NotFound("impersonation_session_not_found", "impersonation session not found")
+	ErrReasonRequired         = apperror.BadRequest("impersonation_reason_required", "a reason is required to impersonate a user")
+	ErrSelfImpersonation      = apperror.BadRequest("self_impersonation", "you cannot impersonate yourself")
+	ErrImpersonationForbidden = apperror.Forbidden("impersonation_requires_admin", "only admins can impersonate users")
+	ErrCannotImpersonateAdmin = apperror.Forbidden("admin_impersonation", "admins cannot be impersonated")
+	ErrInvalidTarget          = apperror.BadRequest("invalid_impersonation_target", "the user to impersonate is not a valid user ID")
+)
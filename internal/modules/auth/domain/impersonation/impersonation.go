@@ -0,0 +1,72 @@
+package impersonation
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is granted to a staff member (the actor) acting as a user. Its token is short-lived and
+// cannot be refreshed; the user is told about the session once it has expired.
+type Session struct {
+	ID         uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ActorID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"actorId"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"userId"`
+	Reason     string     `gorm:"type:varchar(255);not null" json:"reason"`
+	ExpiresAt  time.Time  `gorm:"not null;index" json:"expiresAt"`
+	NotifiedAt *time.Time `json:"-"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (Session) TableName() string {
+	return "impersonation_sessions"
+}
+
+// AuditEntry records one request made with the token of an impersonation session.
+type AuditEntry struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	SessionID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Method    string    `gorm:"type:varchar(10);not null"`
+	Path      string    `gorm:"type:varchar(255);not null"`
+	Status    int       `gorm:"not null"`
+	IP        string    `gorm:"type:varchar(64)"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (AuditEntry) TableName() string {
+	return "impersonation_audit_entries"
+}
+
+func CreateSession(actorID, userID uuid.UUID, reason string, ttl time.Duration) (*Session, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrReasonRequired
+	}
+	if actorID == userID {
+		return nil, ErrSelfImpersonation
+	}
+
+	now := time.Now()
+
+	return &Session{
+		ID:        uuid.New(),
+		ActorID:   actorID,
+		UserID:    userID,
+		Reason:    reason,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, nil
+}
+
+func CreateAuditEntry(sessionID uuid.UUID, method, path string, status int, ip string) *AuditEntry {
+	return &AuditEntry{
+		ID:        uuid.New(),
+		SessionID: sessionID,
+		Method:    method,
+		Path:      path,
+		Status:    status,
+		IP:        ip,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,18 @@
+package impersonation
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ImpersonationRepository interface {
+	Create(ctx context.Context, session *Session) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Session, error)
+	AppendAudit(ctx context.Context, entry *AuditEntry) error
+	CountAudit(ctx context.Context, sessionID uuid.UUID) (int64, error)
+	// FindEndedUnnotified returns sessions expired before now whose user has not been told yet.
+	FindEndedUnnotified(ctx context.Context, now time.Time, limit int) ([]*Session, error)
+	MarkNotified(ctx context.Context, id uuid.UUID, at time.Time) error
+}
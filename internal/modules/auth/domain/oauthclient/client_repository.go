@@ -0,0 +1,12 @@
+package oauthclient
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type ClientRepository interface {
+	Create(ctx context.Context, client *Client) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Client, error)
+}
@@ -0,0 +1,12 @@
+package oauthclient
+
+import "jamlink-backend/internal/shared/apperror"
+
+var (
+	ErrClientNotFound     = apperror.NotFound("oauth_client_not_found", "oauth client not found")
+	ErrInvalidClient      = apperror.Unauthorized("invalid_client", "invalid client credentials")
+	ErrInvalidScope       = apperror.BadRequest("invalid_scope", "invalid scope")
+	ErrInvalidRedirectURI = apperror.BadRequest("invalid_redirect_uri", "redirect URIs must be absolute https URIs without fragment")
+	ErrClientNameRequired = apperror.BadRequest("client_name_required", "a client name is required")
+	ErrRegisterForbidden  = apperror.Forbidden("oauth_client_registration_forbidden", "only admins can register oauth clients")
+)
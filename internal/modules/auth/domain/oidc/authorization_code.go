@@ -0,0 +1,83 @@
+package oidc
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// GrantType is the grant_type of the token requests of RFC 6749 section 4.1.
+	GrantType = "authorization_code"
+
+	ResponseTypeCode    = "code"
+	CodeChallengeMethod = "S256"
+	CodeTTL             = time.Minute
+)
+
+// pkceRegex matches both the code verifiers and the S256 challenges of RFC 7636.
+var pkceRegex = regexp.MustCompile(`^[A-Za-z0-9._~-]{43,128}$`)
+
+// AuthorizationCode is issued once the user has consented, and exchanged once by the client for
+// tokens. Only a hash of the code is stored.
+type AuthorizationCode struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CodeHash      string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ClientID      uuid.UUID `gorm:"type:uuid;not null"`
+	UserID        uuid.UUID `gorm:"type:uuid;not null"`
+	RedirectURI   string    `gorm:"type:text;not null"`
+	Scopes        string    `gorm:"type:text;not null"`
+	Nonce         string    `gorm:"type:varchar(255)"`
+	CodeChallenge string    `gorm:"type:varchar(128);not null"`
+	// AuthTime and AuthMethods copy the authentication of the browser session into the ID token.
+	AuthTime    time.Time `gorm:"not null"`
+	AuthMethods string    `gorm:"type:varchar(255)"`
+	ExpiresAt   time.Time `gorm:"not null;index"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
+
+func (AuthorizationCode) TableName() string {
+	return "oauth_authorization_codes"
+}
+
+func CreateAuthorizationCode(codeHash string, clientID, userID uuid.UUID, redirectURI string, scopes []string, nonce, codeChallenge string, authTime time.Time, authMethods []string) *AuthorizationCode {
+	now := time.Now()
+
+	return &AuthorizationCode{
+		ID:            uuid.New(),
+		CodeHash:      codeHash,
+		ClientID:      clientID,
+		UserID:        userID,
+		RedirectURI:   redirectURI,
+		Scopes:        strings.Join(scopes, " "),
+		Nonce:         nonce,
+		CodeChallenge: codeChallenge,
+		AuthTime:      authTime,
+		AuthMethods:   strings.Join(authMethods, " "),
+		ExpiresAt:     now.Add(CodeTTL),
+		CreatedAt:     now,
+	}
+}
+
+func (c *AuthorizationCode) IsExpired(now time.Time) bool {
+	return now.After(c.ExpiresAt)
+}
+
+// VerifyCodeVerifier checks the PKCE verifier against the S256 challenge of the authorization request.
+func (c *AuthorizationCode) VerifyCodeVerifier(verifier string) bool {
+	if !pkceRegex.MatchString(verifier) {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:]) == c.CodeChallenge
+}
+
+// ValidCodeChallenge reports whether the challenge of an authorization request is a well-formed S256 one.
+func ValidCodeChallenge(challenge, method string) bool {
+	return method == CodeChallengeMethod && pkceRegex.MatchString(challenge)
+}
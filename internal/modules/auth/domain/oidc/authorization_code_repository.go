@@ -0,0 +1,10 @@
+package oidc
+
+import "context"
+
+type AuthorizationCodeRepository interface {
+	Create(ctx context.Context, code *AuthorizationCode) error
+	// Consume deletes the code and returns it, or returns ErrInvalidCode when it does not exist, so a
+	// code is exchanged once.
+	Consume(ctx context.Context, codeHash string) (*AuthorizationCode, error)
+}
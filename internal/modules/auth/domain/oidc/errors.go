@@ -0,0 +1,22 @@
+package oidc
+
+import (
+	"errors"
+
+	"jamlink-backend/internal/shared/apperror"
+)
+
+// The authorization errors carry the error codes of RFC 6749 section 4.1.2.1, as they are sent back
+// to the client in the redirect.
+var (
+	ErrUnsupportedResponseType = errors.New("unsupported_response_type")
+	ErrInvalidRequest          = errors.New("invalid_request")
+	ErrInvalidScope            = errors.New("invalid_scope")
+	ErrConsentRequired         = errors.New("consent_required")
+	ErrAccessDenied            = errors.New("access_denied")
+
+	ErrUnknownClient      = errors.New("unknown client")
+	ErrInvalidRedirectURI = errors.New("redirect_uri is not registered for this client")
+	ErrInvalidCode        = errors.New("invalid, expired or already used authorization code")
+	ErrGrantNotFound      = apperror.NotFound("oauth_grant_not_found", "oauth grant not found")
+)
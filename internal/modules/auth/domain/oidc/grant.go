@@ -0,0 +1,71 @@
+package oidc
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+// SupportedScopes are the OpenID Connect scopes a client may be registered with.
+var SupportedScopes = []string{ScopeOpenID, ScopeProfile, ScopeEmail}
+
+// Grant records the scopes a user consented to give a client, so the consent screen is only shown
+// again when the client asks for more.
+type Grant struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"-"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_oauth_grant_user_client" json:"-"`
+	ClientID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_oauth_grant_user_client" json:"clientId"`
+	Scopes    string    `gorm:"type:text;not null" json:"scopes"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (Grant) TableName() string {
+	return "oauth_grants"
+}
+
+// CreateGrant merges the scopes of an earlier grant, if any, with the newly consented ones.
+func CreateGrant(userID, clientID uuid.UUID, scopes []string, previous *Grant) *Grant {
+	merged := scopes
+	if previous != nil {
+		merged = strings.Fields(previous.Scopes)
+		for _, scope := range scopes {
+			if !contains(merged, scope) {
+				merged = append(merged, scope)
+			}
+		}
+	}
+
+	return &Grant{
+		ID:       uuid.New(),
+		UserID:   userID,
+		ClientID: clientID,
+		Scopes:   strings.Join(merged, " "),
+	}
+}
+
+func (g *Grant) Covers(scopes []string) bool {
+	granted := strings.Fields(g.Scopes)
+	for _, scope := range scopes {
+		if !contains(granted, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,15 @@
+package oidc
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type GrantRepository interface {
+	// Save creates the grant of the user to the client, or replaces its scopes.
+	Save(ctx context.Context, grant *Grant) error
+	Find(ctx context.Context, userID, clientID uuid.UUID) (*Grant, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*Grant, error)
+	Delete(ctx context.Context, userID, clientID uuid.UUID) error
+}
@@ -0,0 +1,10 @@
+package otp
+
+import "jamlink-backend/internal/shared/apperror"
+
+var (
+	ErrInvalidCode     = apperror.Unauthorized("invalid_verification_code", "invalid verification code")
+	ErrCodeExpired     = apperror.Unauthorized("verification_code_expired", "verification code expired")
+	ErrTooManyAttempts = apperror.TooManyRequests("too_many_verification_attempts", "too many verification attempts, request a new code")
+	ErrTooManyCodes    = apperror.TooManyRequests("too_many_verification_codes", "a verification code was sent recently, wait before asking for another one")
+)
@@ -0,0 +1,42 @@
+package otp
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CodeLength  = 6
+	CodeTTL     = time.Minute * 15
+	MaxAttempts = 5
+	// ResendCooldown is the time to wait before another code is sent to a user, and MaxCodesPerHour
+	// caps the codes sent to a user in an hour. Each code allows MaxAttempts guesses, so together they
+	// bound the guesses on an account.
+	ResendCooldown  = time.Minute
+	MaxCodesPerHour = 5
+)
+
+type VerificationCode struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	CodeHash  string    `gorm:"type:varchar(64);not null"`
+	Attempts  int       `gorm:"not null;default:0"`
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func CreateVerificationCode(userID uuid.UUID, codeHash string, expiresAt time.Time) *VerificationCode {
+	return &VerificationCode{
+		ID:        uuid.New(),
+		UserID:    userID,
+		CodeHash:  codeHash,
+		Attempts:  0,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+}
+
+func (c *VerificationCode) IsExpired(now time.Time) bool {
+	return now.After(c.ExpiresAt)
+}
@@ -0,0 +1,18 @@
+package otp
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type VerificationCodeRepository interface {
+	Create(ctx context.Context, code *VerificationCode) error
+	FindLatestByUserID(ctx context.Context, userID uuid.UUID) (*VerificationCode, error)
+	CountCreatedSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error)
+	// UseAttempt counts a guess of the code in a single statement, so that parallel guesses cannot
+	// exceed maxAttempts. It returns ErrTooManyAttempts when none is left.
+	UseAttempt(ctx context.Context, id uuid.UUID, maxAttempts int) error
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
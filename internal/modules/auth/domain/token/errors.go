@@ -1,12 +1,16 @@
 package token
 
-import "errors"
+import (
+	"errors"
+
+	"jamlink-backend/internal/shared/apperror"
+)
 
 var (
-	ErrPasswordDoesntMatch = errors.New("password does not match")
-	ErrTokenType           = errors.New("unexpected token type")
-	ErrTokenExpired        = errors.New("token expired")
+	ErrPasswordDoesntMatch = apperror.BadRequest("password_confirmation_mismatch", "password does not match")
+	ErrTokenType           = apperror.Unauthorized("wrong_token_type", "unexpected token type")
+	ErrTokenExpired        = apperror.Unauthorized("token_expired", "token expired")
 	ErrTokenCreationFailed = errors.New("token creation failed")
 	ErrTokenDeletionFailed = errors.New("token deletion failed")
-	ErrTokenNotFound       = errors.New("token not found")
+	ErrTokenNotFound       = apperror.Unauthorized("token_not_found", "token not found")
 )
@@ -0,0 +1,23 @@
+package token
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session holds the claims of an opaque access or refresh token, when TOKEN_STRATEGY is "opaque".
+// Only a keyed hash of the token is stored. Tokens that are not tied to an account (email
+// verification, client credentials) have no UserID.
+type Session struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	TokenHash string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	UserID    *uuid.UUID `gorm:"type:uuid;index"`
+	Claims    string     `gorm:"type:jsonb;not null"`
+	ExpiresAt time.Time  `gorm:"not null;index"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+}
+
+func (Session) TableName() string {
+	return "opaque_sessions"
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 )
 
+// Token is a token handed to a client that can be looked up and revoked. Token holds its keyed hash
+// (security.SecurityService.HashOTP), never the token itself, so the table cannot be used to sign in.
 type Token struct {
 	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
@@ -14,7 +16,7 @@ type Token struct {
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 }
 
-func CreateToken(userID uuid.UUID, token string, expiresAt time.Time) (*Token, error) {
+func CreateToken(userID uuid.UUID, tokenHash string, expiresAt time.Time) (*Token, error) {
 	if err := tokenInvariants.ValidateToken(expiresAt); err != nil {
 		return nil, err
 	}
@@ -22,7 +24,7 @@ func CreateToken(userID uuid.UUID, token string, expiresAt time.Time) (*Token, e
 	return &Token{
 		ID:        uuid.New(),
 		UserID:    userID,
-		Token:     token,
+		Token:     tokenHash,
 		ExpiresAt: expiresAt,
 		CreatedAt: time.Now(),
 	}, nil
@@ -1,10 +1,22 @@
 package token
 
-import "github.com/google/uuid"
+import (
+	"context"
+	"time"
 
+	"github.com/google/uuid"
+)
+
+// TokenRepository also stores the sessions of opaque tokens, so it can be used as the
+// security.SessionStore of the opaque token strategy. Deleting the tokens of a user deletes their
+// sessions too, which revokes their opaque access tokens at once.
 type TokenRepository interface {
-	Create(token *Token) error
-	FindByToken(token string) (*Token, error)
-	DeleteByID(userID uuid.UUID) error
-	DeleteUserTokens(userID uuid.UUID) error
+	Create(ctx context.Context, token *Token) error
+	FindByToken(ctx context.Context, tokenHash string) (*Token, error)
+	// DeleteByID returns ErrTokenNotFound when no token has this ID.
+	DeleteByID(ctx context.Context, id uuid.UUID) error
+	DeleteUserTokens(ctx context.Context, userID uuid.UUID) error
+	DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error)
+	CreateSession(ctx context.Context, tokenHash string, userID *uuid.UUID, claims []byte, expiresAt time.Time) error
+	FindSession(ctx context.Context, tokenHash string) (claims []byte, expiresAt time.Time, err error)
 }
@@ -0,0 +1,25 @@
+package transaction
+
+import (
+	"context"
+	"jamlink-backend/internal/modules/auth/domain/device"
+	tokenDomain "jamlink-backend/internal/modules/auth/domain/token"
+	"jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/invitation/domain/invitation"
+)
+
+// Repositories are the repositories of a unit of work. They all share its transaction; add a field when
+// a use case needs another repository in one.
+type Repositories struct {
+	Users        user.UserRepository
+	Tokens       tokenDomain.TokenRepository
+	KnownDevices device.KnownDeviceRepository
+	Invitations  invitation.InvitationRepository
+}
+
+// UnitOfWork runs several repository calls atomically, without use cases depending on the database.
+type UnitOfWork interface {
+	// Do runs fn in a transaction, committed when fn returns nil and rolled back otherwise. Only the
+	// repositories given to fn take part in it.
+	Do(ctx context.Context, fn func(repos Repositories) error) error
+}
@@ -1,8 +1,11 @@
 package user
 
-import "errors"
+import "jamlink-backend/internal/shared/apperror"
 
 var (
-	ErrEmailAlreadyExists = errors.New("email already exists")
-	ErrUserNotFound       = errors.New("user not found")
+	ErrEmailAlreadyExists  = apperror.Conflict("email_already_exists", "email already exists")
+	ErrUserNotFound        = apperror.NotFound("user_not_found", "user not found")
+	ErrNotAwaitingGuardian = apperror.BadRequest("guardian_consent_not_pending", "no guardian consent is pending for this account")
+
+	ErrGuardianConsentRequestedRecently = apperror.TooManyRequests("guardian_consent_requested_recently", "the guardian was already asked for consent recently")
 )
@@ -0,0 +1,40 @@
+package userInvariants
+
+import (
+	"jamlink-backend/internal/shared/apperror"
+	"strings"
+	"time"
+)
+
+const maxAge = 120
+
+var (
+	ErrInvalidDateOfBirth      = apperror.BadRequest("invalid_date_of_birth", "invalid date of birth")
+	ErrGuardianEmailRequired   = apperror.BadRequest("guardian_email_required", "a guardian email is required for minors")
+	ErrGuardianEmailIsOwnEmail = apperror.BadRequest("guardian_email_is_own_email", "the guardian email must differ from the account email")
+)
+
+func ValidateDateOfBirth(dateOfBirth time.Time, at time.Time) error {
+	if dateOfBirth.After(at) || dateOfBirth.Before(at.AddDate(-maxAge, 0, 0)) {
+		return ErrInvalidDateOfBirth
+	}
+
+	return nil
+}
+
+// ValidateGuardianEmail checks the address a minor gave for their guardian.
+func ValidateGuardianEmail(email, guardianEmail string) error {
+	if guardianEmail == "" {
+		return ErrGuardianEmailRequired
+	}
+
+	if err := ValidateEmail(guardianEmail); err != nil {
+		return err
+	}
+
+	if strings.EqualFold(email, guardianEmail) {
+		return ErrGuardianEmailIsOwnEmail
+	}
+
+	return nil
+}
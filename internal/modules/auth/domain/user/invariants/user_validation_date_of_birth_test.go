@@ -0,0 +1,38 @@
+package userInvariants
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDateOfBirth(t *testing.T) {
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		dateOfBirth time.Time
+		expectError bool
+	}{
+		{time.Date(2010, time.May, 1, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(1950, time.January, 1, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateDateOfBirth(tt.dateOfBirth, now)
+		if tt.expectError {
+			assert.ErrorIs(t, err, ErrInvalidDateOfBirth, "Expected error for date of birth: %s", tt.dateOfBirth)
+		} else {
+			assert.NoError(t, err, "Expected no error for date of birth: %s", tt.dateOfBirth)
+		}
+	}
+}
+
+func TestValidateGuardianEmail(t *testing.T) {
+	assert.NoError(t, ValidateGuardianEmail("teen@example.com", "parent@example.com"))
+	assert.ErrorIs(t, ValidateGuardianEmail("teen@example.com", ""), ErrGuardianEmailRequired)
+	assert.ErrorIs(t, ValidateGuardianEmail("teen@example.com", "not-an-email"), ErrInvalidUserEmail)
+	assert.ErrorIs(t, ValidateGuardianEmail("teen@example.com", "Teen@Example.com"), ErrGuardianEmailIsOwnEmail)
+}
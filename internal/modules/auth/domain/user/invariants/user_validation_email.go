@@ -1,12 +1,12 @@
 package userInvariants
 
 import (
-	"errors"
+	"jamlink-backend/internal/shared/apperror"
 	"regexp"
 )
 
 var (
-	ErrInvalidUserEmail = errors.New("invalid user email")
+	ErrInvalidUserEmail = apperror.BadRequest("invalid_email", "invalid user email")
 	emailRegex          = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9\-]+(\.[a-zA-Z0-9\-]+)*\.[a-zA-Z]{2,}$`)
 )
 
@@ -1,18 +1,25 @@
 package userInvariants
 
 import (
-	"errors"
+	"jamlink-backend/internal/shared/apperror"
 	"regexp"
 )
 
+// The bounds of the length of a password, sent as the "min" and "max" params of ErrShortPassword and
+// ErrLongPassword.
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 64
+)
+
 var (
-	ErrInvalidUserPassword = errors.New("invalid user password")
-	ErrShortPassword       = errors.New("password must be at least 8 characters long")
-	ErrLongPassword        = errors.New("password must be at most 64 characters long")
-	ErrNoUpperCase         = errors.New("password must contain at least one uppercase letter")
-	ErrNoLowerCase         = errors.New("password must contain at least one lowercase letter")
-	ErrNoDigit             = errors.New("password must contain at least one digit")
-	ErrNoSpecialChar       = errors.New("password must contain at least one special character")
+	ErrInvalidUserPassword = apperror.BadRequest("password_required", "invalid user password")
+	ErrShortPassword       = apperror.BadRequest("password_too_short", "password must be at least 8 characters long")
+	ErrLongPassword        = apperror.BadRequest("password_too_long", "password must be at most 64 characters long")
+	ErrNoUpperCase         = apperror.BadRequest("password_missing_uppercase", "password must contain at least one uppercase letter")
+	ErrNoLowerCase         = apperror.BadRequest("password_missing_lowercase", "password must contain at least one lowercase letter")
+	ErrNoDigit             = apperror.BadRequest("password_missing_digit", "password must contain at least one digit")
+	ErrNoSpecialChar       = apperror.BadRequest("password_missing_special_character", "password must contain at least one special character")
 )
 
 var (
@@ -27,12 +34,12 @@ func ValidatePassword(password string) error {
 		return ErrInvalidUserPassword
 	}
 
-	if len(password) < 8 {
-		return ErrShortPassword
+	if len(password) < MinPasswordLength {
+		return ErrShortPassword.With("min", MinPasswordLength)
 	}
 
-	if len(password) > 64 {
-		return ErrLongPassword
+	if len(password) > MaxPasswordLength {
+		return ErrLongPassword.With("max", MaxPasswordLength)
 	}
 
 	if !upperCaseRegex.MatchString(password) {
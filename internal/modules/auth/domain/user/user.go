@@ -6,6 +6,11 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
 	ID            uuid.UUID        `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
 	Email         string           `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
@@ -15,11 +20,72 @@ type User struct {
 	PreferredLang string           `gorm:"type:varchar(5);default:'fr'" json:"preferredLang"`
 	Verification  UserVerification `gorm:"embedded" json:"-"`
 	Provider      string           `gorm:"default:'local'" json:"-"`
+	Role          string           `gorm:"type:varchar(16);default:'user';not null" json:"-"`
+	DateOfBirth   *time.Time       `gorm:"type:date;default:null" json:"-"`
+	Minor         MinorStatus      `gorm:"embedded" json:"-"`
+	Privacy       PrivacySettings  `gorm:"embedded" json:"-"`
+}
+
+// MinorStatus is set at registration from the date of birth. A minor's account is only fully verified
+// once a guardian has confirmed it.
+type MinorStatus struct {
+	IsMinor           bool       `gorm:"default:false" json:"-"`
+	GuardianEmail     string     `gorm:"type:varchar(255)" json:"-"`
+	GuardianConsentAt *time.Time `gorm:"default:null" json:"-"`
+	// GuardianConsentRequestedAt is when the consent request was last emailed to the guardian.
+	GuardianConsentRequestedAt *time.Time `gorm:"default:null" json:"-"`
+}
+
+// PrivacySettings are expressed as restrictions so their zero value, the default for adults, is also
+// the database default.
+type PrivacySettings struct {
+	RestrictAdultMessages bool `gorm:"default:false" json:"-"`
+	HideLocation          bool `gorm:"default:false" json:"-"`
 }
 
 type UserVerification struct {
 	IsVerified bool       `gorm:"boolean;default:false" json:"-"`
 	VerifiedAt *time.Time `gorm:"autoUpdateTime;default:null" json:"-"`
+	// DeletionWarnedAt is set once the "verify or lose your account" email has been sent.
+	DeletionWarnedAt *time.Time `gorm:"default:null" json:"-"`
+}
+
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// IsVerified is true once the email is verified and, for minors, a guardian has consented.
+func (u *User) IsVerified() bool {
+	return u.Verification.IsVerified && !u.AwaitingGuardianConsent()
+}
+
+func (u *User) AwaitingGuardianConsent() bool {
+	return u.Minor.IsMinor && u.Minor.GuardianConsentAt == nil
+}
+
+// SetDateOfBirth marks the user as a minor when younger than minorAge at the given time, and then
+// restricts direct messages from adults and location visibility by default.
+func (u *User) SetDateOfBirth(dateOfBirth time.Time, minorAge int, guardianEmail string, at time.Time) {
+	u.DateOfBirth = &dateOfBirth
+	u.Minor = MinorStatus{}
+
+	if AgeAt(dateOfBirth, at) < minorAge {
+		u.Minor = MinorStatus{IsMinor: true, GuardianEmail: guardianEmail}
+		u.Privacy = PrivacySettings{RestrictAdultMessages: true, HideLocation: true}
+	}
+}
+
+func (u *User) ConfirmGuardianConsent(at time.Time) {
+	u.Minor.GuardianConsentAt = &at
+}
+
+// AgeAt returns the age in completed years.
+func AgeAt(dateOfBirth time.Time, at time.Time) int {
+	age := at.Year() - dateOfBirth.Year()
+	if at.Month() < dateOfBirth.Month() || (at.Month() == dateOfBirth.Month() && at.Day() < dateOfBirth.Day()) {
+		age--
+	}
+	return age
 }
 
 func CreateUser(email string, password string, preferredLang string, provider string) (*User, error) {
@@ -35,5 +101,6 @@ func CreateUser(email string, password string, preferredLang string, provider st
 			VerifiedAt: nil,
 		},
 		Provider: provider,
+		Role:     RoleUser,
 	}, nil
 }
@@ -1,10 +1,23 @@
 package user
 
-import "github.com/google/uuid"
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type UserRepository interface {
-	Create(user *User) error
-	FindByEmail(email string) (*User, error)
-	FindByID(id uuid.UUID) (*User, error)
-	Update(user *User) error
+	Create(ctx context.Context, user *User) error
+	FindByEmail(ctx context.Context, email string) (*User, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*User, error)
+	Update(ctx context.Context, user *User) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindUnverifiedToWarn(ctx context.Context, createdBefore time.Time, limit int) ([]*User, error)
+	FindUnverifiedToPurge(ctx context.Context, createdBefore time.Time, warnedBefore time.Time, limit int) ([]*User, error)
+	MarkDeletionWarned(ctx context.Context, id uuid.UUID, warnedAt time.Time) error
+	MarkGuardianConsent(ctx context.Context, id uuid.UUID, consentAt time.Time) error
+	// MarkGuardianConsentRequested records a consent request at requestedAt unless one was already made
+	// after notBefore, in which case it returns ErrGuardianConsentRequestedRecently.
+	MarkGuardianConsentRequested(ctx context.Context, id uuid.UUID, requestedAt time.Time, notBefore time.Time) error
 }
@@ -0,0 +1,17 @@
+package userinfra
+
+import (
+	"embed"
+	"io/fs"
+
+	"jamlink-backend/internal/infra/db/migrate"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrations returns the SQL migrations of the auth module.
+func Migrations() migrate.Source {
+	files, _ := fs.Sub(migrations, "migrations")
+	return migrate.Source{Module: "auth", Dir: "internal/modules/auth/infra/migrations", Files: files}
+}
@@ -0,0 +1,16 @@
+-- Schema of the users table at the baseline release, as gorm's AutoMigrate created it: IF NOT EXISTS
+-- lets databases created that way adopt it. Later columns are added by 20261019000011_add_users_columns,
+-- so this script must not change.
+CREATE TABLE IF NOT EXISTS users (
+	id uuid DEFAULT gen_random_uuid(),
+	email varchar(255) NOT NULL,
+	password varchar(255) NOT NULL,
+	created_at timestamptz,
+	updated_at timestamptz,
+	preferred_lang varchar(5) DEFAULT 'fr',
+	is_verified boolean DEFAULT false,
+	verified_at timestamptz DEFAULT null,
+	provider text DEFAULT 'local',
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
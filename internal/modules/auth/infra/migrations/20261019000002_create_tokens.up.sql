@@ -0,0 +1,12 @@
+-- Schema of the tokens table at the baseline release, as gorm's AutoMigrate created it: IF NOT EXISTS
+-- lets databases created that way adopt it, so this script must not change.
+CREATE TABLE IF NOT EXISTS tokens (
+	id uuid DEFAULT gen_random_uuid(),
+	user_id uuid NOT NULL,
+	token text NOT NULL,
+	expires_at timestamptz NOT NULL,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tokens_token ON tokens (token);
+CREATE INDEX IF NOT EXISTS idx_tokens_user_id ON tokens (user_id);
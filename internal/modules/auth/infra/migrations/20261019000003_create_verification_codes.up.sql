@@ -0,0 +1,12 @@
+-- Baseline of a schema previously created by gorm's AutoMigrate: IF NOT EXISTS lets databases
+-- created that way adopt it.
+CREATE TABLE IF NOT EXISTS verification_codes (
+	id uuid DEFAULT gen_random_uuid(),
+	user_id uuid NOT NULL,
+	code_hash varchar(64) NOT NULL,
+	attempts bigint NOT NULL DEFAULT 0,
+	expires_at timestamptz NOT NULL,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_verification_codes_user_id ON verification_codes (user_id);
@@ -0,0 +1,15 @@
+-- Baseline of a schema previously created by gorm's AutoMigrate: IF NOT EXISTS lets databases
+-- created that way adopt it.
+CREATE TABLE IF NOT EXISTS known_devices (
+	id uuid DEFAULT gen_random_uuid(),
+	user_id uuid NOT NULL,
+	fingerprint varchar(64) NOT NULL,
+	description varchar(255),
+	ip_prefix varchar(64),
+	country varchar(64),
+	city varchar(128),
+	last_seen_at timestamptz NOT NULL,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_known_device_user_fingerprint ON known_devices (user_id, fingerprint);
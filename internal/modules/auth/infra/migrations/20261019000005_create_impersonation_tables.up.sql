@@ -0,0 +1,27 @@
+-- Baseline of a schema previously created by gorm's AutoMigrate: IF NOT EXISTS lets databases
+-- created that way adopt it.
+CREATE TABLE IF NOT EXISTS impersonation_sessions (
+	id uuid DEFAULT gen_random_uuid(),
+	actor_id uuid NOT NULL,
+	user_id uuid NOT NULL,
+	reason varchar(255) NOT NULL,
+	expires_at timestamptz NOT NULL,
+	notified_at timestamptz,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_impersonation_sessions_user_id ON impersonation_sessions (user_id);
+CREATE INDEX IF NOT EXISTS idx_impersonation_sessions_actor_id ON impersonation_sessions (actor_id);
+CREATE INDEX IF NOT EXISTS idx_impersonation_sessions_expires_at ON impersonation_sessions (expires_at);
+
+CREATE TABLE IF NOT EXISTS impersonation_audit_entries (
+	id uuid DEFAULT gen_random_uuid(),
+	session_id uuid NOT NULL,
+	method varchar(10) NOT NULL,
+	path varchar(255) NOT NULL,
+	status bigint NOT NULL,
+	ip varchar(64),
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_impersonation_audit_entries_session_id ON impersonation_audit_entries (session_id);
@@ -0,0 +1,19 @@
+-- Baseline of a schema previously created by gorm's AutoMigrate: IF NOT EXISTS lets databases
+-- created that way adopt it.
+CREATE TABLE IF NOT EXISTS device_authorizations (
+	id uuid DEFAULT gen_random_uuid(),
+	device_code_hash varchar(64) NOT NULL,
+	user_code varchar(16) NOT NULL,
+	client_id varchar(100) NOT NULL,
+	status varchar(20) NOT NULL DEFAULT 'pending',
+	user_id uuid,
+	decided_at timestamptz,
+	"interval" bigint NOT NULL,
+	last_polled_at timestamptz,
+	expires_at timestamptz NOT NULL,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_device_authorizations_user_code ON device_authorizations (user_code);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_device_authorizations_device_code_hash ON device_authorizations (device_code_hash);
+CREATE INDEX IF NOT EXISTS idx_device_authorizations_expires_at ON device_authorizations (expires_at);
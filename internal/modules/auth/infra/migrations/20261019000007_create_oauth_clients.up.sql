@@ -0,0 +1,12 @@
+-- Baseline of a schema previously created by gorm's AutoMigrate: IF NOT EXISTS lets databases
+-- created that way adopt it.
+CREATE TABLE IF NOT EXISTS oauth_clients (
+	id uuid DEFAULT gen_random_uuid(),
+	name varchar(100) NOT NULL,
+	secret_hash varchar(255) NOT NULL,
+	scopes text NOT NULL,
+	redirect_uris text NOT NULL DEFAULT '',
+	created_by uuid NOT NULL,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
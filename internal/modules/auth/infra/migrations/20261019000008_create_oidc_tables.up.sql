@@ -0,0 +1,30 @@
+-- Baseline of a schema previously created by gorm's AutoMigrate: IF NOT EXISTS lets databases
+-- created that way adopt it.
+CREATE TABLE IF NOT EXISTS oauth_authorization_codes (
+	id uuid DEFAULT gen_random_uuid(),
+	code_hash varchar(64) NOT NULL,
+	client_id uuid NOT NULL,
+	user_id uuid NOT NULL,
+	redirect_uri text NOT NULL,
+	scopes text NOT NULL,
+	nonce varchar(255),
+	code_challenge varchar(128) NOT NULL,
+	auth_time timestamptz NOT NULL,
+	auth_methods varchar(255),
+	expires_at timestamptz NOT NULL,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_oauth_authorization_codes_code_hash ON oauth_authorization_codes (code_hash);
+CREATE INDEX IF NOT EXISTS idx_oauth_authorization_codes_expires_at ON oauth_authorization_codes (expires_at);
+
+CREATE TABLE IF NOT EXISTS oauth_grants (
+	id uuid DEFAULT gen_random_uuid(),
+	user_id uuid NOT NULL,
+	client_id uuid NOT NULL,
+	scopes text NOT NULL,
+	created_at timestamptz,
+	updated_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_oauth_grant_user_client ON oauth_grants (user_id, client_id);
@@ -0,0 +1,8 @@
+ALTER TABLE users DROP COLUMN IF EXISTS hide_location;
+ALTER TABLE users DROP COLUMN IF EXISTS restrict_adult_messages;
+ALTER TABLE users DROP COLUMN IF EXISTS guardian_consent_at;
+ALTER TABLE users DROP COLUMN IF EXISTS guardian_email;
+ALTER TABLE users DROP COLUMN IF EXISTS is_minor;
+ALTER TABLE users DROP COLUMN IF EXISTS date_of_birth;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
+ALTER TABLE users DROP COLUMN IF EXISTS deletion_warned_at;
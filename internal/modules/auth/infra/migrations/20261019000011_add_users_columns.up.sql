@@ -0,0 +1,10 @@
+-- Columns added to users since the baseline release. IF NOT EXISTS keeps the script safe on databases
+-- that gorm's AutoMigrate already brought up to date.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_warned_at timestamptz DEFAULT null;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role varchar(16) NOT NULL DEFAULT 'user';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS date_of_birth date DEFAULT null;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_minor boolean DEFAULT false;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS guardian_email varchar(255);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS guardian_consent_at timestamptz DEFAULT null;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS restrict_adult_messages boolean DEFAULT false;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS hide_location boolean DEFAULT false;
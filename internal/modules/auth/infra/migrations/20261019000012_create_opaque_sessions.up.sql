@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS opaque_sessions (
+	id uuid DEFAULT gen_random_uuid(),
+	token_hash varchar(64) NOT NULL,
+	user_id uuid,
+	claims jsonb NOT NULL,
+	expires_at timestamptz NOT NULL,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_opaque_sessions_token_hash ON opaque_sessions (token_hash);
+CREATE INDEX IF NOT EXISTS idx_opaque_sessions_user_id ON opaque_sessions (user_id);
+CREATE INDEX IF NOT EXISTS idx_opaque_sessions_expires_at ON opaque_sessions (expires_at);
@@ -0,0 +1,7 @@
+ALTER TABLE oauth_grants DROP CONSTRAINT IF EXISTS fk_oauth_grants_user;
+ALTER TABLE oauth_authorization_codes DROP CONSTRAINT IF EXISTS fk_oauth_authorization_codes_user;
+ALTER TABLE device_authorizations DROP CONSTRAINT IF EXISTS fk_device_authorizations_user;
+ALTER TABLE known_devices DROP CONSTRAINT IF EXISTS fk_known_devices_user;
+ALTER TABLE verification_codes DROP CONSTRAINT IF EXISTS fk_verification_codes_user;
+ALTER TABLE opaque_sessions DROP CONSTRAINT IF EXISTS fk_opaque_sessions_user;
+ALTER TABLE tokens DROP CONSTRAINT IF EXISTS fk_tokens_user;
@@ -0,0 +1,17 @@
+-- Deleting a user deletes what only makes sense with the account. Rows left behind by deletions made
+-- before these keys are removed first. The audit trail of impersonations is kept.
+DELETE FROM tokens WHERE user_id NOT IN (SELECT id FROM users);
+DELETE FROM opaque_sessions WHERE user_id IS NOT NULL AND user_id NOT IN (SELECT id FROM users);
+DELETE FROM verification_codes WHERE user_id NOT IN (SELECT id FROM users);
+DELETE FROM known_devices WHERE user_id NOT IN (SELECT id FROM users);
+DELETE FROM device_authorizations WHERE user_id IS NOT NULL AND user_id NOT IN (SELECT id FROM users);
+DELETE FROM oauth_authorization_codes WHERE user_id NOT IN (SELECT id FROM users);
+DELETE FROM oauth_grants WHERE user_id NOT IN (SELECT id FROM users);
+
+ALTER TABLE tokens ADD CONSTRAINT fk_tokens_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+ALTER TABLE opaque_sessions ADD CONSTRAINT fk_opaque_sessions_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+ALTER TABLE verification_codes ADD CONSTRAINT fk_verification_codes_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+ALTER TABLE known_devices ADD CONSTRAINT fk_known_devices_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+ALTER TABLE device_authorizations ADD CONSTRAINT fk_device_authorizations_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+ALTER TABLE oauth_authorization_codes ADD CONSTRAINT fk_oauth_authorization_codes_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+ALTER TABLE oauth_grants ADD CONSTRAINT fk_oauth_grants_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
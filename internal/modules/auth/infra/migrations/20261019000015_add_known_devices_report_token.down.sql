@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_known_devices_report_token_hash;
+ALTER TABLE known_devices DROP COLUMN IF EXISTS report_expires_at;
+ALTER TABLE known_devices DROP COLUMN IF EXISTS report_token_hash;
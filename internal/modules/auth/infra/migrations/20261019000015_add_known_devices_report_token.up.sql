@@ -0,0 +1,6 @@
+-- The "this wasn't me" link of new sign-in emails is a single-use random value kept hashed on the device
+-- it reports, rather than a signed token stored in tokens. Links sent before stop working and their rows
+-- in tokens are purged when they expire.
+ALTER TABLE known_devices ADD COLUMN IF NOT EXISTS report_token_hash varchar(64) NOT NULL DEFAULT '';
+ALTER TABLE known_devices ADD COLUMN IF NOT EXISTS report_expires_at timestamptz;
+CREATE INDEX IF NOT EXISTS idx_known_devices_report_token_hash ON known_devices (report_token_hash) WHERE report_token_hash <> '';
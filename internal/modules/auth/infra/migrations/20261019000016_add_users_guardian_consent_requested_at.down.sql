@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN IF EXISTS guardian_consent_requested_at;
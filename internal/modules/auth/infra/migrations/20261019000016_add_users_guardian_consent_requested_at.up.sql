@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS guardian_consent_requested_at timestamptz DEFAULT null;
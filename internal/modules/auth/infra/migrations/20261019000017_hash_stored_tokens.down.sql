@@ -0,0 +1,2 @@
+-- Hashed tokens cannot be turned back into tokens.
+DELETE FROM tokens;
@@ -0,0 +1,4 @@
+-- tokens.token now holds the keyed hash of each token instead of the token itself. Rows stored before
+-- cannot be hashed without the secret, so they are removed: sessions have to sign in again and pending
+-- password reset links have to be requested again.
+DELETE FROM tokens;
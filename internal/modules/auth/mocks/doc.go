@@ -0,0 +1,4 @@
+// Package mocks holds the test doubles of the auth module. Methods take the context of the interfaces
+// they implement but leave it out of the recorded arguments, so that expectations only list the values
+// a test cares about.
+package mocks
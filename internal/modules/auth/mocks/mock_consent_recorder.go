@@ -0,0 +1,22 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockConsentRecorder struct {
+	mock.Mock
+}
+
+func (m *MockConsentRecorder) Check(_ context.Context, termsVersion, privacyVersion string) error {
+	args := m.Called(termsVersion, privacyVersion)
+	return args.Error(0)
+}
+
+func (m *MockConsentRecorder) Record(_ context.Context, userID uuid.UUID, termsVersion, privacyVersion string, marketingOptIn bool, ip, userAgent string) error {
+	args := m.Called(userID, termsVersion, privacyVersion, marketingOptIn, ip, userAgent)
+	return args.Error(0)
+}
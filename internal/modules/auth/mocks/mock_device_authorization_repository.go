@@ -0,0 +1,57 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/auth/domain/deviceauth"
+)
+
+type MockDeviceAuthorizationRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeviceAuthorizationRepository) Create(_ context.Context, authorization *deviceauth.DeviceAuthorization) error {
+	args := m.Called(authorization)
+	return args.Error(0)
+}
+
+func (m *MockDeviceAuthorizationRepository) FindByDeviceCodeHash(_ context.Context, deviceCodeHash string) (*deviceauth.DeviceAuthorization, error) {
+	args := m.Called(deviceCodeHash)
+	authorization := args.Get(0)
+	if authorization == nil {
+		return nil, args.Error(1)
+	}
+	return authorization.(*deviceauth.DeviceAuthorization), args.Error(1)
+}
+
+func (m *MockDeviceAuthorizationRepository) FindByUserCode(_ context.Context, userCode string) (*deviceauth.DeviceAuthorization, error) {
+	args := m.Called(userCode)
+	authorization := args.Get(0)
+	if authorization == nil {
+		return nil, args.Error(1)
+	}
+	return authorization.(*deviceauth.DeviceAuthorization), args.Error(1)
+}
+
+func (m *MockDeviceAuthorizationRepository) Decide(_ context.Context, id uuid.UUID, status deviceauth.Status, userID uuid.UUID, at time.Time) error {
+	args := m.Called(id, status, userID, at)
+	return args.Error(0)
+}
+
+func (m *MockDeviceAuthorizationRepository) RecordPoll(_ context.Context, id uuid.UUID, at time.Time, interval int) error {
+	args := m.Called(id, at, interval)
+	return args.Error(0)
+}
+
+func (m *MockDeviceAuthorizationRepository) Consume(_ context.Context, id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockDeviceAuthorizationRepository) Delete(_ context.Context, id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
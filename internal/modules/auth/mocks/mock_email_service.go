@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/stretchr/testify/mock"
 	"jamlink-backend/internal/shared/email"
 )
@@ -9,7 +11,7 @@ type MockEmailService struct {
 	mock.Mock
 }
 
-func (m *MockEmailService) Send(to string, template email.TemplateType, lang string, data map[string]string) error {
+func (m *MockEmailService) Send(_ context.Context, to string, template email.TemplateType, lang string, data map[string]string) error {
 	args := m.Called(to, template, lang, data)
 	return args.Error(0)
 }
@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/shared/fingerprint"
+)
+
+type MockFingerprinter struct {
+	mock.Mock
+}
+
+func (m *MockFingerprinter) Fingerprint(login fingerprint.LoginContext) fingerprint.Fingerprint {
+	args := m.Called(login)
+	return args.Get(0).(fingerprint.Fingerprint)
+}
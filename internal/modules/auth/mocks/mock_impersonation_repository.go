@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/auth/domain/impersonation"
+)
+
+type MockImpersonationRepository struct {
+	mock.Mock
+}
+
+func (m *MockImpersonationRepository) Create(_ context.Context, session *impersonation.Session) error {
+	args := m.Called(session)
+	return args.Error(0)
+}
+
+func (m *MockImpersonationRepository) FindByID(_ context.Context, id uuid.UUID) (*impersonation.Session, error) {
+	args := m.Called(id)
+	session := args.Get(0)
+	if session == nil {
+		return nil, args.Error(1)
+	}
+	return session.(*impersonation.Session), args.Error(1)
+}
+
+func (m *MockImpersonationRepository) AppendAudit(_ context.Context, entry *impersonation.AuditEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockImpersonationRepository) CountAudit(_ context.Context, sessionID uuid.UUID) (int64, error) {
+	args := m.Called(sessionID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockImpersonationRepository) FindEndedUnnotified(_ context.Context, now time.Time, limit int) ([]*impersonation.Session, error) {
+	args := m.Called(now, limit)
+	sessions := args.Get(0)
+	if sessions == nil {
+		return nil, args.Error(1)
+	}
+	return sessions.([]*impersonation.Session), args.Error(1)
+}
+
+func (m *MockImpersonationRepository) MarkNotified(_ context.Context, id uuid.UUID, at time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/auth/domain/device"
+)
+
+type MockKnownDeviceRepository struct {
+	mock.Mock
+}
+
+func (m *MockKnownDeviceRepository) Create(_ context.Context, knownDevice *device.KnownDevice) error {
+	args := m.Called(knownDevice)
+	return args.Error(0)
+}
+
+func (m *MockKnownDeviceRepository) FindByFingerprint(_ context.Context, userID uuid.UUID, fingerprint string) (*device.KnownDevice, error) {
+	args := m.Called(userID, fingerprint)
+	foundDevice := args.Get(0)
+	if foundDevice == nil {
+		return nil, args.Error(1)
+	}
+	return foundDevice.(*device.KnownDevice), args.Error(1)
+}
+
+func (m *MockKnownDeviceRepository) CountByUserID(_ context.Context, userID uuid.UUID) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockKnownDeviceRepository) Touch(_ context.Context, id uuid.UUID, seenAt time.Time) error {
+	args := m.Called(id, seenAt)
+	return args.Error(0)
+}
+
+func (m *MockKnownDeviceRepository) DeleteByUserID(_ context.Context, userID uuid.UUID) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockKnownDeviceRepository) ConsumeReportToken(_ context.Context, tokenHash string, now time.Time) (uuid.UUID, error) {
+	args := m.Called(tokenHash, now)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
@@ -0,0 +1,27 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/auth/domain/oauthclient"
+)
+
+type MockOAuthClientRepository struct {
+	mock.Mock
+}
+
+func (m *MockOAuthClientRepository) Create(_ context.Context, client *oauthclient.Client) error {
+	args := m.Called(client)
+	return args.Error(0)
+}
+
+func (m *MockOAuthClientRepository) FindByID(_ context.Context, id uuid.UUID) (*oauthclient.Client, error) {
+	args := m.Called(id)
+	client := args.Get(0)
+	if client == nil {
+		return nil, args.Error(1)
+	}
+	return client.(*oauthclient.Client), args.Error(1)
+}
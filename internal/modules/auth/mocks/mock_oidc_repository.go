@@ -0,0 +1,59 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/auth/domain/oidc"
+)
+
+type MockAuthorizationCodeRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuthorizationCodeRepository) Create(_ context.Context, code *oidc.AuthorizationCode) error {
+	args := m.Called(code)
+	return args.Error(0)
+}
+
+func (m *MockAuthorizationCodeRepository) Consume(_ context.Context, codeHash string) (*oidc.AuthorizationCode, error) {
+	args := m.Called(codeHash)
+	code := args.Get(0)
+	if code == nil {
+		return nil, args.Error(1)
+	}
+	return code.(*oidc.AuthorizationCode), args.Error(1)
+}
+
+type MockGrantRepository struct {
+	mock.Mock
+}
+
+func (m *MockGrantRepository) Save(_ context.Context, grant *oidc.Grant) error {
+	args := m.Called(grant)
+	return args.Error(0)
+}
+
+func (m *MockGrantRepository) Find(_ context.Context, userID, clientID uuid.UUID) (*oidc.Grant, error) {
+	args := m.Called(userID, clientID)
+	grant := args.Get(0)
+	if grant == nil {
+		return nil, args.Error(1)
+	}
+	return grant.(*oidc.Grant), args.Error(1)
+}
+
+func (m *MockGrantRepository) FindByUserID(_ context.Context, userID uuid.UUID) ([]*oidc.Grant, error) {
+	args := m.Called(userID)
+	grants := args.Get(0)
+	if grants == nil {
+		return nil, args.Error(1)
+	}
+	return grants.([]*oidc.Grant), args.Error(1)
+}
+
+func (m *MockGrantRepository) Delete(_ context.Context, userID, clientID uuid.UUID) error {
+	args := m.Called(userID, clientID)
+	return args.Error(0)
+}
@@ -0,0 +1,23 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/invitation/domain/invitation"
+)
+
+type MockRegistrationGate struct {
+	mock.Mock
+}
+
+func (m *MockRegistrationGate) Check(_ context.Context, inviteCode string) error {
+	args := m.Called(inviteCode)
+	return args.Error(0)
+}
+
+func (m *MockRegistrationGate) Redeem(_ context.Context, _ invitation.InvitationRepository, inviteCode string, userID uuid.UUID) error {
+	args := m.Called(inviteCode, userID)
+	return args.Error(0)
+}
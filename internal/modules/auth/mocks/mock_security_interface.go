@@ -1,9 +1,12 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/shared/security"
 	"time"
 )
 
@@ -11,32 +14,52 @@ type MockSecurityService struct {
 	mock.Mock
 }
 
-func (m *MockSecurityService) HashPassword(password string) (string, error) {
+func (m *MockSecurityService) HashPassword(_ context.Context, password string) (string, error) {
 	args := m.Called(password)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockSecurityService) CheckPassword(password, hash string) bool {
+func (m *MockSecurityService) CheckPassword(_ context.Context, password, hash string) bool {
 	args := m.Called(password, hash)
 	return args.Bool(0)
 }
 
-func (m *MockSecurityService) GenerateJWT(id *uuid.UUID, email *string, duration time.Duration, tokenType string, isVerified bool) (string, error) {
-	args := m.Called(id, email, duration, tokenType, isVerified)
+func (m *MockSecurityService) GenerateJWT(_ context.Context, id *uuid.UUID, email *string, duration time.Duration, tokenType string, isVerified bool, auth *security.AuthContext, opts ...security.ClaimOption) (string, error) {
+	// opts only add optional claims and are left out of the recorded arguments.
+	args := m.Called(id, email, duration, tokenType, isVerified, auth)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockSecurityService) ValidateJWT(tokenString string) (jwt.MapClaims, error) {
+func (m *MockSecurityService) ValidateJWT(_ context.Context, tokenString string) (jwt.MapClaims, error) {
 	args := m.Called(tokenString)
 	return args.Get(0).(jwt.MapClaims), args.Error(1)
 }
 
-func (m *MockSecurityService) GetJWTInfo(token string) (uuid.UUID, error) {
+func (m *MockSecurityService) GetJWTInfo(_ context.Context, token string) (uuid.UUID, error) {
 	args := m.Called(token)
 	return args.Get(0).(uuid.UUID), args.Error(1)
 }
 
+func (m *MockSecurityService) GetJWTAuthContext(_ context.Context, token string) (*security.AuthContext, error) {
+	args := m.Called(token)
+	auth := args.Get(0)
+	if auth == nil {
+		return nil, args.Error(1)
+	}
+	return auth.(*security.AuthContext), args.Error(1)
+}
+
 func (m *MockSecurityService) GenerateSecureRandomString(n int) (string, error) {
 	args := m.Called(n)
 	return args.Get(0).(string), args.Error(1)
 }
+
+func (m *MockSecurityService) GenerateNumericCode(digits int) (string, error) {
+	args := m.Called(digits)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockSecurityService) HashOTP(code string) string {
+	args := m.Called(code)
+	return args.String(0)
+}
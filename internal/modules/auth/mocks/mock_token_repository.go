@@ -1,6 +1,9 @@
 package mocks
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 	tokenDomain "jamlink-backend/internal/modules/auth/domain/token"
@@ -10,7 +13,7 @@ type MockTokenRepository struct {
 	mock.Mock
 }
 
-func (m *MockTokenRepository) FindByToken(id string) (*tokenDomain.Token, error) {
+func (m *MockTokenRepository) FindByToken(_ context.Context, id string) (*tokenDomain.Token, error) {
 	args := m.Called(id)
 	foundUser := args.Get(0)
 	if foundUser == nil {
@@ -19,17 +22,33 @@ func (m *MockTokenRepository) FindByToken(id string) (*tokenDomain.Token, error)
 	return foundUser.(*tokenDomain.Token), args.Error(1)
 }
 
-func (m *MockTokenRepository) Create(token *tokenDomain.Token) error {
+func (m *MockTokenRepository) Create(_ context.Context, token *tokenDomain.Token) error {
 	args := m.Called(token)
 	return args.Error(0)
 }
 
-func (m *MockTokenRepository) DeleteByID(userID uuid.UUID) error {
+func (m *MockTokenRepository) DeleteByID(_ context.Context, userID uuid.UUID) error {
 	args := m.Called(userID)
 	return args.Error(0)
 }
 
-func (m *MockTokenRepository) DeleteUserTokens(userID uuid.UUID) error {
+func (m *MockTokenRepository) DeleteUserTokens(_ context.Context, userID uuid.UUID) error {
 	args := m.Called(userID)
 	return args.Error(0)
 }
+
+func (m *MockTokenRepository) DeleteExpired(_ context.Context, before time.Time, limit int) (int64, error) {
+	args := m.Called(before, limit)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTokenRepository) CreateSession(_ context.Context, tokenHash string, userID *uuid.UUID, claims []byte, expiresAt time.Time) error {
+	args := m.Called(tokenHash, userID, claims, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) FindSession(_ context.Context, tokenHash string) ([]byte, time.Time, error) {
+	args := m.Called(tokenHash)
+	claims, _ := args.Get(0).([]byte)
+	return claims, args.Get(1).(time.Time), args.Error(2)
+}
@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	"context"
+
+	tokenDomain "jamlink-backend/internal/modules/auth/domain/token"
+	"jamlink-backend/internal/modules/auth/domain/transaction"
+	"jamlink-backend/internal/modules/auth/domain/user"
+)
+
+// MockUnitOfWork hands the repository mocks it was built with to the function given to Do. It counts
+// the units that would have been committed or rolled back, since mocks cannot undo their calls.
+type MockUnitOfWork struct {
+	Repositories transaction.Repositories
+	Commits      int
+	Rollbacks    int
+}
+
+func NewMockUnitOfWork(users user.UserRepository, tokens tokenDomain.TokenRepository) *MockUnitOfWork {
+	return &MockUnitOfWork{Repositories: transaction.Repositories{Users: users, Tokens: tokens}}
+}
+
+func (m *MockUnitOfWork) Do(_ context.Context, fn func(repos transaction.Repositories) error) error {
+	if err := fn(m.Repositories); err != nil {
+		m.Rollbacks++
+		return err
+	}
+	m.Commits++
+	return nil
+}
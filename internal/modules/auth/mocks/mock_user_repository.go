@@ -1,6 +1,9 @@
 package mocks
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 	userDomain "jamlink-backend/internal/modules/auth/domain/user"
@@ -10,7 +13,7 @@ type MockUserRepository struct {
 	mock.Mock
 }
 
-func (m *MockUserRepository) FindByEmail(email string) (*userDomain.User, error) {
+func (m *MockUserRepository) FindByEmail(_ context.Context, email string) (*userDomain.User, error) {
 	args := m.Called(email)
 	foundUser := args.Get(0)
 	if foundUser == nil {
@@ -19,7 +22,7 @@ func (m *MockUserRepository) FindByEmail(email string) (*userDomain.User, error)
 	return foundUser.(*userDomain.User), args.Error(1)
 }
 
-func (m *MockUserRepository) FindByID(id uuid.UUID) (*userDomain.User, error) {
+func (m *MockUserRepository) FindByID(_ context.Context, id uuid.UUID) (*userDomain.User, error) {
 	args := m.Called(id)
 	foundUser := args.Get(0)
 	if foundUser == nil {
@@ -28,12 +31,50 @@ func (m *MockUserRepository) FindByID(id uuid.UUID) (*userDomain.User, error) {
 	return foundUser.(*userDomain.User), args.Error(1)
 }
 
-func (m *MockUserRepository) Create(user *userDomain.User) error {
+func (m *MockUserRepository) Create(_ context.Context, user *userDomain.User) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) Update(user *userDomain.User) error {
+func (m *MockUserRepository) Update(_ context.Context, user *userDomain.User) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
+
+func (m *MockUserRepository) Delete(_ context.Context, id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FindUnverifiedToWarn(_ context.Context, createdBefore time.Time, limit int) ([]*userDomain.User, error) {
+	args := m.Called(createdBefore, limit)
+	foundUsers := args.Get(0)
+	if foundUsers == nil {
+		return nil, args.Error(1)
+	}
+	return foundUsers.([]*userDomain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindUnverifiedToPurge(_ context.Context, createdBefore time.Time, warnedBefore time.Time, limit int) ([]*userDomain.User, error) {
+	args := m.Called(createdBefore, warnedBefore, limit)
+	foundUsers := args.Get(0)
+	if foundUsers == nil {
+		return nil, args.Error(1)
+	}
+	return foundUsers.([]*userDomain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) MarkDeletionWarned(_ context.Context, id uuid.UUID, warnedAt time.Time) error {
+	args := m.Called(id, warnedAt)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) MarkGuardianConsent(_ context.Context, id uuid.UUID, consentAt time.Time) error {
+	args := m.Called(id, consentAt)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) MarkGuardianConsentRequested(_ context.Context, id uuid.UUID, requestedAt time.Time, notBefore time.Time) error {
+	args := m.Called(id, requestedAt, notBefore)
+	return args.Error(0)
+}
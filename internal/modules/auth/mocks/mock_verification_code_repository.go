@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/auth/domain/otp"
+)
+
+type MockVerificationCodeRepository struct {
+	mock.Mock
+}
+
+func (m *MockVerificationCodeRepository) Create(_ context.Context, code *otp.VerificationCode) error {
+	args := m.Called(code)
+	return args.Error(0)
+}
+
+func (m *MockVerificationCodeRepository) FindLatestByUserID(_ context.Context, userID uuid.UUID) (*otp.VerificationCode, error) {
+	args := m.Called(userID)
+	foundCode := args.Get(0)
+	if foundCode == nil {
+		return nil, args.Error(1)
+	}
+	return foundCode.(*otp.VerificationCode), args.Error(1)
+}
+
+func (m *MockVerificationCodeRepository) CountCreatedSince(_ context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	args := m.Called(userID, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockVerificationCodeRepository) UseAttempt(_ context.Context, id uuid.UUID, maxAttempts int) error {
+	args := m.Called(id, maxAttempts)
+	return args.Error(0)
+}
+
+func (m *MockVerificationCodeRepository) DeleteByUserID(_ context.Context, userID uuid.UUID) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
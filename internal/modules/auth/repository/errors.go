@@ -0,0 +1,30 @@
+package userRepository
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// notFoundAs translates gorm's not-found error into the given domain error so use cases can tell a
+// missing row apart from a database failure without importing gorm.
+func notFoundAs(err error, domainErr error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domainErr
+	}
+	return err
+}
+
+// uniqueViolation is the Postgres error code of a duplicate key.
+const uniqueViolation = "23505"
+
+// duplicateAs translates a unique constraint violation into the given domain error, so that a race
+// between two inserts is reported like the check that lost it.
+func duplicateAs(err error, domainErr error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return domainErr
+	}
+	return err
+}
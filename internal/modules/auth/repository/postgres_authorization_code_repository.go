@@ -0,0 +1,35 @@
+package userRepository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"jamlink-backend/internal/modules/auth/domain/oidc"
+)
+
+type PostgresAuthorizationCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresAuthorizationCodeRepository(db *gorm.DB) *PostgresAuthorizationCodeRepository {
+	return &PostgresAuthorizationCodeRepository{db: db}
+}
+
+func (r *PostgresAuthorizationCodeRepository) Create(ctx context.Context, code *oidc.AuthorizationCode) error {
+	return r.db.WithContext(ctx).Create(code).Error
+}
+
+func (r *PostgresAuthorizationCodeRepository) Consume(ctx context.Context, codeHash string) (*oidc.AuthorizationCode, error) {
+	var codes []oidc.AuthorizationCode
+
+	result := r.db.WithContext(ctx).Clauses(clause.Returning{}).Where("code_hash = ?", codeHash).Delete(&codes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 || len(codes) == 0 {
+		return nil, oidc.ErrInvalidCode
+	}
+
+	return &codes[0], nil
+}
@@ -0,0 +1,78 @@
+package userRepository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"jamlink-backend/internal/modules/auth/domain/deviceauth"
+)
+
+type PostgresDeviceAuthorizationRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresDeviceAuthorizationRepository(db *gorm.DB) *PostgresDeviceAuthorizationRepository {
+	return &PostgresDeviceAuthorizationRepository{db: db}
+}
+
+func (r *PostgresDeviceAuthorizationRepository) Create(ctx context.Context, authorization *deviceauth.DeviceAuthorization) error {
+	return r.db.WithContext(ctx).Create(authorization).Error
+}
+
+func (r *PostgresDeviceAuthorizationRepository) FindByDeviceCodeHash(ctx context.Context, deviceCodeHash string) (*deviceauth.DeviceAuthorization, error) {
+	var authorization deviceauth.DeviceAuthorization
+
+	if err := r.db.WithContext(ctx).Where("device_code_hash = ?", deviceCodeHash).First(&authorization).Error; err != nil {
+		return nil, notFoundAs(err, deviceauth.ErrInvalidDeviceCode)
+	}
+
+	return &authorization, nil
+}
+
+func (r *PostgresDeviceAuthorizationRepository) FindByUserCode(ctx context.Context, userCode string) (*deviceauth.DeviceAuthorization, error) {
+	var authorization deviceauth.DeviceAuthorization
+
+	if err := r.db.WithContext(ctx).Where("user_code = ?", userCode).First(&authorization).Error; err != nil {
+		return nil, notFoundAs(err, deviceauth.ErrUserCodeNotFound)
+	}
+
+	return &authorization, nil
+}
+
+func (r *PostgresDeviceAuthorizationRepository) Decide(ctx context.Context, id uuid.UUID, status deviceauth.Status, userID uuid.UUID, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&deviceauth.DeviceAuthorization{}).
+		Where("id = ? AND status = ?", id, deviceauth.StatusPending).
+		UpdateColumns(map[string]interface{}{"status": status, "user_id": userID, "decided_at": at})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return deviceauth.ErrAlreadyDecided
+	}
+
+	return nil
+}
+
+func (r *PostgresDeviceAuthorizationRepository) RecordPoll(ctx context.Context, id uuid.UUID, at time.Time, interval int) error {
+	return r.db.WithContext(ctx).Model(&deviceauth.DeviceAuthorization{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"last_polled_at": at, "interval": interval}).Error
+}
+
+func (r *PostgresDeviceAuthorizationRepository) Consume(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND status = ?", id, deviceauth.StatusApproved).Delete(&deviceauth.DeviceAuthorization{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return deviceauth.ErrInvalidDeviceCode
+	}
+
+	return nil
+}
+
+func (r *PostgresDeviceAuthorizationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&deviceauth.DeviceAuthorization{}).Error
+}
@@ -0,0 +1,55 @@
+package userRepository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"jamlink-backend/internal/modules/auth/domain/oidc"
+)
+
+type PostgresGrantRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresGrantRepository(db *gorm.DB) *PostgresGrantRepository {
+	return &PostgresGrantRepository{db: db}
+}
+
+func (r *PostgresGrantRepository) Save(ctx context.Context, grant *oidc.Grant) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "client_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"scopes", "updated_at"}),
+	}).Create(grant).Error
+}
+
+func (r *PostgresGrantRepository) Find(ctx context.Context, userID, clientID uuid.UUID) (*oidc.Grant, error) {
+	var grant oidc.Grant
+
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND client_id = ?", userID, clientID).First(&grant).Error; err != nil {
+		return nil, notFoundAs(err, oidc.ErrGrantNotFound)
+	}
+
+	return &grant, nil
+}
+
+func (r *PostgresGrantRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*oidc.Grant, error) {
+	var grants []*oidc.Grant
+
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("updated_at DESC").Find(&grants).Error
+
+	return grants, err
+}
+
+func (r *PostgresGrantRepository) Delete(ctx context.Context, userID, clientID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("user_id = ? AND client_id = ?", userID, clientID).Delete(&oidc.Grant{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return oidc.ErrGrantNotFound
+	}
+
+	return nil
+}
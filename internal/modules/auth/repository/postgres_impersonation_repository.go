@@ -0,0 +1,59 @@
+package userRepository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"jamlink-backend/internal/modules/auth/domain/impersonation"
+)
+
+type PostgresImpersonationRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresImpersonationRepository(db *gorm.DB) *PostgresImpersonationRepository {
+	return &PostgresImpersonationRepository{db: db}
+}
+
+func (r *PostgresImpersonationRepository) Create(ctx context.Context, session *impersonation.Session) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+func (r *PostgresImpersonationRepository) FindByID(ctx context.Context, id uuid.UUID) (*impersonation.Session, error) {
+	var session impersonation.Session
+
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&session).Error; err != nil {
+		return nil, notFoundAs(err, impersonation.ErrSessionNotFound)
+	}
+
+	return &session, nil
+}
+
+func (r *PostgresImpersonationRepository) AppendAudit(ctx context.Context, entry *impersonation.AuditEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *PostgresImpersonationRepository) CountAudit(ctx context.Context, sessionID uuid.UUID) (int64, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).Model(&impersonation.AuditEntry{}).Where("session_id = ?", sessionID).Count(&count).Error
+
+	return count, err
+}
+
+func (r *PostgresImpersonationRepository) FindEndedUnnotified(ctx context.Context, now time.Time, limit int) ([]*impersonation.Session, error) {
+	var sessions []*impersonation.Session
+
+	err := r.db.WithContext(ctx).Where("expires_at < ? AND notified_at IS NULL", now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&sessions).Error
+
+	return sessions, err
+}
+
+func (r *PostgresImpersonationRepository) MarkNotified(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&impersonation.Session{}).Where("id = ?", id).UpdateColumn("notified_at", at).Error
+}
@@ -0,0 +1,65 @@
+package userRepository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"jamlink-backend/internal/modules/auth/domain/device"
+)
+
+type PostgresKnownDeviceRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresKnownDeviceRepository(db *gorm.DB) *PostgresKnownDeviceRepository {
+	return &PostgresKnownDeviceRepository{db: db}
+}
+
+func (r *PostgresKnownDeviceRepository) Create(ctx context.Context, knownDevice *device.KnownDevice) error {
+	return r.db.WithContext(ctx).Create(knownDevice).Error
+}
+
+func (r *PostgresKnownDeviceRepository) FindByFingerprint(ctx context.Context, userID uuid.UUID, fingerprint string) (*device.KnownDevice, error) {
+	var knownDevice device.KnownDevice
+
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND fingerprint = ?", userID, fingerprint).First(&knownDevice).Error; err != nil {
+		return nil, notFoundAs(err, device.ErrDeviceNotFound)
+	}
+
+	return &knownDevice, nil
+}
+
+func (r *PostgresKnownDeviceRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).Model(&device.KnownDevice{}).Where("user_id = ?", userID).Count(&count).Error
+
+	return count, err
+}
+
+func (r *PostgresKnownDeviceRepository) Touch(ctx context.Context, id uuid.UUID, seenAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&device.KnownDevice{}).Where("id = ?", id).UpdateColumn("last_seen_at", seenAt).Error
+}
+
+func (r *PostgresKnownDeviceRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&device.KnownDevice{}).Error
+}
+
+func (r *PostgresKnownDeviceRepository) ConsumeReportToken(ctx context.Context, tokenHash string, now time.Time) (uuid.UUID, error) {
+	var devices []device.KnownDevice
+
+	result := r.db.WithContext(ctx).Model(&devices).Clauses(clause.Returning{Columns: []clause.Column{{Name: "user_id"}}}).
+		Where("report_token_hash = ? AND report_expires_at > ?", tokenHash, now).
+		Updates(map[string]any{"report_token_hash": "", "report_expires_at": nil})
+	if result.Error != nil {
+		return uuid.Nil, result.Error
+	}
+	if result.RowsAffected == 0 || len(devices) == 0 {
+		return uuid.Nil, device.ErrInvalidReportToken
+	}
+
+	return devices[0].UserID, nil
+}
@@ -0,0 +1,31 @@
+package userRepository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"jamlink-backend/internal/modules/auth/domain/oauthclient"
+)
+
+type PostgresOAuthClientRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresOAuthClientRepository(db *gorm.DB) *PostgresOAuthClientRepository {
+	return &PostgresOAuthClientRepository{db: db}
+}
+
+func (r *PostgresOAuthClientRepository) Create(ctx context.Context, client *oauthclient.Client) error {
+	return r.db.WithContext(ctx).Create(client).Error
+}
+
+func (r *PostgresOAuthClientRepository) FindByID(ctx context.Context, id uuid.UUID) (*oauthclient.Client, error) {
+	var client oauthclient.Client
+
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&client).Error; err != nil {
+		return nil, notFoundAs(err, oauthclient.ErrClientNotFound)
+	}
+
+	return &client, nil
+}
@@ -1,6 +1,9 @@
 package userRepository
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 	tokenDomain "jamlink-backend/internal/modules/auth/domain/token"
@@ -14,24 +17,75 @@ func NewPostgresTokenRepository(db *gorm.DB) *PostgresTokenRepository {
 	return &PostgresTokenRepository{db: db}
 }
 
-func (r *PostgresTokenRepository) Create(token *tokenDomain.Token) error {
-	return r.db.Create(token).Error
+func (r *PostgresTokenRepository) Create(ctx context.Context, token *tokenDomain.Token) error {
+	return r.db.WithContext(ctx).Create(token).Error
 }
 
-func (r *PostgresTokenRepository) FindByToken(token string) (*tokenDomain.Token, error) {
+func (r *PostgresTokenRepository) FindByToken(ctx context.Context, tokenHash string) (*tokenDomain.Token, error) {
 	var t tokenDomain.Token
 
-	if err := r.db.Where("token  = ?", token).First(&t).Error; err != nil {
-		return nil, err
+	if err := r.db.WithContext(ctx).Where("token = ?", tokenHash).First(&t).Error; err != nil {
+		return nil, notFoundAs(err, tokenDomain.ErrTokenNotFound)
 	}
 
 	return &t, nil
 }
 
-func (r *PostgresTokenRepository) DeleteByID(id uuid.UUID) error {
-	return r.db.Where("id = ?", id).Delete(&tokenDomain.Token{}).Error
+// DeleteByID returns ErrTokenNotFound when the token is already gone, so that of two transactions
+// consuming the same token only the first succeeds.
+func (r *PostgresTokenRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&tokenDomain.Token{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return tokenDomain.ErrTokenNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresTokenRepository) DeleteUserTokens(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&tokenDomain.Token{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&tokenDomain.Session{}).Error
+	})
+}
+
+// DeleteExpired deletes expired tokens first, then expired sessions within what is left of limit.
+func (r *PostgresTokenRepository) DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error) {
+	batch := r.db.WithContext(ctx).Model(&tokenDomain.Token{}).Select("id").Where("expires_at < ?", before).Limit(limit)
+
+	result := r.db.WithContext(ctx).Where("id IN (?)", batch).Delete(&tokenDomain.Token{})
+	if result.Error != nil || result.RowsAffected >= int64(limit) {
+		return result.RowsAffected, result.Error
+	}
+
+	sessionBatch := r.db.WithContext(ctx).Model(&tokenDomain.Session{}).Select("id").Where("expires_at < ?", before).Limit(limit - int(result.RowsAffected))
+
+	sessions := r.db.WithContext(ctx).Where("id IN (?)", sessionBatch).Delete(&tokenDomain.Session{})
+
+	return result.RowsAffected + sessions.RowsAffected, sessions.Error
+}
+
+func (r *PostgresTokenRepository) CreateSession(ctx context.Context, tokenHash string, userID *uuid.UUID, claims []byte, expiresAt time.Time) error {
+	return r.db.WithContext(ctx).Create(&tokenDomain.Session{
+		ID:        uuid.New(),
+		TokenHash: tokenHash,
+		UserID:    userID,
+		Claims:    string(claims),
+		ExpiresAt: expiresAt,
+	}).Error
 }
 
-func (r *PostgresTokenRepository) DeleteUserTokens(userID uuid.UUID) error {
-	return r.db.Where("user_id = ?", userID).Delete(&tokenDomain.Token{}).Error
+func (r *PostgresTokenRepository) FindSession(ctx context.Context, tokenHash string) ([]byte, time.Time, error) {
+	var session tokenDomain.Session
+
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&session).Error; err != nil {
+		return nil, time.Time{}, notFoundAs(err, tokenDomain.ErrTokenNotFound)
+	}
+
+	return []byte(session.Claims), session.ExpiresAt, nil
 }
@@ -0,0 +1,30 @@
+package userRepository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"jamlink-backend/internal/modules/auth/domain/transaction"
+	invitationRepository "jamlink-backend/internal/modules/invitation/repository"
+)
+
+type PostgresUnitOfWork struct {
+	db *gorm.DB
+}
+
+func NewPostgresUnitOfWork(db *gorm.DB) *PostgresUnitOfWork {
+	return &PostgresUnitOfWork{db: db}
+}
+
+// Do builds the repositories on the transaction. Transactions they open themselves, such as
+// DeleteUserTokens' or the redemption of an invitation, become savepoints of this one.
+func (u *PostgresUnitOfWork) Do(ctx context.Context, fn func(repos transaction.Repositories) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(transaction.Repositories{
+			Users:        NewPostgresUserRepository(tx),
+			Tokens:       NewPostgresTokenRepository(tx),
+			KnownDevices: NewPostgresKnownDeviceRepository(tx),
+			Invitations:  invitationRepository.NewPostgresInvitationRepository(tx),
+		})
+	})
+}
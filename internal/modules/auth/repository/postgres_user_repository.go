@@ -1,6 +1,9 @@
 package userRepository
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"jamlink-backend/internal/modules/auth/domain/user"
@@ -14,30 +17,85 @@ func NewPostgresUserRepository(db *gorm.DB) *PostgresUserRepository {
 	return &PostgresUserRepository{db: db}
 }
 
-func (r *PostgresUserRepository) Create(user *user.User) error {
-	return r.db.Create(user).Error
+func (r *PostgresUserRepository) Create(ctx context.Context, newUser *user.User) error {
+	return duplicateAs(r.db.WithContext(ctx).Create(newUser).Error, user.ErrEmailAlreadyExists)
 }
 
-func (r *PostgresUserRepository) FindByEmail(email string) (*user.User, error) {
+func (r *PostgresUserRepository) FindByEmail(ctx context.Context, email string) (*user.User, error) {
 	var foundUser user.User
 
-	if err := r.db.Where("email  = ?", email).First(&foundUser).Error; err != nil {
-		return nil, err
+	if err := r.db.WithContext(ctx).Where("email  = ?", email).First(&foundUser).Error; err != nil {
+		return nil, notFoundAs(err, user.ErrUserNotFound)
 	}
 
 	return &foundUser, nil
 }
 
-func (r *PostgresUserRepository) FindByID(id uuid.UUID) (*user.User, error) {
+func (r *PostgresUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
 	var foundUser user.User
 
-	if err := r.db.Where("id = ?", id.String()).First(&foundUser).Error; err != nil {
-		return nil, err
+	if err := r.db.WithContext(ctx).Where("id = ?", id.String()).First(&foundUser).Error; err != nil {
+		return nil, notFoundAs(err, user.ErrUserNotFound)
 	}
 
 	return &foundUser, nil
 }
 
-func (r *PostgresUserRepository) Update(user *user.User) error {
-	return r.db.Save(user).Error
+func (r *PostgresUserRepository) Update(ctx context.Context, user *user.User) error {
+	return r.db.WithContext(ctx).Save(user).Error
+}
+
+func (r *PostgresUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&user.User{}).Error
+}
+
+// Only local accounts are considered: Google accounts cannot verify through the email link.
+func (r *PostgresUserRepository) FindUnverifiedToWarn(ctx context.Context, createdBefore time.Time, limit int) ([]*user.User, error) {
+	var users []*user.User
+
+	err := r.db.WithContext(ctx).
+		Where("is_verified = ? AND provider = ? AND deletion_warned_at IS NULL AND created_at < ?", false, "local", createdBefore).
+		Order("created_at").
+		Limit(limit).
+		Find(&users).Error
+
+	return users, err
+}
+
+func (r *PostgresUserRepository) FindUnverifiedToPurge(ctx context.Context, createdBefore time.Time, warnedBefore time.Time, limit int) ([]*user.User, error) {
+	var users []*user.User
+
+	err := r.db.WithContext(ctx).
+		Where("is_verified = ? AND provider = ? AND deletion_warned_at < ? AND created_at < ?", false, "local", warnedBefore, createdBefore).
+		Order("created_at").
+		Limit(limit).
+		Find(&users).Error
+
+	return users, err
+}
+
+// MarkDeletionWarned uses UpdateColumn so the autoUpdateTime on VerifiedAt is not triggered.
+func (r *PostgresUserRepository) MarkDeletionWarned(ctx context.Context, id uuid.UUID, warnedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&user.User{}).Where("id = ?", id).UpdateColumn("deletion_warned_at", warnedAt).Error
+}
+
+// MarkGuardianConsent uses UpdateColumn for the same reason as MarkDeletionWarned.
+func (r *PostgresUserRepository) MarkGuardianConsent(ctx context.Context, id uuid.UUID, consentAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&user.User{}).Where("id = ?", id).UpdateColumn("guardian_consent_at", consentAt).Error
+}
+
+// MarkGuardianConsentRequested checks and records the request in a single statement, so concurrent
+// requests cannot both get past the cooldown.
+func (r *PostgresUserRepository) MarkGuardianConsentRequested(ctx context.Context, id uuid.UUID, requestedAt time.Time, notBefore time.Time) error {
+	result := r.db.WithContext(ctx).Model(&user.User{}).
+		Where("id = ? AND (guardian_consent_requested_at IS NULL OR guardian_consent_requested_at <= ?)", id, notBefore).
+		UpdateColumn("guardian_consent_requested_at", requestedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return user.ErrGuardianConsentRequestedRecently
+	}
+
+	return nil
 }
@@ -0,0 +1,55 @@
+package userRepository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"jamlink-backend/internal/modules/auth/domain/otp"
+)
+
+type PostgresVerificationCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresVerificationCodeRepository(db *gorm.DB) *PostgresVerificationCodeRepository {
+	return &PostgresVerificationCodeRepository{db: db}
+}
+
+func (r *PostgresVerificationCodeRepository) Create(ctx context.Context, code *otp.VerificationCode) error {
+	return r.db.WithContext(ctx).Create(code).Error
+}
+
+func (r *PostgresVerificationCodeRepository) FindLatestByUserID(ctx context.Context, userID uuid.UUID) (*otp.VerificationCode, error) {
+	var code otp.VerificationCode
+
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").First(&code).Error; err != nil {
+		return nil, err
+	}
+
+	return &code, nil
+}
+
+func (r *PostgresVerificationCodeRepository) CountCreatedSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&otp.VerificationCode{}).Where("user_id = ? AND created_at >= ?", userID, since).Count(&count).Error
+	return count, err
+}
+
+func (r *PostgresVerificationCodeRepository) UseAttempt(ctx context.Context, id uuid.UUID, maxAttempts int) error {
+	result := r.db.WithContext(ctx).Model(&otp.VerificationCode{}).
+		Where("id = ? AND attempts < ?", id, maxAttempts).
+		UpdateColumn("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return otp.ErrTooManyAttempts
+	}
+	return nil
+}
+
+func (r *PostgresVerificationCodeRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&otp.VerificationCode{}).Error
+}
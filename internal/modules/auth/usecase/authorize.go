@@ -0,0 +1,151 @@
+package useCase
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"jamlink-backend/internal/modules/auth/domain/oauthclient"
+	"jamlink-backend/internal/modules/auth/domain/oidc"
+	"jamlink-backend/internal/shared/security"
+	"net/url"
+	"strings"
+)
+
+type AuthorizeUseCase struct {
+	clientRepo oauthclient.ClientRepository
+	grantRepo  oidc.GrantRepository
+	codeRepo   oidc.AuthorizationCodeRepository
+	security   security.SecurityService
+}
+
+// AuthorizeInput holds the parameters of an authorization request. The consent screen sends them
+// back with Approve set.
+type AuthorizeInput struct {
+	UserID              uuid.UUID             `form:"-" json:"-"`
+	Auth                *security.AuthContext `form:"-" json:"-"`
+	ResponseType        string                `form:"response_type" json:"response_type" example:"code"`
+	ClientID            string                `form:"client_id" json:"client_id" binding:"required"`
+	RedirectURI         string                `form:"redirect_uri" json:"redirect_uri" binding:"required" example:"https://booking.example.com/callback"`
+	Scope               string                `form:"scope" json:"scope" example:"openid email"`
+	State               string                `form:"state" json:"state"`
+	Nonce               string                `form:"nonce" json:"nonce"`
+	CodeChallenge       string                `form:"code_challenge" json:"code_challenge"`
+	CodeChallengeMethod string                `form:"code_challenge_method" json:"code_challenge_method" example:"S256"`
+	Approve             bool                  `form:"-" json:"approve"`
+}
+
+type AuthorizeOutput struct {
+	RedirectTo string `json:"redirect_to" example:"https://booking.example.com/callback?code=...&state=..."`
+}
+
+func NewAuthorizeUseCase(clientRepo oauthclient.ClientRepository, grantRepo oidc.GrantRepository, codeRepo oidc.AuthorizationCodeRepository, security security.SecurityService) *AuthorizeUseCase {
+	return &AuthorizeUseCase{clientRepo: clientRepo, grantRepo: grantRepo, codeRepo: codeRepo, security: security}
+}
+
+// ResolveClient checks the client and its redirect URI. Its errors must be shown to the user rather
+// than redirected, as the redirect URI cannot be trusted.
+func (uc *AuthorizeUseCase) ResolveClient(ctx context.Context, rawClientID, redirectURI string) (*oauthclient.Client, error) {
+	clientID, err := uuid.Parse(rawClientID)
+	if err != nil {
+		return nil, oidc.ErrUnknownClient
+	}
+
+	client, err := uc.clientRepo.FindByID(ctx, clientID)
+	if errors.Is(err, oauthclient.ErrClientNotFound) {
+		return nil, oidc.ErrUnknownClient
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !client.AllowsRedirectURI(redirectURI) {
+		return nil, oidc.ErrInvalidRedirectURI
+	}
+
+	return client, nil
+}
+
+// Execute issues an authorization code when the user already granted the requested scopes to the
+// client, or approves them now; otherwise it returns ErrConsentRequired.
+func (uc *AuthorizeUseCase) Execute(ctx context.Context, input AuthorizeInput) (*AuthorizeOutput, error) {
+	client, err := uc.ResolveClient(ctx, input.ClientID, input.RedirectURI)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.ResponseType != oidc.ResponseTypeCode {
+		return nil, oidc.ErrUnsupportedResponseType
+	}
+	if !oidc.ValidCodeChallenge(input.CodeChallenge, input.CodeChallengeMethod) {
+		return nil, oidc.ErrInvalidRequest
+	}
+
+	scopes := strings.Fields(input.Scope)
+	if !containsScope(scopes, oidc.ScopeOpenID) {
+		return nil, oidc.ErrInvalidScope
+	}
+	if _, err := client.GrantScopes(input.Scope); err != nil {
+		return nil, oidc.ErrInvalidScope
+	}
+
+	grant, err := uc.grantRepo.Find(ctx, input.UserID, client.ID)
+	if err != nil && !errors.Is(err, oidc.ErrGrantNotFound) {
+		return nil, err
+	}
+
+	if grant == nil || !grant.Covers(scopes) {
+		if !input.Approve {
+			return nil, oidc.ErrConsentRequired
+		}
+		if err := uc.grantRepo.Save(ctx, oidc.CreateGrant(input.UserID, client.ID, scopes, grant)); err != nil {
+			return nil, err
+		}
+	}
+
+	code, err := uc.security.GenerateSecureRandomString(32)
+	if err != nil {
+		return nil, err
+	}
+
+	auth := input.Auth
+	if auth == nil {
+		auth = &security.AuthContext{}
+	}
+
+	authorizationCode := oidc.CreateAuthorizationCode(uc.security.HashOTP(code), client.ID, input.UserID, input.RedirectURI, scopes, input.Nonce, input.CodeChallenge, auth.Time, auth.Methods)
+	if err := uc.codeRepo.Create(ctx, authorizationCode); err != nil {
+		return nil, err
+	}
+
+	return &AuthorizeOutput{
+		RedirectTo: AuthorizationRedirect(input.RedirectURI, url.Values{"code": {code}}, input.State),
+	}, nil
+}
+
+// AuthorizationRedirect adds params and the state of the request to the query of the redirect URI.
+func AuthorizationRedirect(redirectURI string, params url.Values, state string) string {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return redirectURI
+	}
+
+	query := u.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	if state != "" {
+		query.Set("state", state)
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+func containsScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package useCase
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/auth/domain/oauthclient"
+	"jamlink-backend/internal/modules/auth/domain/oidc"
+	"jamlink-backend/internal/modules/auth/mocks"
+	"jamlink-backend/internal/shared/security"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const (
+	testRedirectURI   = "https://partner.example.com/callback"
+	testCodeVerifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	testCodeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+)
+
+func newRelyingParty() *oauthclient.Client {
+	return &oauthclient.Client{ID: uuid.New(), Name: "Booking partner", SecretHash: "hashed-secret", Scopes: "openid email profile", RedirectURIs: testRedirectURI}
+}
+
+func newAuthorizeInput(client *oauthclient.Client, userID uuid.UUID) AuthorizeInput {
+	return AuthorizeInput{
+		UserID:              userID,
+		Auth:                &security.AuthContext{Time: time.Now(), Methods: []string{security.AuthMethodPassword}},
+		ResponseType:        oidc.ResponseTypeCode,
+		ClientID:            client.ID.String(),
+		RedirectURI:         testRedirectURI,
+		Scope:               "openid email",
+		State:               "af0ifjsldkj",
+		Nonce:               "n-0S6_WzA2Mj",
+		CodeChallenge:       testCodeChallenge,
+		CodeChallengeMethod: oidc.CodeChallengeMethod,
+	}
+}
+
+func TestAuthorize_ConsentRequired(t *testing.T) {
+	clientRepo := new(mocks.MockOAuthClientRepository)
+	grantRepo := new(mocks.MockGrantRepository)
+	codeRepo := new(mocks.MockAuthorizationCodeRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	client := newRelyingParty()
+	userID := uuid.New()
+
+	clientRepo.On("FindByID", client.ID).Return(client, nil)
+	grantRepo.On("Find", userID, client.ID).Return(nil, oidc.ErrGrantNotFound)
+
+	output, err := NewAuthorizeUseCase(clientRepo, grantRepo, codeRepo, mockSecurity).Execute(t.Context(), newAuthorizeInput(client, userID))
+
+	assert.ErrorIs(t, err, oidc.ErrConsentRequired)
+	assert.Nil(t, output)
+	codeRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestAuthorize_ApproveSavesGrantAndIssuesCode(t *testing.T) {
+	clientRepo := new(mocks.MockOAuthClientRepository)
+	grantRepo := new(mocks.MockGrantRepository)
+	codeRepo := new(mocks.MockAuthorizationCodeRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	client := newRelyingParty()
+	userID := uuid.New()
+
+	clientRepo.On("FindByID", client.ID).Return(client, nil)
+	grantRepo.On("Find", userID, client.ID).Return(nil, oidc.ErrGrantNotFound)
+	grantRepo.On("Save", mock.MatchedBy(func(g *oidc.Grant) bool {
+		return g.UserID == userID && g.ClientID == client.ID && g.Scopes == "openid email"
+	})).Return(nil)
+	mockSecurity.On("GenerateSecureRandomString", 32).Return("authorization-code", nil)
+	mockSecurity.On("HashOTP", "authorization-code").Return("hashed-code")
+	codeRepo.On("Create", mock.MatchedBy(func(c *oidc.AuthorizationCode) bool {
+		return c.CodeHash == "hashed-code" && c.Nonce == "n-0S6_WzA2Mj" && c.CodeChallenge == testCodeChallenge && c.AuthMethods == security.AuthMethodPassword
+	})).Return(nil)
+
+	input := newAuthorizeInput(client, userID)
+	input.Approve = true
+	output, err := NewAuthorizeUseCase(clientRepo, grantRepo, codeRepo, mockSecurity).Execute(t.Context(), input)
+
+	assert.NoError(t, err)
+	redirect, _ := url.Parse(output.RedirectTo)
+	assert.Equal(t, "partner.example.com", redirect.Host)
+	assert.Equal(t, "authorization-code", redirect.Query().Get("code"))
+	assert.Equal(t, "af0ifjsldkj", redirect.Query().Get("state"))
+	grantRepo.AssertExpectations(t)
+}
+
+func TestAuthorize_ExistingGrantSkipsConsent(t *testing.T) {
+	clientRepo := new(mocks.MockOAuthClientRepository)
+	grantRepo := new(mocks.MockGrantRepository)
+	codeRepo := new(mocks.MockAuthorizationCodeRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	client := newRelyingParty()
+	userID := uuid.New()
+
+	clientRepo.On("FindByID", client.ID).Return(client, nil)
+	grantRepo.On("Find", userID, client.ID).Return(&oidc.Grant{UserID: userID, ClientID: client.ID, Scopes: "openid email profile"}, nil)
+	mockSecurity.On("GenerateSecureRandomString", 32).Return("authorization-code", nil)
+	mockSecurity.On("HashOTP", "authorization-code").Return("hashed-code")
+	codeRepo.On("Create", mock.Anything).Return(nil)
+
+	output, err := NewAuthorizeUseCase(clientRepo, grantRepo, codeRepo, mockSecurity).Execute(t.Context(), newAuthorizeInput(client, userID))
+
+	assert.NoError(t, err)
+	assert.Contains(t, output.RedirectTo, "code=authorization-code")
+	grantRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestAuthorize_InvalidRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(input *AuthorizeInput)
+		err    error
+	}{
+		{"unregistered redirect uri", func(input *AuthorizeInput) { input.RedirectURI = "https://partner.example.com/callback/other" }, oidc.ErrInvalidRedirectURI},
+		{"unknown client", func(input *AuthorizeInput) { input.ClientID = "booking" }, oidc.ErrUnknownClient},
+		{"implicit flow", func(input *AuthorizeInput) { input.ResponseType = "token" }, oidc.ErrUnsupportedResponseType},
+		{"missing pkce", func(input *AuthorizeInput) { input.CodeChallenge = "" }, oidc.ErrInvalidRequest},
+		{"plain pkce", func(input *AuthorizeInput) { input.CodeChallengeMethod = "plain" }, oidc.ErrInvalidRequest},
+		{"missing openid scope", func(input *AuthorizeInput) { input.Scope = "email" }, oidc.ErrInvalidScope},
+		{"scope not allowed", func(input *AuthorizeInput) { input.Scope = "openid events:write" }, oidc.ErrInvalidScope},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientRepo := new(mocks.MockOAuthClientRepository)
+			grantRepo := new(mocks.MockGrantRepository)
+			codeRepo := new(mocks.MockAuthorizationCodeRepository)
+			mockSecurity := new(mocks.MockSecurityService)
+			client := newRelyingParty()
+
+			clientRepo.On("FindByID", client.ID).Return(client, nil)
+
+			input := newAuthorizeInput(client, uuid.New())
+			input.Approve = true
+			tt.modify(&input)
+			output, err := NewAuthorizeUseCase(clientRepo, grantRepo, codeRepo, mockSecurity).Execute(t.Context(), input)
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Nil(t, output)
+			grantRepo.AssertNotCalled(t, "Save", mock.Anything)
+			codeRepo.AssertNotCalled(t, "Create", mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package useCase
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/auth/domain/oauthclient"
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/auth/mocks"
+	"jamlink-backend/internal/shared/security"
+	"testing"
+)
+
+func newOAuthClient() *oauthclient.Client {
+	return &oauthclient.Client{ID: uuid.New(), Name: "Recommendation worker", SecretHash: "hashed-secret", Scopes: "events:read events:write"}
+}
+
+func TestClientCredentials_GrantsAllowedScopesByDefault(t *testing.T) {
+	clientRepo := new(mocks.MockOAuthClientRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	client := newOAuthClient()
+
+	clientRepo.On("FindByID", client.ID).Return(client, nil)
+	mockSecurity.On("CheckPassword", "secret", "hashed-secret").Return(true)
+	mockSecurity.On("GenerateJWT", (*uuid.UUID)(nil), (*string)(nil), testLifetimes.ClientCredentials, security.ClientTokenType, false, (*security.AuthContext)(nil)).Return("client.jwt", nil)
+
+	output, err := NewClientCredentialsUseCase(clientRepo, mockSecurity, testLifetimes).Execute(t.Context(), ClientCredentialsInput{ClientID: client.ID.String(), ClientSecret: "secret"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &TokenOutput{AccessToken: "client.jwt", TokenType: "Bearer", ExpiresIn: 3600, Scope: "events:read events:write"}, output)
+}
+
+func TestClientCredentials_RequestedScopes(t *testing.T) {
+	clientRepo := new(mocks.MockOAuthClientRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	client := newOAuthClient()
+
+	clientRepo.On("FindByID", client.ID).Return(client, nil)
+	mockSecurity.On("CheckPassword", "secret", "hashed-secret").Return(true)
+	mockSecurity.On("GenerateJWT", (*uuid.UUID)(nil), (*string)(nil), testLifetimes.ClientCredentials, security.ClientTokenType, false, (*security.AuthContext)(nil)).Return("client.jwt", nil)
+
+	output, err := NewClientCredentialsUseCase(clientRepo, mockSecurity, testLifetimes).Execute(t.Context(), ClientCredentialsInput{ClientID: client.ID.String(), ClientSecret: "secret", Scope: "events:read"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "events:read", output.Scope)
+}
+
+func TestClientCredentials_ScopeNotAllowed(t *testing.T) {
+	clientRepo := new(mocks.MockOAuthClientRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	client := newOAuthClient()
+
+	clientRepo.On("FindByID", client.ID).Return(client, nil)
+	mockSecurity.On("CheckPassword", "secret", "hashed-secret").Return(true)
+
+	output, err := NewClientCredentialsUseCase(clientRepo, mockSecurity, testLifetimes).Execute(t.Context(), ClientCredentialsInput{ClientID: client.ID.String(), ClientSecret: "secret", Scope: "events:read users:delete"})
+
+	assert.ErrorIs(t, err, oauthclient.ErrInvalidScope)
+	assert.Nil(t, output)
+	mockSecurity.AssertNotCalled(t, "GenerateJWT", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestClientCredentials_InvalidClient(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(clientRepo *mocks.MockOAuthClientRepository, mockSecurity *mocks.MockSecurityService, client *oauthclient.Client) ClientCredentialsInput
+	}{
+		{"malformed client id", func(_ *mocks.MockOAuthClientRepository, _ *mocks.MockSecurityService, _ *oauthclient.Client) ClientCredentialsInput {
+			return ClientCredentialsInput{ClientID: "jamlink-cli", ClientSecret: "secret"}
+		}},
+		{"unknown client", func(clientRepo *mocks.MockOAuthClientRepository, _ *mocks.MockSecurityService, client *oauthclient.Client) ClientCredentialsInput {
+			clientRepo.On("FindByID", client.ID).Return(nil, oauthclient.ErrClientNotFound)
+			return ClientCredentialsInput{ClientID: client.ID.String(), ClientSecret: "secret"}
+		}},
+		{"wrong secret", func(clientRepo *mocks.MockOAuthClientRepository, mockSecurity *mocks.MockSecurityService, client *oauthclient.Client) ClientCredentialsInput {
+			clientRepo.On("FindByID", client.ID).Return(client, nil)
+			mockSecurity.On("CheckPassword", "wrong", "hashed-secret").Return(false)
+			return ClientCredentialsInput{ClientID: client.ID.String(), ClientSecret: "wrong"}
+		}},
+	}
+
+	for _, tt := range tests {
+		clientRepo := new(mocks.MockOAuthClientRepository)
+		mockSecurity := new(mocks.MockSecurityService)
+		input := tt.setup(clientRepo, mockSecurity, newOAuthClient())
+
+		output, err := NewClientCredentialsUseCase(clientRepo, mockSecurity, testLifetimes).Execute(t.Context(), input)
+
+		assert.ErrorIs(t, err, oauthclient.ErrInvalidClient, tt.name)
+		assert.Nil(t, output, tt.name)
+	}
+}
+
+func TestRegisterOAuthClient_Success(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	clientRepo := new(mocks.MockOAuthClientRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	admin := &userDomain.User{ID: uuid.New(), Role: userDomain.RoleAdmin}
+
+	userRepo.On("FindByID", admin.ID).Return(admin, nil)
+	mockSecurity.On("GenerateSecureRandomString", 32).Return("secret", nil)
+	mockSecurity.On("HashPassword", "secret").Return("hashed-secret", nil)
+	clientRepo.On("Create", mock.MatchedBy(func(c *oauthclient.Client) bool {
+		return c.SecretHash == "hashed-secret" && c.Scopes == "events:read events:write" && c.CreatedBy == admin.ID
+	})).Return(nil)
+
+	output, err := NewRegisterOAuthClientUseCase(userRepo, clientRepo, mockSecurity).Execute(t.Context(), RegisterOAuthClientInput{
+		CreatedBy: admin.ID,
+		Name:      "Recommendation worker",
+		Scopes:    []string{"events:read", "events:write"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", output.ClientSecret)
+	clientRepo.AssertExpectations(t)
+}
+
+func TestRegisterOAuthClient_RequiresAdmin(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	clientRepo := new(mocks.MockOAuthClientRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	member := &userDomain.User{ID: uuid.New(), Role: userDomain.RoleUser}
+
+	userRepo.On("FindByID", member.ID).Return(member, nil)
+
+	output, err := NewRegisterOAuthClientUseCase(userRepo, clientRepo, mockSecurity).Execute(t.Context(), RegisterOAuthClientInput{
+		CreatedBy: member.ID,
+		Name:      "My bot",
+		Scopes:    []string{"events:read"},
+	})
+
+	assert.ErrorIs(t, err, oauthclient.ErrRegisterForbidden)
+	assert.Nil(t, output)
+	clientRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
@@ -0,0 +1,56 @@
+package useCase
+
+import (
+	"context"
+	"github.com/google/uuid"
+	tokenDomain "jamlink-backend/internal/modules/auth/domain/token"
+	"jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/shared/security"
+	"strings"
+	"time"
+)
+
+type ConfirmGuardianConsentUseCase struct {
+	repo     user.UserRepository
+	security security.SecurityService
+}
+
+type ConfirmGuardianConsentInput struct {
+	Token string `json:"token" binding:"required" example:"token"`
+}
+
+func NewConfirmGuardianConsentUseCase(repo user.UserRepository, security security.SecurityService) *ConfirmGuardianConsentUseCase {
+	return &ConfirmGuardianConsentUseCase{repo: repo, security: security}
+}
+
+// Execute records the guardian's consent from the link of the consent email. The token is bound to the
+// guardian address it was sent to, so changing that address invalidates older links.
+func (uc *ConfirmGuardianConsentUseCase) Execute(ctx context.Context, input ConfirmGuardianConsentInput) error {
+	claims, err := uc.security.ValidateJWT(ctx, input.Token)
+	if err != nil {
+		return err
+	}
+
+	if tokenType, ok := claims["type"].(string); !ok || tokenType != GuardianConsentTokenType {
+		return tokenDomain.ErrTokenType
+	}
+
+	rawID, _ := claims["id"].(string)
+	userID, err := uuid.Parse(rawID)
+	if err != nil {
+		return security.ErrInvalidUserID
+	}
+
+	guardianEmail, _ := claims["email"].(string)
+
+	minor, err := uc.repo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !minor.AwaitingGuardianConsent() || !strings.EqualFold(minor.Minor.GuardianEmail, guardianEmail) {
+		return user.ErrNotAwaitingGuardian
+	}
+
+	return uc.repo.MarkGuardianConsent(ctx, minor.ID, time.Now())
+}
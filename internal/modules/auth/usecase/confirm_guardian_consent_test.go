@@ -0,0 +1,73 @@
+package useCase
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	tokenDomain "jamlink-backend/internal/modules/auth/domain/token"
+	"jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/auth/mocks"
+	"testing"
+)
+
+func TestConfirmGuardianConsent_Success(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+
+	userID := uuid.New()
+	minor := &user.User{ID: userID, Minor: user.MinorStatus{IsMinor: true, GuardianEmail: "parent@example.com"}}
+
+	mockSecurity.On("ValidateJWT", "guardian.jwt").Return(jwt.MapClaims{"type": GuardianConsentTokenType, "id": userID.String(), "email": "Parent@example.com"}, nil)
+	mockRepo.On("FindByID", userID).Return(minor, nil)
+	mockRepo.On("MarkGuardianConsent", userID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	err := NewConfirmGuardianConsentUseCase(mockRepo, mockSecurity).Execute(t.Context(), ConfirmGuardianConsentInput{Token: "guardian.jwt"})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestConfirmGuardianConsent_GuardianChanged(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+
+	userID := uuid.New()
+	minor := &user.User{ID: userID, Minor: user.MinorStatus{IsMinor: true, GuardianEmail: "new.parent@example.com"}}
+
+	mockSecurity.On("ValidateJWT", "guardian.jwt").Return(jwt.MapClaims{"type": GuardianConsentTokenType, "id": userID.String(), "email": "parent@example.com"}, nil)
+	mockRepo.On("FindByID", userID).Return(minor, nil)
+
+	err := NewConfirmGuardianConsentUseCase(mockRepo, mockSecurity).Execute(t.Context(), ConfirmGuardianConsentInput{Token: "guardian.jwt"})
+
+	assert.ErrorIs(t, err, user.ErrNotAwaitingGuardian)
+	mockRepo.AssertNotCalled(t, "MarkGuardianConsent", mock.Anything, mock.Anything)
+}
+
+func TestConfirmGuardianConsent_WrongTokenType(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+
+	mockSecurity.On("ValidateJWT", "login.jwt").Return(jwt.MapClaims{"type": "login", "id": uuid.New().String()}, nil)
+
+	err := NewConfirmGuardianConsentUseCase(mockRepo, mockSecurity).Execute(t.Context(), ConfirmGuardianConsentInput{Token: "login.jwt"})
+
+	assert.ErrorIs(t, err, tokenDomain.ErrTokenType)
+	mockRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+}
+
+func TestVerificationClaims(t *testing.T) {
+	adult := &user.User{Verification: user.UserVerification{IsVerified: true}}
+	isVerified, opts := verificationClaims(adult)
+	assert.True(t, isVerified)
+	assert.Empty(t, opts)
+
+	minor := &user.User{Verification: user.UserVerification{IsVerified: true}, Minor: user.MinorStatus{IsMinor: true}}
+	isVerified, opts = verificationClaims(minor)
+	assert.False(t, isVerified)
+	assert.Len(t, opts, 1)
+
+	claims := jwt.MapClaims{}
+	opts[0](claims)
+	assert.Equal(t, "guardian_consent", claims["pendingVerification"])
+}
@@ -0,0 +1,14 @@
+package useCase
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ConsentRecorder checks and records the consents given on the sign-up form. It is implemented by the
+// consents module.
+type ConsentRecorder interface {
+	Check(ctx context.Context, termsVersion, privacyVersion string) error
+	Record(ctx context.Context, userID uuid.UUID, termsVersion, privacyVersion string, marketingOptIn bool, ip, userAgent string) error
+}
@@ -0,0 +1,60 @@
+package useCase
+
+import (
+	"context"
+	"errors"
+	"jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/auth/domain/user/invariants"
+	"jamlink-backend/internal/shared/security"
+	"time"
+)
+
+// CreateAdminUseCase creates administrator accounts from the command line. Admins are created by an
+// operator rather than registering, so they bypass the registration mode and need no verification.
+type CreateAdminUseCase struct {
+	repo     user.UserRepository
+	security security.SecurityService
+}
+
+type CreateAdminInput struct {
+	Email         string
+	Password      string
+	PreferredLang string
+}
+
+func NewCreateAdminUseCase(repo user.UserRepository, security security.SecurityService) *CreateAdminUseCase {
+	return &CreateAdminUseCase{repo: repo, security: security}
+}
+
+func (uc *CreateAdminUseCase) Execute(ctx context.Context, input CreateAdminInput) (*user.User, error) {
+	if err := userInvariants.ValidateUser(input.Email, input.Password); err != nil {
+		return nil, err
+	}
+
+	_, err := uc.repo.FindByEmail(ctx, input.Email)
+	if err == nil {
+		return nil, user.ErrEmailAlreadyExists
+	}
+	if !errors.Is(err, user.ErrUserNotFound) {
+		return nil, err
+	}
+
+	hashed, err := uc.security.HashPassword(ctx, input.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	admin, err := user.CreateUser(input.Email, hashed, input.PreferredLang, "local")
+	if err != nil {
+		return nil, err
+	}
+	admin.Role = user.RoleAdmin
+	now := time.Now()
+	admin.Verification = user.UserVerification{IsVerified: true, VerifiedAt: &now}
+
+	if err := uc.repo.Create(ctx, admin); err != nil {
+		return nil, err
+	}
+
+	return admin, nil
+}
@@ -0,0 +1,53 @@
+package useCase
+
+import (
+	"jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/auth/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAdminUseCase_Execute_CreatesVerifiedAdmin(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockSec := new(mocks.MockSecurityService)
+	uc := NewCreateAdminUseCase(mockRepo, mockSec)
+
+	mockRepo.On("FindByEmail", "admin@example.com").Return(nil, user.ErrUserNotFound)
+	mockSec.On("HashPassword", "Abcd1234!").Return("hashed", nil)
+	mockRepo.On("Create", mock.AnythingOfType("*user.User")).Return(nil)
+
+	admin, err := uc.Execute(t.Context(), CreateAdminInput{Email: "admin@example.com", Password: "Abcd1234!", PreferredLang: "fr-FR"})
+
+	require.NoError(t, err)
+	assert.True(t, admin.IsAdmin())
+	assert.True(t, admin.IsVerified())
+	assert.Equal(t, "hashed", admin.Password)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateAdminUseCase_Execute_RejectsExistingEmail(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockSec := new(mocks.MockSecurityService)
+	uc := NewCreateAdminUseCase(mockRepo, mockSec)
+
+	mockRepo.On("FindByEmail", "admin@example.com").Return(&user.User{Email: "admin@example.com"}, nil)
+
+	_, err := uc.Execute(t.Context(), CreateAdminInput{Email: "admin@example.com", Password: "Abcd1234!"})
+
+	assert.ErrorIs(t, err, user.ErrEmailAlreadyExists)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCreateAdminUseCase_Execute_RejectsWeakPassword(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockSec := new(mocks.MockSecurityService)
+	uc := NewCreateAdminUseCase(mockRepo, mockSec)
+
+	_, err := uc.Execute(t.Context(), CreateAdminInput{Email: "admin@example.com", Password: "weak"})
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "FindByEmail", mock.Anything)
+}
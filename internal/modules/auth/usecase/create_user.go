@@ -1,53 +1,163 @@
 package useCase
 
 import (
+	"context"
+	"errors"
+	"jamlink-backend/internal/modules/auth/domain/transaction"
 	"jamlink-backend/internal/modules/auth/domain/user"
 	"jamlink-backend/internal/modules/auth/domain/user/invariants"
+	"jamlink-backend/internal/shared/email"
 	"jamlink-backend/internal/shared/security"
+	"time"
 )
 
 type CreateUserUseCase struct {
-	repo     user.UserRepository
-	security security.SecurityService
+	uow             transaction.UnitOfWork
+	security        security.SecurityService
+	emailService    email.EmailService
+	gate            RegistrationGate
+	consents        ConsentRecorder
+	guardianConsent *RequestGuardianConsentUseCase
+	minorAge        int
 }
 
-func NewCreateUserUseCase(repo user.UserRepository, security security.SecurityService) *CreateUserUseCase {
-	return &CreateUserUseCase{repo: repo, security: security}
+// DefaultMinorAge is the age under which accounts are treated as minors unless configured otherwise.
+const DefaultMinorAge = 18
+
+// dateOfBirthLayout is the format of CreateUserInput.DateOfBirth.
+const dateOfBirthLayout = "2006-01-02"
+
+func NewCreateUserUseCase(uow transaction.UnitOfWork, security security.SecurityService, emailService email.EmailService, gate RegistrationGate, consents ConsentRecorder, guardianConsent *RequestGuardianConsentUseCase, minorAge int) *CreateUserUseCase {
+	return &CreateUserUseCase{uow: uow, security: security, emailService: emailService, gate: gate, consents: consents, guardianConsent: guardianConsent, minorAge: minorAge}
 }
 
 type CreateUserInput struct {
-	Email         string `json:"email" binding:"required,email" example:"user@example.com"`
-	Password      string `json:"password" binding:"required" example:"Abcd1234!"`
+	Email      string `json:"email" binding:"required,email" example:"user@example.com"`
+	Password   string `json:"password" binding:"required" example:"Abcd1234!"`
+	InviteCode string `json:"inviteCode,omitempty" example:"k3J9xQ2mZ7aB"`
+	// TermsVersion and PrivacyVersion are the versions of the documents shown on the sign-up form.
+	TermsVersion   string `json:"termsVersion" example:"2025-01"`
+	PrivacyVersion string `json:"privacyVersion" example:"2025-01"`
+	MarketingOptIn bool   `json:"marketingOptIn"`
+	// DateOfBirth is optional. Minors must give a guardian email, whose owner confirms the account.
+	DateOfBirth   string `json:"dateOfBirth,omitempty" example:"2009-04-21"`
+	GuardianEmail string `json:"guardianEmail,omitempty" example:"parent@example.com"`
 	PreferredLang string `gorm:"type:varchar(5);default:'en'" json:"-"`
+	IP            string `json:"-"`
+	UserAgent     string `json:"-"`
 }
 
-func (uc *CreateUserUseCase) Execute(input CreateUserInput) (*user.User, error) {
-	_, err := uc.repo.FindByEmail(input.Email)
+// Validate only checks the input itself, the invitation code and the accepted documents, so it can run
+// on the request path without revealing whether the email is already registered.
+func (uc *CreateUserUseCase) Validate(ctx context.Context, input CreateUserInput) error {
+	if err := userInvariants.ValidateUser(input.Email, input.Password); err != nil {
+		return err
+	}
 
-	if err == nil {
-		return nil, user.ErrEmailAlreadyExists
+	if _, err := uc.validateDateOfBirth(input); err != nil {
+		return err
 	}
 
-	if err := userInvariants.ValidateUser(input.Email, input.Password); err != nil {
-		return nil, err
+	if err := uc.consents.Check(ctx, input.TermsVersion, input.PrivacyVersion); err != nil {
+		return err
 	}
 
-	hashedPassword, err := uc.security.HashPassword(input.Password)
+	return uc.gate.Check(ctx, input.InviteCode)
+}
+
+// Execute returns a nil user without error when the email is already taken: the owner of the
+// address is notified by email instead of the caller.
+func (uc *CreateUserUseCase) Execute(ctx context.Context, input CreateUserInput) (*user.User, error) {
+	if err := uc.Validate(ctx, input); err != nil {
+		return nil, err
+	}
 
+	// Hashing is slow, so it is done before the transaction rather than while holding it open. Taken
+	// emails are hashed too, which keeps them from answering faster.
+	hashedPassword, err := uc.security.HashPassword(ctx, input.Password)
 	if err != nil {
 		return nil, err
 	}
 
-	user, err := user.CreateUser(input.Email, hashedPassword, input.PreferredLang, "local")
+	var existingUser, newUser *user.User
+	err = uc.uow.Do(ctx, func(repos transaction.Repositories) error {
+		found, err := repos.Users.FindByEmail(ctx, input.Email)
+		if err == nil {
+			existingUser = found
+			return nil
+		}
+		if !errors.Is(err, user.ErrUserNotFound) {
+			return err
+		}
+
+		newUser, err = user.CreateUser(input.Email, hashedPassword, input.PreferredLang, "local")
+		if err != nil {
+			return err
+		}
+
+		dateOfBirth, err := uc.validateDateOfBirth(input)
+		if err != nil {
+			return err
+		}
+		if dateOfBirth != nil {
+			newUser.SetDateOfBirth(*dateOfBirth, uc.minorAge, input.GuardianEmail, time.Now())
+		}
+
+		// The code is consumed in the transaction, so a failed insert gives the use back.
+		if err := uc.gate.Redeem(ctx, repos.Invitations, input.InviteCode, newUser.ID); err != nil {
+			return err
+		}
+
+		return repos.Users.Create(ctx, newUser)
+	})
+
+	// A concurrent registration of the same email, committed between the lookup and the insert, is
+	// answered like any existing account.
+	if errors.Is(err, user.ErrEmailAlreadyExists) {
+		return nil, uc.emailService.Send(ctx, input.Email, email.TemplateRegisterAttempt, input.PreferredLang, map[string]string{})
+	}
 	if err != nil {
 		return nil, err
 	}
+	if existingUser != nil {
+		return nil, uc.emailService.Send(ctx, existingUser.Email, email.TemplateRegisterAttempt, existingUser.PreferredLang, map[string]string{})
+	}
+
+	// Without a record the user is simply asked to accept the documents again on their first request.
+	if err := uc.consents.Record(ctx, newUser.ID, input.TermsVersion, input.PrivacyVersion, input.MarketingOptIn, input.IP, input.UserAgent); err != nil {
+		return newUser, err
+	}
+
+	// The minor can ask for the email again if this one is lost.
+	if err := uc.guardianConsent.Notify(ctx, newUser); err != nil {
+		return newUser, err
+	}
 
-	err = uc.repo.Create(user)
+	return newUser, nil
+}
+
+// validateDateOfBirth returns nil when no date of birth was given, and requires a guardian email from
+// minors.
+func (uc *CreateUserUseCase) validateDateOfBirth(input CreateUserInput) (*time.Time, error) {
+	if input.DateOfBirth == "" {
+		return nil, nil
+	}
 
+	dateOfBirth, err := time.Parse(dateOfBirthLayout, input.DateOfBirth)
 	if err != nil {
+		return nil, userInvariants.ErrInvalidDateOfBirth
+	}
+
+	now := time.Now()
+	if err := userInvariants.ValidateDateOfBirth(dateOfBirth, now); err != nil {
 		return nil, err
 	}
 
-	return user, err
+	if user.AgeAt(dateOfBirth, now) < uc.minorAge {
+		if err := userInvariants.ValidateGuardianEmail(input.Email, input.GuardianEmail); err != nil {
+			return nil, err
+		}
+	}
+
+	return &dateOfBirth, nil
 }
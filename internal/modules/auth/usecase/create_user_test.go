@@ -5,15 +5,20 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/auth/domain/user/invariants"
 	"jamlink-backend/internal/modules/auth/mocks"
+	"jamlink-backend/internal/shared/email"
+	"jamlink-backend/internal/shared/security"
 	"testing"
+	"time"
 )
 
 func TestCreateUser_Success(t *testing.T) {
 	mockRepo := new(mocks.MockUserRepository)
 	mockSecurity := new(mocks.MockSecurityService)
+	mockEmail := new(mocks.MockEmailService)
 
-	useCase := NewCreateUserUseCase(mockRepo, mockSecurity)
+	useCase := NewCreateUserUseCase(mocks.NewMockUnitOfWork(mockRepo, nil), mockSecurity, mockEmail, openRegistrationGate(), acceptingConsentRecorder(), NewRequestGuardianConsentUseCase(mockRepo, mockSecurity, mockEmail, "https://example.com/guardian-consent"), DefaultMinorAge)
 
 	input := CreateUserInput{
 		Email:         "test@example.com",
@@ -21,44 +26,114 @@ func TestCreateUser_Success(t *testing.T) {
 		PreferredLang: "fr-FR",
 	}
 
-	mockRepo.On("FindByEmail", input.Email).Return(nil, errors.New("user not found"))
+	mockRepo.On("FindByEmail", input.Email).Return(nil, user.ErrUserNotFound)
 	mockSecurity.On("HashPassword", input.Password).Return("hashedpassword123", nil)
 	mockRepo.On("Create", mock.Anything).Return(nil)
-	user, err := useCase.Execute(input)
+	user, err := useCase.Execute(t.Context(), input)
 
 	assert.NoError(t, err)
 	if assert.NotNil(t, user) {
 		assert.Equal(t, input.Email, user.Email, input.PreferredLang)
 		assert.Equal(t, "hashedpassword123", user.Password)
 	}
+	mockEmail.AssertNotCalled(t, "Send")
 }
 
 func TestCreateUser_EmailAlreadyExists(t *testing.T) {
 	mockRepo := new(mocks.MockUserRepository)
 	mockSecurity := new(mocks.MockSecurityService)
+	mockEmail := new(mocks.MockEmailService)
 
-	useCase := NewCreateUserUseCase(mockRepo, mockSecurity)
+	useCase := NewCreateUserUseCase(mocks.NewMockUnitOfWork(mockRepo, nil), mockSecurity, mockEmail, openRegistrationGate(), acceptingConsentRecorder(), NewRequestGuardianConsentUseCase(mockRepo, mockSecurity, mockEmail, "https://example.com/guardian-consent"), DefaultMinorAge)
 
 	input := CreateUserInput{
 		Email:         "test@example.com",
 		Password:      "Password123@",
-		PreferredLang: "fr-FR",
+		PreferredLang: "en",
 	}
 
-	mockRepo.On("FindByEmail", input.Email).Return(&user.User{Email: input.Email}, nil)
+	mockSecurity.On("HashPassword", input.Password).Return("hashedpassword123", nil)
+	mockRepo.On("FindByEmail", input.Email).Return(&user.User{Email: input.Email, PreferredLang: "fr-FR"}, nil)
+	mockEmail.On("Send", input.Email, email.TemplateRegisterAttempt, "fr-FR", map[string]string{}).Return(nil)
 
-	user, err := useCase.Execute(input)
+	user, err := useCase.Execute(t.Context(), input)
 
-	assert.Error(t, err)
+	assert.NoError(t, err)
+	assert.Nil(t, user)
+	mockEmail.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCreateUser_ConcurrentRegistrationIsAnsweredByEmail(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	mockEmail := new(mocks.MockEmailService)
+	uow := mocks.NewMockUnitOfWork(mockRepo, nil)
+
+	useCase := NewCreateUserUseCase(uow, mockSecurity, mockEmail, openRegistrationGate(), acceptingConsentRecorder(), NewRequestGuardianConsentUseCase(mockRepo, mockSecurity, mockEmail, "https://example.com/guardian-consent"), DefaultMinorAge)
+
+	input := CreateUserInput{
+		Email:         "test@example.com",
+		Password:      "Password123@",
+		PreferredLang: "en",
+	}
+
+	// Another registration of the email commits between the lookup and the insert.
+	mockRepo.On("FindByEmail", input.Email).Return(nil, user.ErrUserNotFound)
+	mockSecurity.On("HashPassword", input.Password).Return("hashedpassword123", nil)
+	mockRepo.On("Create", mock.Anything).Return(user.ErrEmailAlreadyExists)
+	mockEmail.On("Send", input.Email, email.TemplateRegisterAttempt, "en", map[string]string{}).Return(nil)
+
+	user, err := useCase.Execute(t.Context(), input)
+
+	assert.NoError(t, err)
+	assert.Nil(t, user)
+	assert.Equal(t, 1, uow.Rollbacks)
+	mockEmail.AssertExpectations(t)
+}
+
+func TestCreateUser_LookupFails(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	mockEmail := new(mocks.MockEmailService)
+
+	useCase := NewCreateUserUseCase(mocks.NewMockUnitOfWork(mockRepo, nil), mockSecurity, mockEmail, openRegistrationGate(), acceptingConsentRecorder(), NewRequestGuardianConsentUseCase(mockRepo, mockSecurity, mockEmail, "https://example.com/guardian-consent"), DefaultMinorAge)
+
+	input := CreateUserInput{
+		Email:    "test@example.com",
+		Password: "Password123@",
+	}
+
+	mockSecurity.On("HashPassword", input.Password).Return("hashedpassword123", nil)
+	mockRepo.On("FindByEmail", input.Email).Return(nil, errors.New("connection refused"))
+
+	user, err := useCase.Execute(t.Context(), input)
+
+	assert.EqualError(t, err, "connection refused")
+	assert.Nil(t, user)
+	mockEmail.AssertNotCalled(t, "Send")
+}
+
+func TestCreateUser_InvalidPasswordIsCheckedFirst(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	mockEmail := new(mocks.MockEmailService)
+
+	useCase := NewCreateUserUseCase(mocks.NewMockUnitOfWork(mockRepo, nil), mockSecurity, mockEmail, openRegistrationGate(), acceptingConsentRecorder(), NewRequestGuardianConsentUseCase(mockRepo, mockSecurity, mockEmail, "https://example.com/guardian-consent"), DefaultMinorAge)
+
+	user, err := useCase.Execute(t.Context(), CreateUserInput{Email: "test@example.com", Password: "weak"})
+
+	assert.ErrorIs(t, err, userInvariants.ErrShortPassword)
 	assert.Nil(t, user)
-	assert.Equal(t, "email already exists", err.Error())
+	mockRepo.AssertNotCalled(t, "FindByEmail", mock.Anything)
 }
 
 func TestCreateUser_FailOnHashing(t *testing.T) {
 	mockRepo := new(mocks.MockUserRepository)
 	mockSecurity := new(mocks.MockSecurityService)
+	mockEmail := new(mocks.MockEmailService)
 
-	useCase := NewCreateUserUseCase(mockRepo, mockSecurity)
+	useCase := NewCreateUserUseCase(mocks.NewMockUnitOfWork(mockRepo, nil), mockSecurity, mockEmail, openRegistrationGate(), acceptingConsentRecorder(), NewRequestGuardianConsentUseCase(mockRepo, mockSecurity, mockEmail, "https://example.com/guardian-consent"), DefaultMinorAge)
 
 	input := CreateUserInput{
 		Email:         "test@example.com",
@@ -66,12 +141,199 @@ func TestCreateUser_FailOnHashing(t *testing.T) {
 		PreferredLang: "fr-FR",
 	}
 
-	mockRepo.On("FindByEmail", input.Email).Return(nil, errors.New("user not found"))
 	mockSecurity.On("HashPassword", input.Password).Return("", errors.New("hashing error"))
 
-	user, err := useCase.Execute(input)
+	user, err := useCase.Execute(t.Context(), input)
 
 	assert.Error(t, err)
 	assert.Nil(t, user)
 	assert.Equal(t, "hashing error", err.Error())
+	mockRepo.AssertNotCalled(t, "FindByEmail", mock.Anything)
+}
+
+func TestCreateUser_RejectedByRegistrationGate(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	mockEmail := new(mocks.MockEmailService)
+	mockGate := new(mocks.MockRegistrationGate)
+
+	useCase := NewCreateUserUseCase(mocks.NewMockUnitOfWork(mockRepo, nil), mockSecurity, mockEmail, mockGate, acceptingConsentRecorder(), NewRequestGuardianConsentUseCase(mockRepo, mockSecurity, mockEmail, "https://example.com/guardian-consent"), DefaultMinorAge)
+
+	gateErr := errors.New("an invitation code is required to register")
+	mockGate.On("Check", "").Return(gateErr)
+
+	user, err := useCase.Execute(t.Context(), CreateUserInput{Email: "test@example.com", Password: "Password123@"})
+
+	assert.ErrorIs(t, err, gateErr)
+	assert.Nil(t, user)
+	mockRepo.AssertNotCalled(t, "FindByEmail", mock.Anything)
+}
+
+func TestCreateUser_RedeemsInvitationBeforeCreating(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	mockEmail := new(mocks.MockEmailService)
+	mockGate := new(mocks.MockRegistrationGate)
+
+	useCase := NewCreateUserUseCase(mocks.NewMockUnitOfWork(mockRepo, nil), mockSecurity, mockEmail, mockGate, acceptingConsentRecorder(), NewRequestGuardianConsentUseCase(mockRepo, mockSecurity, mockEmail, "https://example.com/guardian-consent"), DefaultMinorAge)
+
+	input := CreateUserInput{Email: "test@example.com", Password: "Password123@", InviteCode: "k3J9xQ2mZ7aB"}
+
+	mockGate.On("Check", input.InviteCode).Return(nil)
+	mockRepo.On("FindByEmail", input.Email).Return(nil, user.ErrUserNotFound)
+	mockSecurity.On("HashPassword", input.Password).Return("hashedpassword123", nil)
+	mockGate.On("Redeem", input.InviteCode, mock.Anything).Return(errors.New("invitation has no uses left"))
+
+	user, err := useCase.Execute(t.Context(), input)
+
+	assert.EqualError(t, err, "invitation has no uses left")
+	assert.Nil(t, user)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// openRegistrationGate lets every sign-up through, as in open registration mode without a code.
+func openRegistrationGate() *mocks.MockRegistrationGate {
+	gate := new(mocks.MockRegistrationGate)
+	gate.On("Check", mock.Anything).Return(nil)
+	gate.On("Redeem", mock.Anything, mock.Anything).Return(nil)
+	return gate
+}
+
+func TestCreateUser_RecordsConsents(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	mockEmail := new(mocks.MockEmailService)
+	mockConsents := new(mocks.MockConsentRecorder)
+
+	useCase := NewCreateUserUseCase(mocks.NewMockUnitOfWork(mockRepo, nil), mockSecurity, mockEmail, openRegistrationGate(), mockConsents, NewRequestGuardianConsentUseCase(mockRepo, mockSecurity, mockEmail, "https://example.com/guardian-consent"), DefaultMinorAge)
+
+	input := CreateUserInput{
+		Email:          "test@example.com",
+		Password:       "Password123@",
+		TermsVersion:   "2025-01",
+		PrivacyVersion: "2024-06",
+		MarketingOptIn: true,
+		IP:             "203.0.113.7",
+		UserAgent:      "Mozilla/5.0",
+	}
+
+	mockConsents.On("Check", "2025-01", "2024-06").Return(nil)
+	mockRepo.On("FindByEmail", input.Email).Return(nil, user.ErrUserNotFound)
+	mockSecurity.On("HashPassword", input.Password).Return("hashedpassword123", nil)
+	mockRepo.On("Create", mock.Anything).Return(nil)
+	mockConsents.On("Record", mock.AnythingOfType("uuid.UUID"), "2025-01", "2024-06", true, "203.0.113.7", "Mozilla/5.0").Return(nil)
+
+	createdUser, err := useCase.Execute(t.Context(), input)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, createdUser)
+	mockConsents.AssertExpectations(t)
+}
+
+func TestCreateUser_OutdatedConsentIsRejected(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	mockEmail := new(mocks.MockEmailService)
+	mockConsents := new(mocks.MockConsentRecorder)
+
+	useCase := NewCreateUserUseCase(mocks.NewMockUnitOfWork(mockRepo, nil), mockSecurity, mockEmail, openRegistrationGate(), mockConsents, NewRequestGuardianConsentUseCase(mockRepo, mockSecurity, mockEmail, "https://example.com/guardian-consent"), DefaultMinorAge)
+
+	consentErr := errors.New("the accepted version is not the current one")
+	mockConsents.On("Check", "2024-01", "2024-01").Return(consentErr)
+
+	createdUser, err := useCase.Execute(t.Context(), CreateUserInput{Email: "test@example.com", Password: "Password123@", TermsVersion: "2024-01", PrivacyVersion: "2024-01"})
+
+	assert.ErrorIs(t, err, consentErr)
+	assert.Nil(t, createdUser)
+	mockRepo.AssertNotCalled(t, "FindByEmail", mock.Anything)
+}
+
+// acceptingConsentRecorder accepts and records any consent, as when no document is published yet.
+func acceptingConsentRecorder() *mocks.MockConsentRecorder {
+	consents := new(mocks.MockConsentRecorder)
+	consents.On("Check", mock.Anything, mock.Anything).Return(nil)
+	consents.On("Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	return consents
+}
+
+func TestCreateUser_MinorRequiresGuardianConsent(t *testing.T) {
+
+	mockRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	mockEmail := new(mocks.MockEmailService)
+
+	useCase := NewCreateUserUseCase(mocks.NewMockUnitOfWork(mockRepo, nil), mockSecurity, mockEmail, openRegistrationGate(), acceptingConsentRecorder(), NewRequestGuardianConsentUseCase(mockRepo, mockSecurity, mockEmail, "https://example.com/guardian-consent"), DefaultMinorAge)
+
+	input := CreateUserInput{
+		Email:         "teen@example.com",
+		Password:      "Password123@",
+		PreferredLang: "fr-FR",
+		DateOfBirth:   time.Now().AddDate(-15, 0, 0).Format("2006-01-02"),
+		GuardianEmail: "parent@example.com",
+	}
+
+	mockRepo.On("FindByEmail", input.Email).Return(nil, user.ErrUserNotFound)
+	mockSecurity.On("HashPassword", input.Password).Return("hashedpassword123", nil)
+	mockRepo.On("Create", mock.Anything).Return(nil)
+	mockRepo.On("MarkGuardianConsentRequested", mock.AnythingOfType("uuid.UUID"), mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(nil)
+	mockSecurity.On("GenerateJWT", mock.AnythingOfType("*uuid.UUID"), mock.AnythingOfType("*string"), GuardianConsentTokenTTL, GuardianConsentTokenType, false, (*security.AuthContext)(nil)).Return("guardian.jwt", nil)
+	mockEmail.On("Send", "parent@example.com", email.TemplateGuardianConsent, "fr-FR", map[string]string{
+		"URL":         "https://example.com/guardian-consent?token=guardian.jwt",
+		"CHILD_EMAIL": "teen@example.com",
+	}).Return(nil)
+
+	createdUser, err := useCase.Execute(t.Context(), input)
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, createdUser) {
+		assert.True(t, createdUser.Minor.IsMinor)
+		assert.True(t, createdUser.AwaitingGuardianConsent())
+		assert.True(t, createdUser.Privacy.RestrictAdultMessages)
+		assert.True(t, createdUser.Privacy.HideLocation)
+	}
+	mockEmail.AssertExpectations(t)
+}
+
+func TestCreateUser_AdultWithDateOfBirth(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	mockEmail := new(mocks.MockEmailService)
+
+	useCase := NewCreateUserUseCase(mocks.NewMockUnitOfWork(mockRepo, nil), mockSecurity, mockEmail, openRegistrationGate(), acceptingConsentRecorder(), NewRequestGuardianConsentUseCase(mockRepo, mockSecurity, mockEmail, "https://example.com/guardian-consent"), DefaultMinorAge)
+
+	input := CreateUserInput{Email: "adult@example.com", Password: "Password123@", DateOfBirth: "1990-06-15"}
+
+	mockRepo.On("FindByEmail", input.Email).Return(nil, user.ErrUserNotFound)
+	mockSecurity.On("HashPassword", input.Password).Return("hashedpassword123", nil)
+	mockRepo.On("Create", mock.Anything).Return(nil)
+
+	createdUser, err := useCase.Execute(t.Context(), input)
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, createdUser) {
+		assert.NotNil(t, createdUser.DateOfBirth)
+		assert.False(t, createdUser.Minor.IsMinor)
+		assert.False(t, createdUser.Privacy.RestrictAdultMessages)
+	}
+	mockEmail.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateUser_MinorWithoutGuardianEmail(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	mockEmail := new(mocks.MockEmailService)
+
+	useCase := NewCreateUserUseCase(mocks.NewMockUnitOfWork(mockRepo, nil), mockSecurity, mockEmail, openRegistrationGate(), acceptingConsentRecorder(), NewRequestGuardianConsentUseCase(mockRepo, mockSecurity, mockEmail, "https://example.com/guardian-consent"), 16)
+
+	input := CreateUserInput{
+		Email:       "teen@example.com",
+		Password:    "Password123@",
+		DateOfBirth: time.Now().AddDate(-15, 0, 0).Format("2006-01-02"),
+	}
+
+	createdUser, err := useCase.Execute(t.Context(), input)
+
+	assert.ErrorIs(t, err, userInvariants.ErrGuardianEmailRequired)
+	assert.Nil(t, createdUser)
+	mockRepo.AssertNotCalled(t, "FindByEmail", mock.Anything)
 }
@@ -0,0 +1,51 @@
+package useCase
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"jamlink-backend/internal/modules/auth/domain/deviceauth"
+	"time"
+)
+
+type DecideDeviceAuthorizationUseCase struct {
+	deviceAuthRepo deviceauth.DeviceAuthorizationRepository
+}
+
+type DecideDeviceAuthorizationInput struct {
+	UserID   uuid.UUID `json:"-"`
+	UserCode string    `json:"user_code" binding:"required" example:"WDJB-MJHT"`
+	Approve  bool      `json:"approve" example:"true"`
+}
+
+type DecideDeviceAuthorizationOutput struct {
+	ClientID string `json:"client_id" example:"jamlink-cli"`
+	Approved bool   `json:"approved" example:"true"`
+}
+
+func NewDecideDeviceAuthorizationUseCase(deviceAuthRepo deviceauth.DeviceAuthorizationRepository) *DecideDeviceAuthorizationUseCase {
+	return &DecideDeviceAuthorizationUseCase{deviceAuthRepo: deviceAuthRepo}
+}
+
+// Execute approves or denies, on behalf of the logged-in user, the device showing the user code.
+func (uc *DecideDeviceAuthorizationUseCase) Execute(ctx context.Context, input DecideDeviceAuthorizationInput) (*DecideDeviceAuthorizationOutput, error) {
+	now := time.Now()
+
+	authorization, err := uc.deviceAuthRepo.FindByUserCode(ctx, deviceauth.NormalizeUserCode(input.UserCode))
+	if err != nil {
+		return nil, err
+	}
+	if authorization.IsExpired(now) {
+		return nil, deviceauth.ErrUserCodeNotFound
+	}
+
+	status := deviceauth.StatusDenied
+	if input.Approve {
+		status = deviceauth.StatusApproved
+	}
+
+	if err := uc.deviceAuthRepo.Decide(ctx, authorization.ID, status, input.UserID, now); err != nil {
+		return nil, err
+	}
+
+	return &DecideDeviceAuthorizationOutput{ClientID: authorization.ClientID, Approved: input.Approve}, nil
+}
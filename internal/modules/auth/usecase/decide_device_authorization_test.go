@@ -0,0 +1,60 @@
+package useCase
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/auth/domain/deviceauth"
+	"jamlink-backend/internal/modules/auth/mocks"
+	"testing"
+	"time"
+)
+
+func TestDecideDeviceAuthorization_ApproveNormalizesCode(t *testing.T) {
+	deviceAuthRepo := new(mocks.MockDeviceAuthorizationRepository)
+	userID := uuid.New()
+	authorization := newDeviceAuthorization(deviceauth.StatusPending)
+
+	deviceAuthRepo.On("FindByUserCode", "WDJB-MJHT").Return(authorization, nil)
+	deviceAuthRepo.On("Decide", authorization.ID, deviceauth.StatusApproved, userID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	output, err := NewDecideDeviceAuthorizationUseCase(deviceAuthRepo).Execute(t.Context(), DecideDeviceAuthorizationInput{UserID: userID, UserCode: "wdjb mjht", Approve: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &DecideDeviceAuthorizationOutput{ClientID: "jamlink-cli", Approved: true}, output)
+	deviceAuthRepo.AssertExpectations(t)
+}
+
+func TestDecideDeviceAuthorization_ExpiredCode(t *testing.T) {
+	deviceAuthRepo := new(mocks.MockDeviceAuthorizationRepository)
+	authorization := newDeviceAuthorization(deviceauth.StatusPending)
+	authorization.ExpiresAt = time.Now().Add(-time.Second)
+
+	deviceAuthRepo.On("FindByUserCode", "WDJB-MJHT").Return(authorization, nil)
+
+	_, err := NewDecideDeviceAuthorizationUseCase(deviceAuthRepo).Execute(t.Context(), DecideDeviceAuthorizationInput{UserID: uuid.New(), UserCode: "WDJB-MJHT", Approve: true})
+
+	assert.ErrorIs(t, err, deviceauth.ErrUserCodeNotFound)
+	deviceAuthRepo.AssertNotCalled(t, "Decide", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRequestDeviceAuthorization_Success(t *testing.T) {
+
+	deviceAuthRepo := new(mocks.MockDeviceAuthorizationRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+
+	mockSecurity.On("GenerateSecureRandomString", 32).Return("device-code", nil)
+	mockSecurity.On("HashOTP", "device-code").Return("hashed-device-code")
+	deviceAuthRepo.On("Create", mock.MatchedBy(func(a *deviceauth.DeviceAuthorization) bool {
+		return a.DeviceCodeHash == "hashed-device-code" && a.ClientID == "jamlink-tv" && a.Status == deviceauth.StatusPending
+	})).Return(nil)
+
+	output, err := NewRequestDeviceAuthorizationUseCase(deviceAuthRepo, mockSecurity, "https://jamlink.app/device").Execute(t.Context(), RequestDeviceAuthorizationInput{ClientID: "jamlink-tv"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "device-code", output.DeviceCode)
+	assert.Regexp(t, `^[B-Z]{4}-[B-Z]{4}$`, output.UserCode)
+	assert.Equal(t, "https://jamlink.app/device?user_code="+output.UserCode, output.VerificationURIComplete)
+	assert.Equal(t, 600, output.ExpiresIn)
+	assert.Equal(t, 5, output.Interval)
+}
@@ -1,27 +1,32 @@
 package useCase
 
-import "jamlink-backend/internal/modules/auth/domain/token"
+import (
+	"context"
+	"jamlink-backend/internal/modules/auth/domain/token"
+	"jamlink-backend/internal/shared/security"
+)
 
 type DisconnectUserUseCase struct {
 	tokenRepo token.TokenRepository
+	security  security.SecurityService
 }
 
 type DisconnectUserInput struct {
 	RefreshToken string
 }
 
-func NewDisconnectUserUseCase(tokenRepo token.TokenRepository) *DisconnectUserUseCase {
-	return &DisconnectUserUseCase{tokenRepo}
+func NewDisconnectUserUseCase(tokenRepo token.TokenRepository, security security.SecurityService) *DisconnectUserUseCase {
+	return &DisconnectUserUseCase{tokenRepo, security}
 }
 
-func (uc *DisconnectUserUseCase) Execute(input *DisconnectUserInput) error {
-	foundRefreshToken, err := uc.tokenRepo.FindByToken(input.RefreshToken)
+func (uc *DisconnectUserUseCase) Execute(ctx context.Context, input *DisconnectUserInput) error {
+	foundRefreshToken, err := uc.tokenRepo.FindByToken(ctx, uc.security.HashOTP(input.RefreshToken))
 
 	if err != nil {
 		return err
 	}
 
-	err = uc.tokenRepo.DeleteUserTokens(foundRefreshToken.UserID)
+	err = uc.tokenRepo.DeleteUserTokens(ctx, foundRefreshToken.UserID)
 
 	if err != nil {
 		return err
@@ -0,0 +1,39 @@
+package useCase
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	tokenDomain "jamlink-backend/internal/modules/auth/domain/token"
+	"jamlink-backend/internal/modules/auth/mocks"
+	"testing"
+	"time"
+)
+
+func TestDisconnectUser_RevokesTheUserTokens(t *testing.T) {
+	tokenRepo := new(mocks.MockTokenRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	storedToken := &tokenDomain.Token{ID: uuid.New(), UserID: uuid.New(), Token: "hashed.refresh", ExpiresAt: time.Now().Add(time.Hour)}
+
+	mockSecurity.On("HashOTP", "refresh.jwt").Return("hashed.refresh")
+	tokenRepo.On("FindByToken", "hashed.refresh").Return(storedToken, nil)
+	tokenRepo.On("DeleteUserTokens", storedToken.UserID).Return(nil)
+
+	err := NewDisconnectUserUseCase(tokenRepo, mockSecurity).Execute(t.Context(), &DisconnectUserInput{RefreshToken: "refresh.jwt"})
+
+	assert.NoError(t, err)
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestDisconnectUser_UnknownToken(t *testing.T) {
+	tokenRepo := new(mocks.MockTokenRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+
+	mockSecurity.On("HashOTP", "refresh.jwt").Return("hashed.refresh")
+	tokenRepo.On("FindByToken", "hashed.refresh").Return(nil, tokenDomain.ErrTokenNotFound)
+
+	err := NewDisconnectUserUseCase(tokenRepo, mockSecurity).Execute(t.Context(), &DisconnectUserInput{RefreshToken: "refresh.jwt"})
+
+	assert.ErrorIs(t, err, tokenDomain.ErrTokenNotFound)
+	tokenRepo.AssertNotCalled(t, "DeleteUserTokens", mock.Anything)
+}
@@ -0,0 +1,122 @@
+package useCase
+
+import (
+	"context"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"jamlink-backend/internal/modules/auth/domain/oauthclient"
+	"jamlink-backend/internal/modules/auth/domain/oidc"
+	"jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/shared/security"
+	"strings"
+	"time"
+)
+
+// IDTokenTTL only bounds how long a client may take to read the ID token; it is not a session.
+const IDTokenTTL = 10 * time.Minute
+
+type ExchangeAuthorizationCodeUseCase struct {
+	clientRepo oauthclient.ClientRepository
+	codeRepo   oidc.AuthorizationCodeRepository
+	userRepo   user.UserRepository
+	security   security.SecurityService
+	keys       *security.KeySet
+	issuer     string
+	lifetimes  TokenLifetimes
+}
+
+type ExchangeAuthorizationCodeInput struct {
+	ClientID     string
+	ClientSecret string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+}
+
+func NewExchangeAuthorizationCodeUseCase(clientRepo oauthclient.ClientRepository, codeRepo oidc.AuthorizationCodeRepository, userRepo user.UserRepository, security security.SecurityService, keys *security.KeySet, issuer string, lifetimes TokenLifetimes) *ExchangeAuthorizationCodeUseCase {
+	return &ExchangeAuthorizationCodeUseCase{clientRepo: clientRepo, codeRepo: codeRepo, userRepo: userRepo, security: security, keys: keys, issuer: issuer, lifetimes: lifetimes}
+}
+
+// Execute consumes the code before checking it, so a code is never accepted twice even when the first
+// attempt fails. The access token is only accepted by /oauth/userinfo, never by the API itself.
+func (uc *ExchangeAuthorizationCodeUseCase) Execute(ctx context.Context, input ExchangeAuthorizationCodeInput) (*TokenOutput, error) {
+	client, err := authenticateClient(ctx, uc.clientRepo, uc.security, input.ClientID, input.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := uc.codeRepo.Consume(ctx, uc.security.HashOTP(input.Code))
+	if errors.Is(err, oidc.ErrInvalidCode) {
+		return nil, oidc.ErrInvalidCode
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if code.ClientID != client.ID || code.IsExpired(time.Now()) || code.RedirectURI != input.RedirectURI || !code.VerifyCodeVerifier(input.CodeVerifier) {
+		return nil, oidc.ErrInvalidCode
+	}
+
+	u, err := uc.userRepo.FindByID(ctx, code.UserID)
+	if errors.Is(err, user.ErrUserNotFound) {
+		return nil, oidc.ErrInvalidCode
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := strings.Fields(code.Scopes)
+	auth := &security.AuthContext{Time: code.AuthTime, Methods: strings.Fields(code.AuthMethods)}
+
+	accessToken, err := uc.security.GenerateJWT(ctx, &u.ID, nil, uc.lifetimes.Access, security.PartnerAccessTokenType, u.IsVerified(), auth,
+		security.WithScopes(scopes))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":       uc.issuer,
+		"sub":       u.ID.String(),
+		"aud":       client.ID.String(),
+		"iat":       now.Unix(),
+		"exp":       now.Add(IDTokenTTL).Unix(),
+		"auth_time": code.AuthTime.Unix(),
+		"amr":       auth.Methods,
+	}
+	if code.Nonce != "" {
+		claims["nonce"] = code.Nonce
+	}
+	for name, value := range userInfoClaims(u, scopes) {
+		claims[name] = value
+	}
+
+	idToken, err := uc.keys.Sign(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenOutput{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(uc.lifetimes.Access.Seconds()),
+		Scope:       code.Scopes,
+		IDToken:     idToken,
+	}, nil
+}
+
+// userInfoClaims returns the standard claims released for the granted scopes, shared by the ID token
+// and /oauth/userinfo.
+func userInfoClaims(u *user.User, scopes []string) map[string]interface{} {
+	claims := map[string]interface{}{"sub": u.ID.String()}
+
+	if containsScope(scopes, oidc.ScopeEmail) {
+		claims["email"] = u.Email
+		claims["email_verified"] = u.Verification.IsVerified
+	}
+	if containsScope(scopes, oidc.ScopeProfile) {
+		claims["locale"] = u.PreferredLang
+	}
+
+	return claims
+}
@@ -0,0 +1,133 @@
+package useCase
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"jamlink-backend/internal/modules/auth/domain/oauthclient"
+	"jamlink-backend/internal/modules/auth/domain/oidc"
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/auth/mocks"
+	"jamlink-backend/internal/shared/security"
+	"testing"
+	"time"
+)
+
+const testIssuer = "https://api.jamlink.test"
+
+func newTestKeySet(t *testing.T) (*security.KeySet, *rsa.PrivateKey) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	return security.NewKeySet(security.NewSigningKey(privateKey)), privateKey
+}
+
+func newAuthorizationCode(client *oauthclient.Client, userID uuid.UUID) *oidc.AuthorizationCode {
+	return oidc.CreateAuthorizationCode("hashed-code", client.ID, userID, testRedirectURI, []string{oidc.ScopeOpenID, oidc.ScopeEmail}, "n-0S6_WzA2Mj", testCodeChallenge, time.Now().Add(-time.Minute), []string{security.AuthMethodPassword})
+}
+
+func newExchangeAuthorizationCodeInput(client *oauthclient.Client) ExchangeAuthorizationCodeInput {
+	return ExchangeAuthorizationCodeInput{ClientID: client.ID.String(), ClientSecret: "secret", Code: "authorization-code", RedirectURI: testRedirectURI, CodeVerifier: testCodeVerifier}
+}
+
+func TestExchangeAuthorizationCode_Success(t *testing.T) {
+	clientRepo := new(mocks.MockOAuthClientRepository)
+	codeRepo := new(mocks.MockAuthorizationCodeRepository)
+	mockRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	keys, privateKey := newTestKeySet(t)
+	client := newRelyingParty()
+	u := &userDomain.User{ID: uuid.New(), Email: "user@example.com", PreferredLang: "fr", Verification: userDomain.UserVerification{IsVerified: true}}
+
+	clientRepo.On("FindByID", client.ID).Return(client, nil)
+	mockSecurity.On("CheckPassword", "secret", "hashed-secret").Return(true)
+	mockSecurity.On("HashOTP", "authorization-code").Return("hashed-code")
+	codeRepo.On("Consume", "hashed-code").Return(newAuthorizationCode(client, u.ID), nil)
+	mockRepo.On("FindByID", u.ID).Return(u, nil)
+	mockSecurity.On("GenerateJWT", &u.ID, (*string)(nil), testLifetimes.Access, security.PartnerAccessTokenType, true, mock.AnythingOfType("*security.AuthContext")).Return("access.jwt", nil)
+
+	output, err := NewExchangeAuthorizationCodeUseCase(clientRepo, codeRepo, mockRepo, mockSecurity, keys, testIssuer, testLifetimes).Execute(t.Context(), newExchangeAuthorizationCodeInput(client))
+
+	require.NoError(t, err)
+	assert.Equal(t, "access.jwt", output.AccessToken)
+	assert.Equal(t, "openid email", output.Scope)
+	assert.Empty(t, output.RefreshToken)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(output.IDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		return &privateKey.PublicKey, nil
+	}, jwt.WithValidMethods([]string{"RS256"}))
+	require.NoError(t, err)
+	assert.Equal(t, testIssuer, claims["iss"])
+	assert.Equal(t, u.ID.String(), claims["sub"])
+	assert.Equal(t, client.ID.String(), claims["aud"])
+	assert.Equal(t, "n-0S6_WzA2Mj", claims["nonce"])
+	assert.Equal(t, "user@example.com", claims["email"])
+	assert.Equal(t, true, claims["email_verified"])
+	assert.NotContains(t, claims, "locale")
+}
+
+func TestExchangeAuthorizationCode_InvalidGrant(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(input *ExchangeAuthorizationCodeInput, code *oidc.AuthorizationCode)
+	}{
+		{"wrong code verifier", func(input *ExchangeAuthorizationCodeInput, _ *oidc.AuthorizationCode) {
+			input.CodeVerifier = "wrong-verifier-wrong-verifier-wrong-verifier"
+		}},
+		{"different redirect uri", func(input *ExchangeAuthorizationCodeInput, _ *oidc.AuthorizationCode) {
+			input.RedirectURI = "https://partner.example.com/other"
+		}},
+		{"code of another client", func(_ *ExchangeAuthorizationCodeInput, code *oidc.AuthorizationCode) {
+			code.ClientID = uuid.New()
+		}},
+		{"expired code", func(_ *ExchangeAuthorizationCodeInput, code *oidc.AuthorizationCode) {
+			code.ExpiresAt = time.Now().Add(-time.Second)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientRepo := new(mocks.MockOAuthClientRepository)
+			codeRepo := new(mocks.MockAuthorizationCodeRepository)
+			mockRepo := new(mocks.MockUserRepository)
+			mockSecurity := new(mocks.MockSecurityService)
+			keys, _ := newTestKeySet(t)
+			client := newRelyingParty()
+			code := newAuthorizationCode(client, uuid.New())
+			input := newExchangeAuthorizationCodeInput(client)
+			tt.modify(&input, code)
+
+			clientRepo.On("FindByID", client.ID).Return(client, nil)
+			mockSecurity.On("CheckPassword", "secret", "hashed-secret").Return(true)
+			mockSecurity.On("HashOTP", "authorization-code").Return("hashed-code")
+			codeRepo.On("Consume", "hashed-code").Return(code, nil)
+
+			output, err := NewExchangeAuthorizationCodeUseCase(clientRepo, codeRepo, mockRepo, mockSecurity, keys, testIssuer, testLifetimes).Execute(t.Context(), input)
+
+			assert.ErrorIs(t, err, oidc.ErrInvalidCode)
+			assert.Nil(t, output)
+			mockSecurity.AssertNotCalled(t, "GenerateJWT", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestExchangeAuthorizationCode_InvalidClientDoesNotConsumeCode(t *testing.T) {
+	clientRepo := new(mocks.MockOAuthClientRepository)
+	codeRepo := new(mocks.MockAuthorizationCodeRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	client := newRelyingParty()
+
+	clientRepo.On("FindByID", client.ID).Return(client, nil)
+	mockSecurity.On("CheckPassword", "secret", "hashed-secret").Return(false)
+
+	output, err := NewExchangeAuthorizationCodeUseCase(clientRepo, codeRepo, new(mocks.MockUserRepository), mockSecurity, nil, testIssuer, testLifetimes).Execute(t.Context(), newExchangeAuthorizationCodeInput(client))
+
+	assert.ErrorIs(t, err, oauthclient.ErrInvalidClient)
+	assert.Nil(t, output)
+	codeRepo.AssertNotCalled(t, "Consume", mock.Anything)
+}
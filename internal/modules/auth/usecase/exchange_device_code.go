@@ -0,0 +1,103 @@
+package useCase
+
+import (
+	"context"
+	"jamlink-backend/internal/modules/auth/domain/deviceauth"
+	tokenDomain "jamlink-backend/internal/modules/auth/domain/token"
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/shared/metrics"
+	"jamlink-backend/internal/shared/security"
+	"time"
+)
+
+type ExchangeDeviceCodeUseCase struct {
+	deviceAuthRepo deviceauth.DeviceAuthorizationRepository
+	userRepo       userDomain.UserRepository
+	tokenRepo      tokenDomain.TokenRepository
+	security       security.SecurityService
+	lifetimes      TokenLifetimes
+}
+
+type ExchangeDeviceCodeInput struct {
+	DeviceCode string
+	ClientID   string
+}
+
+// TokenOutput is the successful token response of RFC 6749 section 5.1.
+type TokenOutput struct {
+	AccessToken  string `json:"access_token" example:"eyJhbGciOi..."`
+	TokenType    string `json:"token_type" example:"Bearer"`
+	ExpiresIn    int    `json:"expires_in" example:"900"`
+	RefreshToken string `json:"refresh_token,omitempty" example:"eyJhbGciOi..."`
+	Scope        string `json:"scope,omitempty" example:"events:write"`
+	IDToken      string `json:"id_token,omitempty" example:"eyJhbGciOiJSUzI1NiIs..."`
+}
+
+func NewExchangeDeviceCodeUseCase(deviceAuthRepo deviceauth.DeviceAuthorizationRepository, userRepo userDomain.UserRepository, tokenRepo tokenDomain.TokenRepository, security security.SecurityService, lifetimes TokenLifetimes) *ExchangeDeviceCodeUseCase {
+	return &ExchangeDeviceCodeUseCase{deviceAuthRepo: deviceAuthRepo, userRepo: userRepo, tokenRepo: tokenRepo, security: security, lifetimes: lifetimes}
+}
+
+// Execute answers a polling device. Until the user decides, it returns ErrAuthorizationPending, or
+// ErrSlowDown with a longer interval when the device polls too fast. Once approved, the session tokens
+// are issued exactly once.
+func (uc *ExchangeDeviceCodeUseCase) Execute(ctx context.Context, input ExchangeDeviceCodeInput) (output *TokenOutput, err error) {
+	now := time.Now()
+
+	authorization, err := uc.deviceAuthRepo.FindByDeviceCodeHash(ctx, uc.security.HashOTP(input.DeviceCode))
+	if err != nil {
+		return nil, err
+	}
+	if authorization.ClientID != input.ClientID {
+		return nil, deviceauth.ErrInvalidDeviceCode
+	}
+
+	if authorization.IsExpired(now) {
+		if err := uc.deviceAuthRepo.Delete(ctx, authorization.ID); err != nil {
+			return nil, err
+		}
+		return nil, deviceauth.ErrExpiredToken
+	}
+
+	switch authorization.Status {
+	case deviceauth.StatusDenied:
+		if err := uc.deviceAuthRepo.Delete(ctx, authorization.ID); err != nil {
+			return nil, err
+		}
+		return nil, deviceauth.ErrAccessDenied
+
+	case deviceauth.StatusPending:
+		interval, pollErr := authorization.Interval, deviceauth.ErrAuthorizationPending
+		if authorization.PolledTooFast(now) {
+			interval, pollErr = interval+deviceauth.SlowDownStep, deviceauth.ErrSlowDown
+		}
+
+		if err := uc.deviceAuthRepo.RecordPoll(ctx, authorization.ID, now, interval); err != nil {
+			return nil, err
+		}
+		return nil, pollErr
+	}
+
+	// Polls are not login attempts: the login is counted once the user has approved the device.
+	defer func() { metrics.RecordLogin(metrics.ProviderDevice, err) }()
+
+	if err := uc.deviceAuthRepo.Consume(ctx, authorization.ID); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, *authorization.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, refreshToken, err := issueSessionTokens(ctx, uc.security, uc.tokenRepo, uc.lifetimes, user, security.NewAuthContext(security.AuthMethodDevice))
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenOutput{
+		AccessToken:  token,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(uc.lifetimes.Access.Seconds()),
+		RefreshToken: refreshToken,
+	}, nil
+}
@@ -0,0 +1,136 @@
+package useCase
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/auth/domain/deviceauth"
+	tokenDomain "jamlink-backend/internal/modules/auth/domain/token"
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/auth/mocks"
+	"jamlink-backend/internal/shared/security"
+	"testing"
+	"time"
+)
+
+func newDeviceAuthorization(status deviceauth.Status) *deviceauth.DeviceAuthorization {
+	authorization := deviceauth.CreateDeviceAuthorization("hashed-device-code", "WDJB-MJHT", "jamlink-cli")
+	authorization.Status = status
+	return authorization
+}
+
+func newExchangeDeviceCode() (*ExchangeDeviceCodeUseCase, *mocks.MockDeviceAuthorizationRepository, *mocks.MockUserRepository, *mocks.MockTokenRepository, *mocks.MockSecurityService) {
+	deviceAuthRepo := new(mocks.MockDeviceAuthorizationRepository)
+	userRepo := new(mocks.MockUserRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+
+	mockSecurity.On("HashOTP", "device-code").Return("hashed-device-code")
+
+	return NewExchangeDeviceCodeUseCase(deviceAuthRepo, userRepo, tokenRepo, mockSecurity, testLifetimes), deviceAuthRepo, userRepo, tokenRepo, mockSecurity
+}
+
+func TestExchangeDeviceCode_AuthorizationPending(t *testing.T) {
+	useCase, deviceAuthRepo, _, _, _ := newExchangeDeviceCode()
+	authorization := newDeviceAuthorization(deviceauth.StatusPending)
+
+	deviceAuthRepo.On("FindByDeviceCodeHash", "hashed-device-code").Return(authorization, nil)
+	deviceAuthRepo.On("RecordPoll", authorization.ID, mock.AnythingOfType("time.Time"), deviceauth.PollInterval).Return(nil)
+
+	output, err := useCase.Execute(t.Context(), ExchangeDeviceCodeInput{DeviceCode: "device-code", ClientID: "jamlink-cli"})
+
+	assert.ErrorIs(t, err, deviceauth.ErrAuthorizationPending)
+	assert.Nil(t, output)
+	deviceAuthRepo.AssertExpectations(t)
+}
+
+func TestExchangeDeviceCode_SlowDown(t *testing.T) {
+	useCase, deviceAuthRepo, _, _, _ := newExchangeDeviceCode()
+	authorization := newDeviceAuthorization(deviceauth.StatusPending)
+	lastPoll := time.Now().Add(-2 * time.Second)
+	authorization.LastPolledAt = &lastPoll
+
+	deviceAuthRepo.On("FindByDeviceCodeHash", "hashed-device-code").Return(authorization, nil)
+	deviceAuthRepo.On("RecordPoll", authorization.ID, mock.AnythingOfType("time.Time"), deviceauth.PollInterval+deviceauth.SlowDownStep).Return(nil)
+
+	_, err := useCase.Execute(t.Context(), ExchangeDeviceCodeInput{DeviceCode: "device-code", ClientID: "jamlink-cli"})
+
+	assert.ErrorIs(t, err, deviceauth.ErrSlowDown)
+	deviceAuthRepo.AssertExpectations(t)
+}
+
+func TestExchangeDeviceCode_ExpiredToken(t *testing.T) {
+	useCase, deviceAuthRepo, _, _, _ := newExchangeDeviceCode()
+	authorization := newDeviceAuthorization(deviceauth.StatusApproved)
+	authorization.ExpiresAt = time.Now().Add(-time.Minute)
+
+	deviceAuthRepo.On("FindByDeviceCodeHash", "hashed-device-code").Return(authorization, nil)
+	deviceAuthRepo.On("Delete", authorization.ID).Return(nil)
+
+	_, err := useCase.Execute(t.Context(), ExchangeDeviceCodeInput{DeviceCode: "device-code", ClientID: "jamlink-cli"})
+
+	assert.ErrorIs(t, err, deviceauth.ErrExpiredToken)
+	deviceAuthRepo.AssertNotCalled(t, "Consume", mock.Anything)
+}
+
+func TestExchangeDeviceCode_AccessDenied(t *testing.T) {
+	useCase, deviceAuthRepo, _, _, _ := newExchangeDeviceCode()
+	authorization := newDeviceAuthorization(deviceauth.StatusDenied)
+
+	deviceAuthRepo.On("FindByDeviceCodeHash", "hashed-device-code").Return(authorization, nil)
+	deviceAuthRepo.On("Delete", authorization.ID).Return(nil)
+
+	_, err := useCase.Execute(t.Context(), ExchangeDeviceCodeInput{DeviceCode: "device-code", ClientID: "jamlink-cli"})
+
+	assert.ErrorIs(t, err, deviceauth.ErrAccessDenied)
+}
+
+func TestExchangeDeviceCode_WrongClient(t *testing.T) {
+	useCase, deviceAuthRepo, _, _, _ := newExchangeDeviceCode()
+	authorization := newDeviceAuthorization(deviceauth.StatusApproved)
+
+	deviceAuthRepo.On("FindByDeviceCodeHash", "hashed-device-code").Return(authorization, nil)
+
+	_, err := useCase.Execute(t.Context(), ExchangeDeviceCodeInput{DeviceCode: "device-code", ClientID: "jamlink-tv"})
+
+	assert.ErrorIs(t, err, deviceauth.ErrInvalidDeviceCode)
+	deviceAuthRepo.AssertNotCalled(t, "Consume", mock.Anything)
+}
+
+func TestExchangeDeviceCode_ApprovedIssuesSession(t *testing.T) {
+	useCase, deviceAuthRepo, userRepo, tokenRepo, mockSecurity := newExchangeDeviceCode()
+	approver := &userDomain.User{ID: uuid.New(), Verification: userDomain.UserVerification{IsVerified: true}}
+	authorization := newDeviceAuthorization(deviceauth.StatusApproved)
+	authorization.UserID = &approver.ID
+
+	deviceAuthRepo.On("FindByDeviceCodeHash", "hashed-device-code").Return(authorization, nil)
+	deviceAuthRepo.On("Consume", authorization.ID).Return(nil)
+	userRepo.On("FindByID", approver.ID).Return(approver, nil)
+	deviceAuth := mock.MatchedBy(func(auth *security.AuthContext) bool {
+		return len(auth.Methods) == 1 && auth.Methods[0] == security.AuthMethodDevice
+	})
+	mockSecurity.On("GenerateJWT", &approver.ID, (*string)(nil), testLifetimes.Access, "login", true, deviceAuth).Return("access.jwt", nil)
+	mockSecurity.On("GenerateJWT", &approver.ID, (*string)(nil), testLifetimes.Refresh, "refresh_token", true, deviceAuth).Return("refresh.jwt", nil)
+	mockSecurity.On("HashOTP", "access.jwt").Return("hashed-access")
+	mockSecurity.On("HashOTP", "refresh.jwt").Return("hashed-refresh")
+	tokenRepo.On("Create", mock.AnythingOfType("*token.Token")).Return(nil)
+
+	output, err := useCase.Execute(t.Context(), ExchangeDeviceCodeInput{DeviceCode: "device-code", ClientID: "jamlink-cli"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &TokenOutput{AccessToken: "access.jwt", TokenType: "Bearer", ExpiresIn: 900, RefreshToken: "refresh.jwt"}, output)
+	tokenRepo.AssertCalled(t, "Create", mock.MatchedBy(func(token *tokenDomain.Token) bool { return token.Token == "hashed-refresh" }))
+}
+
+func TestExchangeDeviceCode_AlreadyConsumed(t *testing.T) {
+	useCase, deviceAuthRepo, userRepo, _, _ := newExchangeDeviceCode()
+	authorization := newDeviceAuthorization(deviceauth.StatusApproved)
+
+	deviceAuthRepo.On("FindByDeviceCodeHash", "hashed-device-code").Return(authorization, nil)
+	deviceAuthRepo.On("Consume", authorization.ID).Return(deviceauth.ErrInvalidDeviceCode)
+
+	_, err := useCase.Execute(t.Context(), ExchangeDeviceCodeInput{DeviceCode: "device-code", ClientID: "jamlink-cli"})
+
+	assert.ErrorIs(t, err, deviceauth.ErrInvalidDeviceCode)
+	userRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+}
@@ -0,0 +1,29 @@
+package useCase
+
+import "context"
+
+// OAuthClientOutput is what the consent screen shows of a client. The secret and the registration
+// details are never exposed.
+type OAuthClientOutput struct {
+	ClientID string `json:"clientId"`
+	Name     string `json:"name" example:"Booking partner"`
+}
+
+type GetOAuthClientUseCase struct {
+	authorize *AuthorizeUseCase
+}
+
+func NewGetOAuthClientUseCase(authorize *AuthorizeUseCase) *GetOAuthClientUseCase {
+	return &GetOAuthClientUseCase{authorize: authorize}
+}
+
+// Execute requires the redirect URI of the authorization request, so a client is only described to
+// the screens it actually redirects to.
+func (uc *GetOAuthClientUseCase) Execute(ctx context.Context, clientID, redirectURI string) (*OAuthClientOutput, error) {
+	client, err := uc.authorize.ResolveClient(ctx, clientID, redirectURI)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OAuthClientOutput{ClientID: client.ID.String(), Name: client.Name}, nil
+}
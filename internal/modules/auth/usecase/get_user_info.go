@@ -0,0 +1,25 @@
+package useCase
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"jamlink-backend/internal/modules/auth/domain/user"
+)
+
+type GetUserInfoUseCase struct {
+	userRepo user.UserRepository
+}
+
+func NewGetUserInfoUseCase(userRepo user.UserRepository) *GetUserInfoUseCase {
+	return &GetUserInfoUseCase{userRepo: userRepo}
+}
+
+// Execute returns the claims of the user released by the scopes of the access token.
+func (uc *GetUserInfoUseCase) Execute(ctx context.Context, userID uuid.UUID, scopes []string) (map[string]interface{}, error) {
+	u, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return userInfoClaims(u, scopes), nil
+}
@@ -0,0 +1,104 @@
+package useCase
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/auth/domain/impersonation"
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/auth/mocks"
+	"jamlink-backend/internal/shared/security"
+	"testing"
+)
+
+func TestImpersonateUser_Success(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	impersonationRepo := new(mocks.MockImpersonationRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+
+	admin := &userDomain.User{ID: uuid.New(), Role: userDomain.RoleAdmin}
+	target := &userDomain.User{ID: uuid.New(), Role: userDomain.RoleUser, Verification: userDomain.UserVerification{IsVerified: true}}
+
+	userRepo.On("FindByID", admin.ID).Return(admin, nil)
+	userRepo.On("FindByID", target.ID).Return(target, nil)
+	impersonationRepo.On("Create", mock.MatchedBy(func(s *impersonation.Session) bool {
+		return s.ActorID == admin.ID && s.UserID == target.ID && s.Reason == "Support ticket #1234"
+	})).Return(nil)
+	// No auth context: the token must never count as a recent authentication.
+	mockSecurity.On("GenerateJWT", &target.ID, (*string)(nil), testLifetimes.Impersonation, "login", true, (*security.AuthContext)(nil)).Return("impersonation.jwt", nil)
+
+	output, err := NewImpersonateUserUseCase(userRepo, impersonationRepo, mockSecurity, testLifetimes).Execute(t.Context(), ImpersonateUserInput{
+		ActorID: admin.ID,
+		UserID:  target.ID.String(),
+		Reason:  "  Support ticket #1234 ",
+	})
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, output) {
+		assert.Equal(t, "impersonation.jwt", output.Token)
+		assert.Equal(t, 900, output.ExpiresIn)
+		assert.NotEqual(t, uuid.Nil, output.SessionID)
+	}
+	impersonationRepo.AssertExpectations(t)
+	mockSecurity.AssertExpectations(t)
+}
+
+func TestImpersonateUser_RequiresAdmin(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	impersonationRepo := new(mocks.MockImpersonationRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+
+	actor := &userDomain.User{ID: uuid.New(), Role: userDomain.RoleUser}
+	userRepo.On("FindByID", actor.ID).Return(actor, nil)
+
+	output, err := NewImpersonateUserUseCase(userRepo, impersonationRepo, mockSecurity, testLifetimes).Execute(t.Context(), ImpersonateUserInput{
+		ActorID: actor.ID,
+		UserID:  uuid.New().String(),
+		Reason:  "curious",
+	})
+
+	assert.ErrorIs(t, err, impersonation.ErrImpersonationForbidden)
+	assert.Nil(t, output)
+	impersonationRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestImpersonateUser_CannotImpersonateAdmin(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	impersonationRepo := new(mocks.MockImpersonationRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+
+	admin := &userDomain.User{ID: uuid.New(), Role: userDomain.RoleAdmin}
+	otherAdmin := &userDomain.User{ID: uuid.New(), Role: userDomain.RoleAdmin}
+	userRepo.On("FindByID", admin.ID).Return(admin, nil)
+	userRepo.On("FindByID", otherAdmin.ID).Return(otherAdmin, nil)
+
+	output, err := NewImpersonateUserUseCase(userRepo, impersonationRepo, mockSecurity, testLifetimes).Execute(t.Context(), ImpersonateUserInput{
+		ActorID: admin.ID,
+		UserID:  otherAdmin.ID.String(),
+		Reason:  "debugging",
+	})
+
+	assert.ErrorIs(t, err, impersonation.ErrCannotImpersonateAdmin)
+	assert.Nil(t, output)
+	mockSecurity.AssertNotCalled(t, "GenerateJWT", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestImpersonateUser_ReasonRequired(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	impersonationRepo := new(mocks.MockImpersonationRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+
+	admin := &userDomain.User{ID: uuid.New(), Role: userDomain.RoleAdmin}
+	target := &userDomain.User{ID: uuid.New()}
+	userRepo.On("FindByID", admin.ID).Return(admin, nil)
+	userRepo.On("FindByID", target.ID).Return(target, nil)
+
+	output, err := NewImpersonateUserUseCase(userRepo, impersonationRepo, mockSecurity, testLifetimes).Execute(t.Context(), ImpersonateUserInput{
+		ActorID: admin.ID,
+		UserID:  target.ID.String(),
+		Reason:  "   ",
+	})
+
+	assert.ErrorIs(t, err, impersonation.ErrReasonRequired)
+	assert.Nil(t, output)
+}
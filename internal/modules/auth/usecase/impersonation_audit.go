@@ -0,0 +1,20 @@
+package useCase
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"jamlink-backend/internal/modules/auth/domain/impersonation"
+)
+
+// ImpersonationAudit writes the requests made with impersonation tokens to the audit log.
+type ImpersonationAudit struct {
+	impersonationRepo impersonation.ImpersonationRepository
+}
+
+func NewImpersonationAudit(impersonationRepo impersonation.ImpersonationRepository) *ImpersonationAudit {
+	return &ImpersonationAudit{impersonationRepo: impersonationRepo}
+}
+
+func (a *ImpersonationAudit) RecordImpersonatedRequest(ctx context.Context, sessionID uuid.UUID, method, path string, status int, ip string) error {
+	return a.impersonationRepo.AppendAudit(ctx, impersonation.CreateAuditEntry(sessionID, method, path, status, ip))
+}
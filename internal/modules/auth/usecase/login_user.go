@@ -1,28 +1,31 @@
 package useCase
 
 import (
-	"errors"
+	"context"
+	"github.com/google/uuid"
 	tokenDomain "jamlink-backend/internal/modules/auth/domain/token"
 	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/shared/metrics"
 	"jamlink-backend/internal/shared/security"
-	"time"
 )
 
-var (
-	ErrInvalidEmailOrPassword = errors.New("invalid email or password")
-)
+// ErrInvalidEmailOrPassword is the error of a wrong password too, so that the response does not tell
+// whether an account exists.
+var ErrInvalidEmailOrPassword = security.ErrPasswordComparison
 
 type LoginUserUseCase struct {
 	userRepo  userDomain.UserRepository
 	security  security.SecurityService
 	tokenRepo tokenDomain.TokenRepository
+	lifetimes TokenLifetimes
 }
 
-func NewLoginUserUseCase(userRepo userDomain.UserRepository, security security.SecurityService, tokenRepo tokenDomain.TokenRepository) *LoginUserUseCase {
+func NewLoginUserUseCase(userRepo userDomain.UserRepository, security security.SecurityService, tokenRepo tokenDomain.TokenRepository, lifetimes TokenLifetimes) *LoginUserUseCase {
 	return &LoginUserUseCase{
 		userRepo,
 		security,
 		tokenRepo,
+		lifetimes,
 	}
 }
 
@@ -33,50 +36,27 @@ type LoginUserInput struct {
 }
 
 type LoginUserOutput struct {
-	Token        string `json:"token"`
-	RefreshToken string `json:"-"`
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"-"`
+	UserID       uuid.UUID `json:"-"`
 }
 
-func (uc *LoginUserUseCase) Execute(input LoginUserInput) (*LoginUserOutput, error) {
-	user, err := uc.userRepo.FindByEmail(input.Email)
+func (uc *LoginUserUseCase) Execute(ctx context.Context, input LoginUserInput) (output *LoginUserOutput, err error) {
+	defer func() { metrics.RecordLogin(metrics.ProviderPassword, err) }()
+
+	user, err := uc.userRepo.FindByEmail(ctx, input.Email)
 	if err != nil {
 		return nil, ErrInvalidEmailOrPassword
 	}
 
-	if !uc.security.CheckPassword(input.Password, user.Password) {
+	if !uc.security.CheckPassword(ctx, input.Password, user.Password) {
 		return nil, security.ErrPasswordComparison
 	}
 
-	const tokenExpiringTime = time.Minute * 15
-
-	token, err := uc.security.GenerateJWT(&user.ID, nil, tokenExpiringTime, "login", user.Verification.IsVerified)
-
-	if err != nil {
-		return nil, err
-	}
-	inDBToken, err := tokenDomain.CreateToken(user.ID, token, time.Now().Add(tokenExpiringTime))
-	if err != nil {
-		return nil, err
-	}
-	err = uc.tokenRepo.Create(inDBToken)
-	if err != nil {
-		return nil, err
-	}
-
-	const refreshTokenExpiringTime = time.Hour * 24 * 7
-	refreshToken, err := uc.security.GenerateJWT(&user.ID, nil, refreshTokenExpiringTime, "refresh_token", user.Verification.IsVerified)
-	if err != nil {
-		return nil, err
-	}
-
-	inDBREfreshToken, err := tokenDomain.CreateToken(user.ID, refreshToken, time.Now().Add(refreshTokenExpiringTime))
-	if err != nil {
-		return nil, err
-	}
-	err = uc.tokenRepo.Create(inDBREfreshToken)
+	token, refreshToken, err := issueSessionTokens(ctx, uc.security, uc.tokenRepo, uc.lifetimes, user, security.NewAuthContext(security.AuthMethodPassword))
 	if err != nil {
 		return nil, err
 	}
 
-	return &LoginUserOutput{Token: token, RefreshToken: refreshToken}, nil
+	return &LoginUserOutput{Token: token, RefreshToken: refreshToken, UserID: user.ID}, nil
 }
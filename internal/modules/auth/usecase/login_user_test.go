@@ -15,6 +15,15 @@ import (
 	"jamlink-backend/internal/modules/auth/mocks"
 )
 
+// testLifetimes are the token lifetimes of config.Default.
+var testLifetimes = TokenLifetimes{
+	Access:            15 * time.Minute,
+	Refresh:           7 * 24 * time.Hour,
+	ResetPassword:     15 * time.Minute,
+	Impersonation:     15 * time.Minute,
+	ClientCredentials: time.Hour,
+}
+
 func TestLoginUser_Success(t *testing.T) {
 	mockSecurity := new(mocks.MockSecurityService)
 	userRepo := new(mocks.MockUserRepository)
@@ -41,19 +50,21 @@ func TestLoginUser_Success(t *testing.T) {
 	userRepo.On("FindByEmail", input.Email).Return(createdUser, nil)
 	mockSecurity.On("CheckPassword", input.Password, createdUser.Password).Return(true)
 
-	mockSecurity.On("GenerateJWT", &createdUser.ID, (*string)(nil), time.Minute*15, "login", createdUser.Verification.IsVerified).Return(accessToken, nil)
+	mockSecurity.On("GenerateJWT", &createdUser.ID, (*string)(nil), time.Minute*15, "login", createdUser.Verification.IsVerified, mock.AnythingOfType("*security.AuthContext")).Return(accessToken, nil)
+	mockSecurity.On("HashOTP", accessToken).Return("hashed_access_token")
 	tokenRepo.On("Create", mock.MatchedBy(func(token *tokenDomain.Token) bool {
-		return token.UserID == createdUser.ID && token.Token == accessToken
+		return token.UserID == createdUser.ID && token.Token == "hashed_access_token"
 	})).Return(nil)
 
-	mockSecurity.On("GenerateJWT", &createdUser.ID, (*string)(nil), expiringTimeForRefreshToken, "refresh_token", createdUser.Verification.IsVerified).Return(refreshToken, nil)
+	mockSecurity.On("GenerateJWT", &createdUser.ID, (*string)(nil), expiringTimeForRefreshToken, "refresh_token", createdUser.Verification.IsVerified, mock.AnythingOfType("*security.AuthContext")).Return(refreshToken, nil)
 
+	mockSecurity.On("HashOTP", refreshToken).Return("hashed_refresh_token")
 	tokenRepo.On("Create", mock.MatchedBy(func(token *tokenDomain.Token) bool {
-		return token.UserID == createdUser.ID && token.Token == refreshToken
+		return token.UserID == createdUser.ID && token.Token == "hashed_refresh_token"
 	})).Return(nil)
 
-	usecase := NewLoginUserUseCase(userRepo, mockSecurity, tokenRepo)
-	output, err := usecase.Execute(input)
+	usecase := NewLoginUserUseCase(userRepo, mockSecurity, tokenRepo, testLifetimes)
+	output, err := usecase.Execute(t.Context(), input)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, output)
@@ -84,8 +95,8 @@ func TestLoginUser_InvalidPassword(t *testing.T) {
 	userRepo.On("FindByEmail", input.Email).Return(user, nil)
 	mockSecurity.On("CheckPassword", input.Password, user.Password).Return(false)
 
-	usecase := NewLoginUserUseCase(userRepo, mockSecurity, tokenRepo)
-	output, err := usecase.Execute(input)
+	usecase := NewLoginUserUseCase(userRepo, mockSecurity, tokenRepo, testLifetimes)
+	output, err := usecase.Execute(t.Context(), input)
 
 	assert.Error(t, err)
 	assert.Nil(t, output)
@@ -105,8 +116,8 @@ func TestLoginUser_UserNotFound(t *testing.T) {
 
 	userRepo.On("FindByEmail", input.Email).Return(nil, errors.New("not found"))
 
-	usecase := NewLoginUserUseCase(userRepo, mockSecurity, tokenRepo)
-	output, err := usecase.Execute(input)
+	usecase := NewLoginUserUseCase(userRepo, mockSecurity, tokenRepo, testLifetimes)
+	output, err := usecase.Execute(t.Context(), input)
 
 	assert.Error(t, err)
 	assert.Nil(t, output)
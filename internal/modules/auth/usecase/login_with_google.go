@@ -2,58 +2,86 @@ package useCase
 
 import (
 	"context"
-	"errors"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
 	"google.golang.org/api/idtoken"
+	"jamlink-backend/internal/modules/auth/domain/transaction"
 	user2 "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/shared/apperror"
+	"jamlink-backend/internal/shared/metrics"
 	"jamlink-backend/internal/shared/security"
-	"os"
-	"time"
 )
 
 type LoginUserWithGoogleInput struct {
 	IDToken       string `json:"id_token" binding:"required"`
+	InviteCode    string `json:"inviteCode,omitempty"`
 	PreferredLang string `gorm:"type:varchar(5);default:'en'" json:"-"`
 }
 
 type LoginUserWithGoogleOutput struct {
-	Token        string `json:"token"`
-	RefreshToken string `json:"-"`
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"-"`
+	UserID       uuid.UUID `json:"-"`
 }
 
+var (
+	ErrInvalidGoogleToken = apperror.Unauthorized("invalid_google_token", "invalid Google token")
+	ErrGoogleTokenNoEmail = apperror.Unauthorized("google_token_missing_email", "email not found in Google token")
+)
+
+var tracer = otel.Tracer("jamlink-backend/internal/modules/auth/usecase")
+
 type LoginUserWithGoogleUseCase struct {
-	repo     user2.UserRepository
-	security security.SecurityService
+	repo      user2.UserRepository
+	uow       transaction.UnitOfWork
+	security  security.SecurityService
+	gate      RegistrationGate
+	clientID  string
+	lifetimes TokenLifetimes
 }
 
-func NewLoginUserWithGoogleUseCase(repo user2.UserRepository, security security.SecurityService) *LoginUserWithGoogleUseCase {
+// NewLoginUserWithGoogleUseCase accepts the ID tokens Google issued to clientID.
+func NewLoginUserWithGoogleUseCase(repo user2.UserRepository, uow transaction.UnitOfWork, security security.SecurityService, gate RegistrationGate, clientID string, lifetimes TokenLifetimes) *LoginUserWithGoogleUseCase {
 	return &LoginUserWithGoogleUseCase{
-		repo:     repo,
-		security: security,
+		repo:      repo,
+		uow:       uow,
+		security:  security,
+		gate:      gate,
+		clientID:  clientID,
+		lifetimes: lifetimes,
 	}
 }
 
-func (uc *LoginUserWithGoogleUseCase) Execute(input LoginUserWithGoogleInput) (*LoginUserWithGoogleOutput, error) {
-	payload, err := idtoken.Validate(context.Background(), input.IDToken, os.Getenv("GOOGLE_CLIENT_ID"))
+func (uc *LoginUserWithGoogleUseCase) Execute(ctx context.Context, input LoginUserWithGoogleInput) (output *LoginUserWithGoogleOutput, err error) {
+	defer func() { metrics.RecordLogin(metrics.ProviderGoogle, err) }()
+
+	payload, err := uc.validateIDToken(ctx, input.IDToken)
 
 	if err != nil {
-		return nil, errors.New("invalid Google token")
+		return nil, ErrInvalidGoogleToken
 	}
 
 	email, ok := payload.Claims["email"].(string)
 
 	if !ok {
-		return nil, errors.New("email not found in Google token")
+		return nil, ErrGoogleTokenNoEmail
 	}
 
-	user, err := uc.repo.FindByEmail(email)
+	user, err := uc.repo.FindByEmail(ctx, email)
 
 	if err != nil {
+		// A first Google sign-in creates the account, so it is subject to the registration mode.
+		if err := uc.gate.Check(ctx, input.InviteCode); err != nil {
+			return nil, err
+		}
+
 		randomPassword, err := uc.security.GenerateSecureRandomString(32)
 		if err != nil {
 			return nil, err
 		}
 
-		hashed, err := uc.security.HashPassword(randomPassword)
+		hashed, err := uc.security.HashPassword(ctx, randomPassword)
 		if err != nil {
 			return nil, err
 		}
@@ -63,18 +91,27 @@ func (uc *LoginUserWithGoogleUseCase) Execute(input LoginUserWithGoogleInput) (*
 			return nil, err
 		}
 
-		err = uc.repo.Create(user)
+		// As in CreateUserUseCase, the code is only consumed if the account is created.
+		err = uc.uow.Do(ctx, func(repos transaction.Repositories) error {
+			if err := uc.gate.Redeem(ctx, repos.Invitations, input.InviteCode, user.ID); err != nil {
+				return err
+			}
+			return repos.Users.Create(ctx, user)
+		})
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	token, err := uc.security.GenerateJWT(&user.ID, nil, time.Minute*15, "login", user.Verification.IsVerified)
+	auth := security.NewAuthContext(security.AuthMethodGoogle)
+	isVerified, claimOpts := verificationClaims(user)
+
+	token, err := uc.security.GenerateJWT(ctx, &user.ID, nil, uc.lifetimes.Access, security.AccessTokenType, isVerified, auth, claimOpts...)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := uc.security.GenerateJWT(&user.ID, nil, time.Hour*24*7, "refresh_token", user.Verification.IsVerified)
+	refreshToken, err := uc.security.GenerateJWT(ctx, &user.ID, nil, uc.lifetimes.Refresh, security.RefreshTokenType, isVerified, auth, claimOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -82,5 +119,20 @@ func (uc *LoginUserWithGoogleUseCase) Execute(input LoginUserWithGoogleInput) (*
 	return &LoginUserWithGoogleOutput{
 		Token:        token,
 		RefreshToken: refreshToken,
+		UserID:       user.ID,
 	}, nil
 }
+
+// validateIDToken traces the validation, which may fetch Google's signing keys.
+func (uc *LoginUserWithGoogleUseCase) validateIDToken(ctx context.Context, idToken string) (*idtoken.Payload, error) {
+	ctx, span := tracer.Start(ctx, "idtoken.Validate")
+	defer span.End()
+
+	payload, err := idtoken.Validate(ctx, idToken, uc.clientID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return payload, err
+}
@@ -1,12 +1,15 @@
 package useCase
 
 import (
+	"context"
 	"errors"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/auth/domain/transaction"
 	userDomain "jamlink-backend/internal/modules/auth/domain/user"
 	"jamlink-backend/internal/modules/auth/mocks"
+	"jamlink-backend/internal/shared/security"
 	"testing"
 )
 
@@ -17,7 +20,7 @@ type MockLoginUserWithGoogleUseCase struct {
 }
 
 // executeWithMockValidation replaces the token validation logic for testing
-func (uc *MockLoginUserWithGoogleUseCase) Execute(input LoginUserWithGoogleInput) (*LoginUserWithGoogleOutput, error) {
+func (uc *MockLoginUserWithGoogleUseCase) Execute(ctx context.Context, input LoginUserWithGoogleInput) (*LoginUserWithGoogleOutput, error) {
 	// Use our mock validation function instead of the real idtoken.Validate
 	email, err := uc.validateTokenFunc(input.IDToken)
 	if err != nil {
@@ -28,15 +31,19 @@ func (uc *MockLoginUserWithGoogleUseCase) Execute(input LoginUserWithGoogleInput
 		return nil, errors.New("email not found in Google token")
 	}
 
-	user, err := uc.repo.FindByEmail(email)
+	user, err := uc.repo.FindByEmail(ctx, email)
 
 	if err != nil {
+		if err := uc.gate.Check(ctx, input.InviteCode); err != nil {
+			return nil, err
+		}
+
 		randomPassword, err := uc.security.GenerateSecureRandomString(32)
 		if err != nil {
 			return nil, err
 		}
 
-		hashed, err := uc.security.HashPassword(randomPassword)
+		hashed, err := uc.security.HashPassword(ctx, randomPassword)
 		if err != nil {
 			return nil, err
 		}
@@ -46,18 +53,25 @@ func (uc *MockLoginUserWithGoogleUseCase) Execute(input LoginUserWithGoogleInput
 			return nil, err
 		}
 
-		err = uc.repo.Create(user)
+		err = uc.uow.Do(ctx, func(repos transaction.Repositories) error {
+			if err := uc.gate.Redeem(ctx, repos.Invitations, input.InviteCode, user.ID); err != nil {
+				return err
+			}
+			return repos.Users.Create(ctx, user)
+		})
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	token, err := uc.security.GenerateJWT(&user.ID, nil, 15, "login", user.Verification.IsVerified)
+	isVerified, claimOpts := verificationClaims(user)
+
+	token, err := uc.security.GenerateJWT(ctx, &user.ID, nil, 15, "login", isVerified, security.NewAuthContext(security.AuthMethodGoogle), claimOpts...)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := uc.security.GenerateJWT(&user.ID, nil, 24*7, "refresh_token", user.Verification.IsVerified)
+	refreshToken, err := uc.security.GenerateJWT(ctx, &user.ID, nil, 24*7, "refresh_token", isVerified, security.NewAuthContext(security.AuthMethodGoogle), claimOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -94,16 +108,18 @@ func TestLoginUserWithGoogle_Success_ExistingUser(t *testing.T) {
 		mock.AnythingOfType("*string"),
 		mock.AnythingOfType("Duration"),
 		"login",
-		true).Return(jwtToken, nil)
+		true,
+		mock.AnythingOfType("*security.AuthContext")).Return(jwtToken, nil)
 	mockSecurity.On("GenerateJWT",
 		mock.MatchedBy(func(id *uuid.UUID) bool { return *id == userID }),
 		mock.AnythingOfType("*string"),
 		mock.AnythingOfType("Duration"),
 		"refresh_token",
-		true).Return(refreshToken, nil)
+		true,
+		mock.AnythingOfType("*security.AuthContext")).Return(refreshToken, nil)
 
 	// Create the use case with our mock validation
-	baseUseCase := NewLoginUserWithGoogleUseCase(mockUserRepo, mockSecurity)
+	baseUseCase := NewLoginUserWithGoogleUseCase(mockUserRepo, mocks.NewMockUnitOfWork(mockUserRepo, nil), mockSecurity, openRegistrationGate(), "", testLifetimes)
 	useCase := &MockLoginUserWithGoogleUseCase{
 		LoginUserWithGoogleUseCase: baseUseCase,
 		validateTokenFunc: func(idToken string) (string, error) {
@@ -113,7 +129,7 @@ func TestLoginUserWithGoogle_Success_ExistingUser(t *testing.T) {
 	}
 
 	// Act
-	output, err := useCase.Execute(LoginUserWithGoogleInput{
+	output, err := useCase.Execute(t.Context(), LoginUserWithGoogleInput{
 		IDToken:       idToken,
 		PreferredLang: "en",
 	})
@@ -149,16 +165,18 @@ func TestLoginUserWithGoogle_Success_NewUser(t *testing.T) {
 		mock.AnythingOfType("*string"),
 		mock.AnythingOfType("Duration"),
 		"login",
-		false).Return(jwtToken, nil)
+		false,
+		mock.AnythingOfType("*security.AuthContext")).Return(jwtToken, nil)
 	mockSecurity.On("GenerateJWT",
 		mock.AnythingOfType("*uuid.UUID"),
 		mock.AnythingOfType("*string"),
 		mock.AnythingOfType("Duration"),
 		"refresh_token",
-		false).Return(refreshToken, nil)
+		false,
+		mock.AnythingOfType("*security.AuthContext")).Return(refreshToken, nil)
 
 	// Create the use case with our mock validation
-	baseUseCase := NewLoginUserWithGoogleUseCase(mockUserRepo, mockSecurity)
+	baseUseCase := NewLoginUserWithGoogleUseCase(mockUserRepo, mocks.NewMockUnitOfWork(mockUserRepo, nil), mockSecurity, openRegistrationGate(), "", testLifetimes)
 	useCase := &MockLoginUserWithGoogleUseCase{
 		LoginUserWithGoogleUseCase: baseUseCase,
 		validateTokenFunc: func(idToken string) (string, error) {
@@ -168,7 +186,7 @@ func TestLoginUserWithGoogle_Success_NewUser(t *testing.T) {
 	}
 
 	// Act
-	output, err := useCase.Execute(LoginUserWithGoogleInput{
+	output, err := useCase.Execute(t.Context(), LoginUserWithGoogleInput{
 		IDToken:       idToken,
 		PreferredLang: "en",
 	})
@@ -190,7 +208,7 @@ func TestLoginUserWithGoogle_InvalidToken(t *testing.T) {
 	idToken := "invalid.google.token"
 
 	// Create the use case with our mock validation
-	baseUseCase := NewLoginUserWithGoogleUseCase(mockUserRepo, mockSecurity)
+	baseUseCase := NewLoginUserWithGoogleUseCase(mockUserRepo, mocks.NewMockUnitOfWork(mockUserRepo, nil), mockSecurity, openRegistrationGate(), "", testLifetimes)
 	useCase := &MockLoginUserWithGoogleUseCase{
 		LoginUserWithGoogleUseCase: baseUseCase,
 		validateTokenFunc: func(idToken string) (string, error) {
@@ -200,7 +218,7 @@ func TestLoginUserWithGoogle_InvalidToken(t *testing.T) {
 	}
 
 	// Act
-	output, err := useCase.Execute(LoginUserWithGoogleInput{
+	output, err := useCase.Execute(t.Context(), LoginUserWithGoogleInput{
 		IDToken:       idToken,
 		PreferredLang: "en",
 	})
@@ -221,7 +239,7 @@ func TestLoginUserWithGoogle_MissingEmail(t *testing.T) {
 	idToken := "valid.google.token.without.email"
 
 	// Create the use case with our mock validation
-	baseUseCase := NewLoginUserWithGoogleUseCase(mockUserRepo, mockSecurity)
+	baseUseCase := NewLoginUserWithGoogleUseCase(mockUserRepo, mocks.NewMockUnitOfWork(mockUserRepo, nil), mockSecurity, openRegistrationGate(), "", testLifetimes)
 	useCase := &MockLoginUserWithGoogleUseCase{
 		LoginUserWithGoogleUseCase: baseUseCase,
 		validateTokenFunc: func(idToken string) (string, error) {
@@ -231,7 +249,7 @@ func TestLoginUserWithGoogle_MissingEmail(t *testing.T) {
 	}
 
 	// Act
-	output, err := useCase.Execute(LoginUserWithGoogleInput{
+	output, err := useCase.Execute(t.Context(), LoginUserWithGoogleInput{
 		IDToken:       idToken,
 		PreferredLang: "en",
 	})
@@ -261,7 +279,7 @@ func TestLoginUserWithGoogle_UserCreationError(t *testing.T) {
 	mockUserRepo.On("Create", mock.AnythingOfType("*user.User")).Return(errors.New("creation error"))
 
 	// Create the use case with our mock validation
-	baseUseCase := NewLoginUserWithGoogleUseCase(mockUserRepo, mockSecurity)
+	baseUseCase := NewLoginUserWithGoogleUseCase(mockUserRepo, mocks.NewMockUnitOfWork(mockUserRepo, nil), mockSecurity, openRegistrationGate(), "", testLifetimes)
 	useCase := &MockLoginUserWithGoogleUseCase{
 		LoginUserWithGoogleUseCase: baseUseCase,
 		validateTokenFunc: func(idToken string) (string, error) {
@@ -271,7 +289,7 @@ func TestLoginUserWithGoogle_UserCreationError(t *testing.T) {
 	}
 
 	// Act
-	output, err := useCase.Execute(LoginUserWithGoogleInput{
+	output, err := useCase.Execute(t.Context(), LoginUserWithGoogleInput{
 		IDToken:       idToken,
 		PreferredLang: "en",
 	})
@@ -283,3 +301,36 @@ func TestLoginUserWithGoogle_UserCreationError(t *testing.T) {
 	mockUserRepo.AssertExpectations(t)
 	mockSecurity.AssertExpectations(t)
 }
+
+func TestLoginUserWithGoogle_NewUserRejectedByRegistrationGate(t *testing.T) {
+	// Arrange
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	mockGate := new(mocks.MockRegistrationGate)
+
+	email := "new.user@example.com"
+	gateErr := errors.New("registrations are closed")
+
+	mockUserRepo.On("FindByEmail", email).Return(nil, errors.New("user not found"))
+	mockGate.On("Check", "").Return(gateErr)
+
+	baseUseCase := NewLoginUserWithGoogleUseCase(mockUserRepo, mocks.NewMockUnitOfWork(mockUserRepo, nil), mockSecurity, mockGate, "", testLifetimes)
+	useCase := &MockLoginUserWithGoogleUseCase{
+		LoginUserWithGoogleUseCase: baseUseCase,
+		validateTokenFunc: func(idToken string) (string, error) {
+			return email, nil
+		},
+	}
+
+	// Act
+	output, err := useCase.Execute(t.Context(), LoginUserWithGoogleInput{
+		IDToken:       "valid.google.token",
+		PreferredLang: "en",
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, gateErr)
+	assert.Nil(t, output)
+	mockUserRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockSecurity.AssertNotCalled(t, "GenerateJWT")
+}
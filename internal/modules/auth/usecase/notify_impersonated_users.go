@@ -0,0 +1,83 @@
+package useCase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"jamlink-backend/internal/modules/auth/domain/impersonation"
+	"jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/shared/email"
+	"strconv"
+	"time"
+)
+
+type NotifyImpersonatedUsersUseCase struct {
+	impersonationRepo impersonation.ImpersonationRepository
+	userRepo          user.UserRepository
+	emailService      email.EmailService
+}
+
+type NotifyImpersonatedUsersInput struct {
+	Now       time.Time
+	BatchSize int
+}
+
+type NotifyImpersonatedUsersOutput struct {
+	Notified int
+	Failed   int
+}
+
+func NewNotifyImpersonatedUsersUseCase(impersonationRepo impersonation.ImpersonationRepository, userRepo user.UserRepository, emailService email.EmailService) *NotifyImpersonatedUsersUseCase {
+	return &NotifyImpersonatedUsersUseCase{impersonationRepo: impersonationRepo, userRepo: userRepo, emailService: emailService}
+}
+
+// Execute tells users about the impersonation sessions that ended, with the reason given by the admin
+// and the number of requests made. Failures on a single session are collected and do not stop the batch.
+func (uc *NotifyImpersonatedUsersUseCase) Execute(ctx context.Context, input NotifyImpersonatedUsersInput) (*NotifyImpersonatedUsersOutput, error) {
+	sessions, err := uc.impersonationRepo.FindEndedUnnotified(ctx, input.Now, input.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &NotifyImpersonatedUsersOutput{}
+	var errs []error
+
+	for _, session := range sessions {
+		if err := uc.notify(ctx, session, input.Now); err != nil {
+			output.Failed++
+			errs = append(errs, fmt.Errorf("notify impersonation session %s: %w", session.ID, err))
+			continue
+		}
+		output.Notified++
+	}
+
+	return output, errors.Join(errs...)
+}
+
+func (uc *NotifyImpersonatedUsersUseCase) notify(ctx context.Context, session *impersonation.Session, now time.Time) error {
+	impersonated, err := uc.userRepo.FindByID(ctx, session.UserID)
+
+	// The account was deleted in the meantime: there is nobody left to tell.
+	if errors.Is(err, user.ErrUserNotFound) {
+		return uc.impersonationRepo.MarkNotified(ctx, session.ID, now)
+	}
+	if err != nil {
+		return err
+	}
+
+	requests, err := uc.impersonationRepo.CountAudit(ctx, session.ID)
+	if err != nil {
+		return err
+	}
+
+	err = uc.emailService.Send(ctx, impersonated.Email, email.TemplateImpersonationNotice, impersonated.PreferredLang, map[string]string{
+		"DATE":     email.FormatDateTime(session.CreatedAt, impersonated.PreferredLang),
+		"REASON":   session.Reason,
+		"REQUESTS": strconv.FormatInt(requests, 10),
+	})
+	if err != nil {
+		return err
+	}
+
+	return uc.impersonationRepo.MarkNotified(ctx, session.ID, now)
+}
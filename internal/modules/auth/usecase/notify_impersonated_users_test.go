@@ -0,0 +1,87 @@
+package useCase
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/auth/domain/impersonation"
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/auth/mocks"
+	"jamlink-backend/internal/shared/email"
+	"testing"
+	"time"
+)
+
+func TestNotifyImpersonatedUsers_SendsSummary(t *testing.T) {
+	impersonationRepo := new(mocks.MockImpersonationRepository)
+	userRepo := new(mocks.MockUserRepository)
+	mockEmail := new(mocks.MockEmailService)
+
+	now := time.Now()
+	impersonated := &userDomain.User{ID: uuid.New(), Email: "user@example.com", PreferredLang: "fr-FR"}
+	session := &impersonation.Session{
+		ID:        uuid.New(),
+		UserID:    impersonated.ID,
+		Reason:    "Support ticket #1234",
+		CreatedAt: time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC),
+	}
+
+	impersonationRepo.On("FindEndedUnnotified", now, 100).Return([]*impersonation.Session{session}, nil)
+	userRepo.On("FindByID", impersonated.ID).Return(impersonated, nil)
+	impersonationRepo.On("CountAudit", session.ID).Return(int64(12), nil)
+	mockEmail.On("Send", "user@example.com", email.TemplateImpersonationNotice, "fr-FR", map[string]string{
+		"DATE":     "14/03/2026 09:30 UTC",
+		"REASON":   "Support ticket #1234",
+		"REQUESTS": "12",
+	}).Return(nil)
+	impersonationRepo.On("MarkNotified", session.ID, now).Return(nil)
+
+	output, err := NewNotifyImpersonatedUsersUseCase(impersonationRepo, userRepo, mockEmail).Execute(t.Context(), NotifyImpersonatedUsersInput{Now: now, BatchSize: 100})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, output.Notified)
+	impersonationRepo.AssertExpectations(t)
+	mockEmail.AssertExpectations(t)
+}
+
+func TestNotifyImpersonatedUsers_DeletedUserIsSkipped(t *testing.T) {
+	impersonationRepo := new(mocks.MockImpersonationRepository)
+	userRepo := new(mocks.MockUserRepository)
+	mockEmail := new(mocks.MockEmailService)
+
+	now := time.Now()
+	session := &impersonation.Session{ID: uuid.New(), UserID: uuid.New()}
+
+	impersonationRepo.On("FindEndedUnnotified", now, 100).Return([]*impersonation.Session{session}, nil)
+	userRepo.On("FindByID", session.UserID).Return(nil, userDomain.ErrUserNotFound)
+	impersonationRepo.On("MarkNotified", session.ID, now).Return(nil)
+
+	output, err := NewNotifyImpersonatedUsersUseCase(impersonationRepo, userRepo, mockEmail).Execute(t.Context(), NotifyImpersonatedUsersInput{Now: now, BatchSize: 100})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, output.Notified)
+	mockEmail.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNotifyImpersonatedUsers_EmailFailureIsRetried(t *testing.T) {
+	impersonationRepo := new(mocks.MockImpersonationRepository)
+	userRepo := new(mocks.MockUserRepository)
+	mockEmail := new(mocks.MockEmailService)
+
+	now := time.Now()
+	impersonated := &userDomain.User{ID: uuid.New(), Email: "user@example.com"}
+	session := &impersonation.Session{ID: uuid.New(), UserID: impersonated.ID}
+
+	impersonationRepo.On("FindEndedUnnotified", now, 100).Return([]*impersonation.Session{session}, nil)
+	userRepo.On("FindByID", impersonated.ID).Return(impersonated, nil)
+	impersonationRepo.On("CountAudit", session.ID).Return(int64(3), nil)
+	mockEmail.On("Send", "user@example.com", email.TemplateImpersonationNotice, "", mock.Anything).Return(errors.New("brevo down"))
+
+	output, err := NewNotifyImpersonatedUsersUseCase(impersonationRepo, userRepo, mockEmail).Execute(t.Context(), NotifyImpersonatedUsersInput{Now: now, BatchSize: 100})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, output.Failed)
+	// Left unnotified so the next maintenance run tries again.
+	impersonationRepo.AssertNotCalled(t, "MarkNotified", mock.Anything, mock.Anything)
+}
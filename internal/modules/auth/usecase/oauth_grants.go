@@ -0,0 +1,69 @@
+package useCase
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"jamlink-backend/internal/modules/auth/domain/oauthclient"
+	"jamlink-backend/internal/modules/auth/domain/oidc"
+	"strings"
+	"time"
+)
+
+type OAuthGrantOutput struct {
+	ClientID   uuid.UUID `json:"clientId"`
+	ClientName string    `json:"clientName" example:"Booking partner"`
+	Scopes     []string  `json:"scopes" example:"openid,email"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+type ListOAuthGrantsUseCase struct {
+	grantRepo  oidc.GrantRepository
+	clientRepo oauthclient.ClientRepository
+}
+
+func NewListOAuthGrantsUseCase(grantRepo oidc.GrantRepository, clientRepo oauthclient.ClientRepository) *ListOAuthGrantsUseCase {
+	return &ListOAuthGrantsUseCase{grantRepo: grantRepo, clientRepo: clientRepo}
+}
+
+// Execute lists the applications the user consented to. Grants of deleted clients are skipped.
+func (uc *ListOAuthGrantsUseCase) Execute(ctx context.Context, userID uuid.UUID) ([]OAuthGrantOutput, error) {
+	grants, err := uc.grantRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := make([]OAuthGrantOutput, 0, len(grants))
+	for _, grant := range grants {
+		client, err := uc.clientRepo.FindByID(ctx, grant.ClientID)
+		if errors.Is(err, oauthclient.ErrClientNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		outputs = append(outputs, OAuthGrantOutput{
+			ClientID:   client.ID,
+			ClientName: client.Name,
+			Scopes:     strings.Fields(grant.Scopes),
+			UpdatedAt:  grant.UpdatedAt,
+		})
+	}
+
+	return outputs, nil
+}
+
+type RevokeOAuthGrantUseCase struct {
+	grantRepo oidc.GrantRepository
+}
+
+func NewRevokeOAuthGrantUseCase(grantRepo oidc.GrantRepository) *RevokeOAuthGrantUseCase {
+	return &RevokeOAuthGrantUseCase{grantRepo: grantRepo}
+}
+
+// Execute makes the next authorization request of the client show the consent screen again. Tokens
+// already issued expire on their own.
+func (uc *RevokeOAuthGrantUseCase) Execute(ctx context.Context, userID, clientID uuid.UUID) error {
+	return uc.grantRepo.Delete(ctx, userID, clientID)
+}
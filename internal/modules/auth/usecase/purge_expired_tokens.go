@@ -0,0 +1,44 @@
+package useCase
+
+import (
+	"context"
+	"jamlink-backend/internal/modules/auth/domain/token"
+	"time"
+)
+
+type PurgeExpiredTokensUseCase struct {
+	tokenRepo token.TokenRepository
+}
+
+type PurgeExpiredTokensInput struct {
+	Now       time.Time
+	BatchSize int
+}
+
+type PurgeExpiredTokensOutput struct {
+	Deleted int64
+	Batches int
+}
+
+func NewPurgeExpiredTokensUseCase(tokenRepo token.TokenRepository) *PurgeExpiredTokensUseCase {
+	return &PurgeExpiredTokensUseCase{tokenRepo: tokenRepo}
+}
+
+// Execute deletes expired tokens batch by batch so a large backlog never holds a long lock on the table.
+func (uc *PurgeExpiredTokensUseCase) Execute(ctx context.Context, input PurgeExpiredTokensInput) (*PurgeExpiredTokensOutput, error) {
+	output := &PurgeExpiredTokensOutput{}
+
+	for {
+		deleted, err := uc.tokenRepo.DeleteExpired(ctx, input.Now, input.BatchSize)
+		if err != nil {
+			return output, err
+		}
+
+		output.Deleted += deleted
+		output.Batches++
+
+		if deleted < int64(input.BatchSize) {
+			return output, nil
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package useCase
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"jamlink-backend/internal/modules/auth/mocks"
+	"testing"
+	"time"
+)
+
+func TestPurgeExpiredTokens_DeletesInBatches(t *testing.T) {
+	tokenRepo := new(mocks.MockTokenRepository)
+	now := time.Now()
+
+	tokenRepo.On("DeleteExpired", now, 2).Return(int64(2), nil).Twice()
+	tokenRepo.On("DeleteExpired", now, 2).Return(int64(1), nil).Once()
+
+	usecase := NewPurgeExpiredTokensUseCase(tokenRepo)
+	output, err := usecase.Execute(t.Context(), PurgeExpiredTokensInput{Now: now, BatchSize: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), output.Deleted)
+	assert.Equal(t, 3, output.Batches)
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestPurgeExpiredTokens_NothingToDelete(t *testing.T) {
+	tokenRepo := new(mocks.MockTokenRepository)
+	now := time.Now()
+
+	tokenRepo.On("DeleteExpired", now, 100).Return(int64(0), nil).Once()
+
+	usecase := NewPurgeExpiredTokensUseCase(tokenRepo)
+	output, err := usecase.Execute(t.Context(), PurgeExpiredTokensInput{Now: now, BatchSize: 100})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), output.Deleted)
+	assert.Equal(t, 1, output.Batches)
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestPurgeExpiredTokens_RepositoryError(t *testing.T) {
+	tokenRepo := new(mocks.MockTokenRepository)
+	now := time.Now()
+
+	tokenRepo.On("DeleteExpired", now, 10).Return(int64(10), nil).Once()
+	tokenRepo.On("DeleteExpired", now, 10).Return(int64(0), errors.New("db error")).Once()
+
+	usecase := NewPurgeExpiredTokensUseCase(tokenRepo)
+	output, err := usecase.Execute(t.Context(), PurgeExpiredTokensInput{Now: now, BatchSize: 10})
+
+	assert.EqualError(t, err, "db error")
+	assert.Equal(t, int64(10), output.Deleted)
+	tokenRepo.AssertExpectations(t)
+}
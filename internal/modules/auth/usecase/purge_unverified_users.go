@@ -0,0 +1,104 @@
+package useCase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/shared/email"
+	"jamlink-backend/internal/shared/security"
+	"time"
+)
+
+var (
+	ErrInvalidRetentionPolicy = errors.New("unverified retention must be longer than the warning lead time")
+)
+
+type PurgeUnverifiedUsersUseCase struct {
+	userRepo     user.UserRepository
+	security     security.SecurityService
+	emailService email.EmailService
+	verifyURL    string
+}
+
+type PurgeUnverifiedUsersInput struct {
+	Now         time.Time
+	Retention   time.Duration
+	WarningLead time.Duration
+	BatchSize   int
+}
+
+type PurgeUnverifiedUsersOutput struct {
+	Warned  int
+	Deleted int
+	Failed  int
+}
+
+func NewPurgeUnverifiedUsersUseCase(userRepo user.UserRepository, security security.SecurityService, emailService email.EmailService, verifyURL string) *PurgeUnverifiedUsersUseCase {
+	return &PurgeUnverifiedUsersUseCase{userRepo: userRepo, security: security, emailService: emailService, verifyURL: verifyURL}
+}
+
+// Execute warns unverified accounts that are WarningLead away from the end of their retention period,
+// then deletes the accounts that were warned at least WarningLead ago and are still unverified.
+// Failures on a single account are collected and do not stop the batch.
+func (uc *PurgeUnverifiedUsersUseCase) Execute(ctx context.Context, input PurgeUnverifiedUsersInput) (*PurgeUnverifiedUsersOutput, error) {
+	if input.WarningLead <= 0 || input.Retention <= input.WarningLead {
+		return nil, ErrInvalidRetentionPolicy
+	}
+
+	output := &PurgeUnverifiedUsersOutput{}
+	var errs []error
+
+	toWarn, err := uc.userRepo.FindUnverifiedToWarn(ctx, input.Now.Add(-(input.Retention - input.WarningLead)), input.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, u := range toWarn {
+		if err := uc.warn(ctx, u, input); err != nil {
+			output.Failed++
+			errs = append(errs, fmt.Errorf("warn user %s: %w", u.ID, err))
+			continue
+		}
+		output.Warned++
+	}
+
+	toPurge, err := uc.userRepo.FindUnverifiedToPurge(ctx, input.Now.Add(-input.Retention), input.Now.Add(-input.WarningLead), input.BatchSize)
+	if err != nil {
+		return output, errors.Join(append(errs, err)...)
+	}
+
+	for _, u := range toPurge {
+		if err := uc.purge(ctx, u); err != nil {
+			output.Failed++
+			errs = append(errs, fmt.Errorf("delete user %s: %w", u.ID, err))
+			continue
+		}
+		output.Deleted++
+	}
+
+	return output, errors.Join(errs...)
+}
+
+func (uc *PurgeUnverifiedUsersUseCase) warn(ctx context.Context, u *user.User, input PurgeUnverifiedUsersInput) error {
+	verificationToken, err := uc.security.GenerateJWT(ctx, nil, &u.Email, input.WarningLead, "verify_email", false, nil)
+	if err != nil {
+		return err
+	}
+
+	err = uc.emailService.Send(ctx, u.Email, email.TemplateAccountDeletionWarning, u.PreferredLang, map[string]string{
+		"URL":  fmt.Sprintf("%s?token=%s", uc.verifyURL, verificationToken),
+		"DATE": email.FormatDate(input.Now.Add(input.WarningLead), u.PreferredLang),
+	})
+	if err != nil {
+		return err
+	}
+
+	return uc.userRepo.MarkDeletionWarned(ctx, u.ID, input.Now)
+}
+
+// purge relies on the foreign keys of the user's tokens, codes, devices and consents, which delete them
+// with the account in one statement.
+func (uc *PurgeUnverifiedUsersUseCase) purge(ctx context.Context, u *user.User) error {
+	return uc.userRepo.Delete(ctx, u.ID)
+}
@@ -0,0 +1,92 @@
+package useCase
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/auth/mocks"
+	"jamlink-backend/internal/shared/email"
+	"jamlink-backend/internal/shared/security"
+	"testing"
+	"time"
+)
+
+func newPurgeInput(now time.Time) PurgeUnverifiedUsersInput {
+	return PurgeUnverifiedUsersInput{
+		Now:         now,
+		Retention:   30 * 24 * time.Hour,
+		WarningLead: 7 * 24 * time.Hour,
+		BatchSize:   50,
+	}
+}
+
+func TestPurgeUnverifiedUsers_WarnsAndDeletes(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	mockEmail := new(mocks.MockEmailService)
+
+	now := time.Now()
+	input := newPurgeInput(now)
+	toWarn := &userDomain.User{ID: uuid.New(), Email: "warn@example.com", PreferredLang: "fr-FR"}
+	toPurge := &userDomain.User{ID: uuid.New(), Email: "purge@example.com"}
+
+	userRepo.On("FindUnverifiedToWarn", now.Add(-23*24*time.Hour), 50).Return([]*userDomain.User{toWarn}, nil)
+	mockSecurity.On("GenerateJWT", (*uuid.UUID)(nil), &toWarn.Email, input.WarningLead, "verify_email", false, (*security.AuthContext)(nil)).Return("verify.jwt", nil)
+	mockEmail.On("Send", toWarn.Email, email.TemplateAccountDeletionWarning, "fr-FR", mock.MatchedBy(func(data map[string]string) bool {
+		return data["URL"] == testVerifyURL+"?token=verify.jwt" && data["DATE"] != ""
+	})).Return(nil)
+	userRepo.On("MarkDeletionWarned", toWarn.ID, now).Return(nil)
+
+	userRepo.On("FindUnverifiedToPurge", now.Add(-input.Retention), now.Add(-input.WarningLead), 50).Return([]*userDomain.User{toPurge}, nil)
+	userRepo.On("Delete", toPurge.ID).Return(nil)
+
+	usecase := NewPurgeUnverifiedUsersUseCase(userRepo, mockSecurity, mockEmail, testVerifyURL)
+	output, err := usecase.Execute(t.Context(), input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, output.Warned)
+	assert.Equal(t, 1, output.Deleted)
+	assert.Equal(t, 0, output.Failed)
+	userRepo.AssertExpectations(t)
+	mockSecurity.AssertExpectations(t)
+	mockEmail.AssertExpectations(t)
+}
+
+func TestPurgeUnverifiedUsers_EmailFailureDoesNotMarkWarned(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	mockEmail := new(mocks.MockEmailService)
+
+	now := time.Now()
+	input := newPurgeInput(now)
+	toWarn := &userDomain.User{ID: uuid.New(), Email: "warn@example.com"}
+
+	userRepo.On("FindUnverifiedToWarn", mock.Anything, 50).Return([]*userDomain.User{toWarn}, nil)
+	mockSecurity.On("GenerateJWT", mock.Anything, &toWarn.Email, input.WarningLead, "verify_email", false, (*security.AuthContext)(nil)).Return("verify.jwt", nil)
+	mockEmail.On("Send", toWarn.Email, email.TemplateAccountDeletionWarning, mock.Anything, mock.Anything).Return(errors.New("brevo down"))
+	userRepo.On("FindUnverifiedToPurge", mock.Anything, mock.Anything, 50).Return([]*userDomain.User{}, nil)
+
+	usecase := NewPurgeUnverifiedUsersUseCase(userRepo, mockSecurity, mockEmail, testVerifyURL)
+	output, err := usecase.Execute(t.Context(), input)
+
+	assert.ErrorContains(t, err, "brevo down")
+	assert.Equal(t, 0, output.Warned)
+	assert.Equal(t, 1, output.Failed)
+	userRepo.AssertNotCalled(t, "MarkDeletionWarned", mock.Anything, mock.Anything)
+}
+
+func TestPurgeUnverifiedUsers_InvalidPolicy(t *testing.T) {
+	usecase := NewPurgeUnverifiedUsersUseCase(new(mocks.MockUserRepository), new(mocks.MockSecurityService), new(mocks.MockEmailService), testVerifyURL)
+
+	output, err := usecase.Execute(t.Context(), PurgeUnverifiedUsersInput{
+		Now:         time.Now(),
+		Retention:   24 * time.Hour,
+		WarningLead: 48 * time.Hour,
+		BatchSize:   10,
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidRetentionPolicy)
+	assert.Nil(t, output)
+}
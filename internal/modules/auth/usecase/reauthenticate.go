@@ -0,0 +1,70 @@
+package useCase
+
+import (
+	"context"
+	"github.com/google/uuid"
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/shared/apperror"
+	"jamlink-backend/internal/shared/security"
+	"time"
+)
+
+const StepUpTokenTTL = time.Minute * 5
+
+var (
+	ErrReauthenticationFailed = apperror.Unauthorized("reauthentication_failed", "re-authentication failed")
+	ErrAuthMethodNotAvailable = apperror.BadRequest("auth_method_not_available", "this authentication method is not enabled for the account")
+)
+
+type ReauthenticateUseCase struct {
+	userRepo userDomain.UserRepository
+	security security.SecurityService
+}
+
+type ReauthenticateInput struct {
+	UserID    uuid.UUID `json:"-"`
+	Method    string    `json:"method" binding:"required,oneof=password totp passkey" example:"password"`
+	Password  string    `json:"password,omitempty" example:"Abcd1234!"`
+	Code      string    `json:"code,omitempty" example:"123456"`
+	Assertion string    `json:"assertion,omitempty"`
+}
+
+type ReauthenticateOutput struct {
+	StepUpToken string `json:"step_up_token"`
+	ExpiresIn   int    `json:"expires_in" example:"300"`
+}
+
+func NewReauthenticateUseCase(userRepo userDomain.UserRepository, security security.SecurityService) *ReauthenticateUseCase {
+	return &ReauthenticateUseCase{userRepo: userRepo, security: security}
+}
+
+func (uc *ReauthenticateUseCase) Execute(ctx context.Context, input ReauthenticateInput) (*ReauthenticateOutput, error) {
+	user, err := uc.userRepo.FindByID(ctx, input.UserID)
+	if err != nil {
+		return nil, ErrReauthenticationFailed
+	}
+
+	var method string
+
+	switch input.Method {
+	case "password":
+		if user.Provider != "local" {
+			return nil, ErrAuthMethodNotAvailable
+		}
+		if input.Password == "" || !uc.security.CheckPassword(ctx, input.Password, user.Password) {
+			return nil, ErrReauthenticationFailed
+		}
+		method = security.AuthMethodPassword
+
+	default:
+		// TOTP and passkeys are part of the API contract, but accounts cannot enroll them yet.
+		return nil, ErrAuthMethodNotAvailable
+	}
+
+	stepUpToken, err := uc.security.GenerateJWT(ctx, &user.ID, nil, StepUpTokenTTL, security.StepUpTokenType, user.IsVerified(), security.NewAuthContext(method))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReauthenticateOutput{StepUpToken: stepUpToken, ExpiresIn: int(StepUpTokenTTL.Seconds())}, nil
+}
@@ -0,0 +1,82 @@
+package useCase
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/auth/mocks"
+	"jamlink-backend/internal/shared/security"
+	"testing"
+)
+
+func TestReauthenticate_PasswordSuccess(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+
+	user := &userDomain.User{ID: uuid.New(), Password: "hashed", Provider: "local", Verification: userDomain.UserVerification{IsVerified: true}}
+
+	userRepo.On("FindByID", user.ID).Return(user, nil)
+	mockSecurity.On("CheckPassword", "Abcd1234!", "hashed").Return(true)
+	mockSecurity.On("GenerateJWT", &user.ID, (*string)(nil), StepUpTokenTTL, security.StepUpTokenType, true,
+		mock.MatchedBy(func(auth *security.AuthContext) bool {
+			return auth.IsRecent(StepUpTokenTTL) && auth.Methods[0] == security.AuthMethodPassword
+		})).Return("step.up.token", nil)
+
+	usecase := NewReauthenticateUseCase(userRepo, mockSecurity)
+	output, err := usecase.Execute(t.Context(), ReauthenticateInput{UserID: user.ID, Method: "password", Password: "Abcd1234!"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "step.up.token", output.StepUpToken)
+	assert.Equal(t, 300, output.ExpiresIn)
+	userRepo.AssertExpectations(t)
+	mockSecurity.AssertExpectations(t)
+}
+
+func TestReauthenticate_WrongPassword(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+
+	user := &userDomain.User{ID: uuid.New(), Password: "hashed", Provider: "local"}
+
+	userRepo.On("FindByID", user.ID).Return(user, nil)
+	mockSecurity.On("CheckPassword", "wrong", "hashed").Return(false)
+
+	usecase := NewReauthenticateUseCase(userRepo, mockSecurity)
+	output, err := usecase.Execute(t.Context(), ReauthenticateInput{UserID: user.ID, Method: "password", Password: "wrong"})
+
+	assert.ErrorIs(t, err, ErrReauthenticationFailed)
+	assert.Nil(t, output)
+	mockSecurity.AssertNotCalled(t, "GenerateJWT")
+}
+
+func TestReauthenticate_PasswordOnGoogleAccount(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+
+	user := &userDomain.User{ID: uuid.New(), Password: "random-hash", Provider: "google"}
+
+	userRepo.On("FindByID", user.ID).Return(user, nil)
+
+	usecase := NewReauthenticateUseCase(userRepo, mockSecurity)
+	output, err := usecase.Execute(t.Context(), ReauthenticateInput{UserID: user.ID, Method: "password", Password: "guess"})
+
+	assert.ErrorIs(t, err, ErrAuthMethodNotAvailable)
+	assert.Nil(t, output)
+	mockSecurity.AssertNotCalled(t, "CheckPassword")
+}
+
+func TestReauthenticate_TOTPNotEnrolled(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+
+	user := &userDomain.User{ID: uuid.New(), Provider: "local"}
+
+	userRepo.On("FindByID", user.ID).Return(user, nil)
+
+	usecase := NewReauthenticateUseCase(userRepo, mockSecurity)
+	output, err := usecase.Execute(t.Context(), ReauthenticateInput{UserID: user.ID, Method: "totp", Code: "123456"})
+
+	assert.ErrorIs(t, err, ErrAuthMethodNotAvailable)
+	assert.Nil(t, output)
+}
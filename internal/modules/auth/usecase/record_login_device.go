@@ -0,0 +1,112 @@
+package useCase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"jamlink-backend/internal/modules/auth/domain/device"
+	"jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/shared/email"
+	"jamlink-backend/internal/shared/fingerprint"
+	"jamlink-backend/internal/shared/security"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	ReportLoginTokenTTL = time.Hour * 24 * 7
+	reportTokenBytes    = 32
+)
+
+type RecordLoginDeviceUseCase struct {
+	userRepo      user.UserRepository
+	deviceRepo    device.KnownDeviceRepository
+	security      security.SecurityService
+	emailService  email.EmailService
+	fingerprinter fingerprint.Fingerprinter
+	reportURL     string
+}
+
+type RecordLoginDeviceInput struct {
+	UserID uuid.UUID
+	Login  fingerprint.LoginContext
+}
+
+func NewRecordLoginDeviceUseCase(userRepo user.UserRepository, deviceRepo device.KnownDeviceRepository, security security.SecurityService, emailService email.EmailService, fingerprinter fingerprint.Fingerprinter, reportURL string) *RecordLoginDeviceUseCase {
+	return &RecordLoginDeviceUseCase{
+		userRepo:      userRepo,
+		deviceRepo:    deviceRepo,
+		security:      security,
+		emailService:  emailService,
+		fingerprinter: fingerprinter,
+		reportURL:     reportURL,
+	}
+}
+
+// Execute remembers the device a login came from and emails the user when it is a new one.
+// The very first device of an account is recorded silently.
+func (uc *RecordLoginDeviceUseCase) Execute(ctx context.Context, input RecordLoginDeviceInput) error {
+	fp := uc.fingerprinter.Fingerprint(input.Login)
+	now := time.Now()
+
+	knownDevice, err := uc.deviceRepo.FindByFingerprint(ctx, input.UserID, fp.Hash)
+	if err == nil {
+		return uc.deviceRepo.Touch(ctx, knownDevice.ID, now)
+	}
+	if !errors.Is(err, device.ErrDeviceNotFound) {
+		return err
+	}
+
+	knownDevices, err := uc.deviceRepo.CountByUserID(ctx, input.UserID)
+	if err != nil {
+		return err
+	}
+
+	newDevice := device.CreateKnownDevice(input.UserID, fp.Hash, fp.Device, fp.IPPrefix, fp.Country, fp.City)
+
+	if knownDevices == 0 {
+		return uc.deviceRepo.Create(ctx, newDevice)
+	}
+
+	foundUser, err := uc.userRepo.FindByID(ctx, input.UserID)
+	if err != nil {
+		return err
+	}
+
+	// The link only lets its holder report the sign-in, so it is a single-use random value rather than a
+	// signed token that could be replayed as a credential.
+	reportToken, err := uc.security.GenerateSecureRandomString(reportTokenBytes)
+	if err != nil {
+		return err
+	}
+	newDevice.IssueReportToken(uc.security.HashOTP(reportToken), now.Add(ReportLoginTokenTTL))
+
+	if err := uc.deviceRepo.Create(ctx, newDevice); err != nil {
+		return err
+	}
+
+	return uc.emailService.Send(ctx, foundUser.Email, email.TemplateNewSignIn, foundUser.PreferredLang, map[string]string{
+		"DEVICE":   fp.Device,
+		"LOCATION": describeLocation(fp),
+		"DATE":     email.FormatDateTime(now, foundUser.PreferredLang),
+		"URL":      fmt.Sprintf("%s?token=%s", uc.reportURL, url.QueryEscape(reportToken)),
+	})
+}
+
+func describeLocation(fp fingerprint.Fingerprint) string {
+	parts := make([]string, 0, 3)
+
+	for _, part := range []string{fp.City, fp.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	if fp.IPPrefix != "" {
+		parts = append(parts, "("+fp.IPPrefix+")")
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,101 @@
+package useCase
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/auth/domain/device"
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/auth/mocks"
+	"jamlink-backend/internal/shared/email"
+	"jamlink-backend/internal/shared/fingerprint"
+	"testing"
+	"time"
+)
+
+type recordLoginDeviceMocks struct {
+	userRepo      *mocks.MockUserRepository
+	deviceRepo    *mocks.MockKnownDeviceRepository
+	security      *mocks.MockSecurityService
+	email         *mocks.MockEmailService
+	fingerprinter *mocks.MockFingerprinter
+}
+
+func newRecordLoginDeviceUseCase() (*RecordLoginDeviceUseCase, *recordLoginDeviceMocks) {
+	m := &recordLoginDeviceMocks{
+		userRepo:      new(mocks.MockUserRepository),
+		deviceRepo:    new(mocks.MockKnownDeviceRepository),
+		security:      new(mocks.MockSecurityService),
+		email:         new(mocks.MockEmailService),
+		fingerprinter: new(mocks.MockFingerprinter),
+	}
+
+	return NewRecordLoginDeviceUseCase(m.userRepo, m.deviceRepo, m.security, m.email, m.fingerprinter, "https://example.com/report-login"), m
+}
+
+var testLogin = fingerprint.LoginContext{UserAgent: "Mozilla/5.0 (Windows NT 10.0) Firefox/126.0", IP: "81.250.12.34"}
+
+var testFingerprint = fingerprint.Fingerprint{Hash: "fp-hash", Device: "Firefox / Windows", IPPrefix: "81.250.12.0/24", Country: "FR", City: "Paris"}
+
+func TestRecordLoginDevice_KnownDevice(t *testing.T) {
+	usecase, m := newRecordLoginDeviceUseCase()
+	userID := uuid.New()
+	knownDevice := device.CreateKnownDevice(userID, "fp-hash", "Firefox / Windows", "81.250.12.0/24", "FR", "Paris")
+
+	m.fingerprinter.On("Fingerprint", testLogin).Return(testFingerprint)
+	m.deviceRepo.On("FindByFingerprint", userID, "fp-hash").Return(knownDevice, nil)
+	m.deviceRepo.On("Touch", knownDevice.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	err := usecase.Execute(t.Context(), RecordLoginDeviceInput{UserID: userID, Login: testLogin})
+
+	assert.NoError(t, err)
+	m.deviceRepo.AssertExpectations(t)
+	m.email.AssertNotCalled(t, "Send")
+}
+
+func TestRecordLoginDevice_FirstDeviceIsSilent(t *testing.T) {
+	usecase, m := newRecordLoginDeviceUseCase()
+	userID := uuid.New()
+
+	m.fingerprinter.On("Fingerprint", testLogin).Return(testFingerprint)
+	m.deviceRepo.On("FindByFingerprint", userID, "fp-hash").Return(nil, device.ErrDeviceNotFound)
+	m.deviceRepo.On("CountByUserID", userID).Return(int64(0), nil)
+	m.deviceRepo.On("Create", mock.MatchedBy(func(d *device.KnownDevice) bool {
+		return d.UserID == userID && d.Fingerprint == "fp-hash" && d.Country == "FR" && d.ReportTokenHash == ""
+	})).Return(nil)
+
+	err := usecase.Execute(t.Context(), RecordLoginDeviceInput{UserID: userID, Login: testLogin})
+
+	assert.NoError(t, err)
+	m.deviceRepo.AssertExpectations(t)
+	m.email.AssertNotCalled(t, "Send")
+	m.security.AssertNotCalled(t, "GenerateSecureRandomString", mock.Anything)
+}
+
+func TestRecordLoginDevice_NewDeviceSendsAlert(t *testing.T) {
+	usecase, m := newRecordLoginDeviceUseCase()
+
+	user := &userDomain.User{ID: uuid.New(), Email: "user@example.com", PreferredLang: "fr-FR", Verification: userDomain.UserVerification{IsVerified: true}}
+
+	m.fingerprinter.On("Fingerprint", testLogin).Return(testFingerprint)
+	m.deviceRepo.On("FindByFingerprint", user.ID, "fp-hash").Return(nil, device.ErrDeviceNotFound)
+	m.deviceRepo.On("CountByUserID", user.ID).Return(int64(2), nil)
+	m.userRepo.On("FindByID", user.ID).Return(user, nil)
+	m.security.On("GenerateSecureRandomString", 32).Return("report+token=", nil)
+	m.security.On("HashOTP", "report+token=").Return("report-hash")
+	m.deviceRepo.On("Create", mock.MatchedBy(func(d *device.KnownDevice) bool {
+		return d.ReportTokenHash == "report-hash" && d.ReportExpiresAt != nil && d.ReportExpiresAt.After(time.Now().Add(ReportLoginTokenTTL-time.Minute))
+	})).Return(nil)
+	m.email.On("Send", user.Email, email.TemplateNewSignIn, "fr-FR", mock.MatchedBy(func(data map[string]string) bool {
+		return data["DEVICE"] == "Firefox / Windows" &&
+			data["LOCATION"] == "Paris FR (81.250.12.0/24)" &&
+			data["URL"] == "https://example.com/report-login?token=report%2Btoken%3D"
+	})).Return(nil)
+
+	err := usecase.Execute(t.Context(), RecordLoginDeviceInput{UserID: user.ID, Login: testLogin})
+
+	assert.NoError(t, err)
+	m.deviceRepo.AssertExpectations(t)
+	m.security.AssertExpectations(t)
+	m.email.AssertExpectations(t)
+}
@@ -1,8 +1,11 @@
 package useCase
 
 import (
+	"context"
+	"errors"
 	tokenDomain "jamlink-backend/internal/modules/auth/domain/token"
-	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/auth/domain/transaction"
+	"jamlink-backend/internal/shared/metrics"
 	"jamlink-backend/internal/shared/security"
 	"time"
 )
@@ -19,57 +22,90 @@ type RefreshTokenOutput struct {
 
 type RefreshTokenUseCase struct {
 	security  security.SecurityService
-	userRepo  userDomain.UserRepository
-	tokenRepo tokenDomain.TokenRepository
+	uow       transaction.UnitOfWork
+	lifetimes TokenLifetimes
 }
 
-func NewRefreshTokenUseCase(security security.SecurityService, userRepo userDomain.UserRepository, tokenRepo tokenDomain.TokenRepository) *RefreshTokenUseCase {
+func NewRefreshTokenUseCase(security security.SecurityService, uow transaction.UnitOfWork, lifetimes TokenLifetimes) *RefreshTokenUseCase {
 	return &RefreshTokenUseCase{
-		security, userRepo, tokenRepo,
+		security, uow, lifetimes,
 	}
 }
 
-func (uc *RefreshTokenUseCase) Execute(input RefreshTokenInput) (*RefreshTokenOutput, error) {
-	existingToken, err := uc.tokenRepo.FindByToken(input.RefreshToken)
-	if err != nil || existingToken == nil || existingToken.ExpiresAt.Before(time.Now()) {
-		return nil, tokenDomain.ErrTokenExpired
-	}
+// Execute rotates the refresh token in a single unit of work: the stored token is deleted before the new
+// pair is issued, so of two concurrent rotations of the same token only the first gets a new pair.
+func (uc *RefreshTokenUseCase) Execute(ctx context.Context, input RefreshTokenInput) (output *RefreshTokenOutput, err error) {
+	defer func() { metrics.RecordRefreshTokenRotation(err) }()
 
-	userId, err := uc.security.GetJWTInfo(input.RefreshToken)
+	// Every token of the user is signed or stored alike, so only its type tells a refresh token from the
+	// other tokens that are kept in tokens, such as a password reset link.
+	claims, err := uc.security.ValidateJWT(ctx, input.RefreshToken)
 	if err != nil {
-		return nil, err
+		return nil, tokenDomain.ErrTokenExpired
 	}
-
-	user, err := uc.userRepo.FindByID(userId)
-
-	if err != nil {
-		return nil, err
+	if tokenType, ok := claims["type"].(string); !ok || tokenType != security.RefreshTokenType {
+		return nil, tokenDomain.ErrTokenType
 	}
 
-	token, err := uc.security.GenerateJWT(&userId, nil, time.Minute*15, "login", user.Verification.IsVerified)
+	userId, err := uc.security.GetJWTInfo(ctx, input.RefreshToken)
 	if err != nil {
 		return nil, err
 	}
 
-	const expiringTime = time.Hour * 24 * 7
-	refreshToken, err := uc.security.GenerateJWT(&userId, nil, expiringTime, "refresh_token", user.Verification.IsVerified)
+	auth, err := uc.security.GetJWTAuthContext(ctx, input.RefreshToken)
 	if err != nil {
 		return nil, err
 	}
 
-	inDBToken, err := tokenDomain.CreateToken(userId, refreshToken, time.Now().Add(expiringTime))
-	if err != nil {
-		return nil, err
-	}
-	err = uc.tokenRepo.Create(inDBToken)
-	if err != nil {
-		return nil, tokenDomain.ErrTokenCreationFailed
-	}
+	// The old token is only given up for a stored new one, so a failure never signs the user out.
+	err = uc.uow.Do(ctx, func(repos transaction.Repositories) error {
+		existingToken, err := repos.Tokens.FindByToken(ctx, uc.security.HashOTP(input.RefreshToken))
+		if err != nil || existingToken.UserID != userId || existingToken.ExpiresAt.Before(time.Now()) {
+			return tokenDomain.ErrTokenExpired
+		}
+		if err := repos.Tokens.DeleteByID(ctx, existingToken.ID); err != nil {
+			if errors.Is(err, tokenDomain.ErrTokenNotFound) {
+				// Rotated by a concurrent request in the meantime.
+				return tokenDomain.ErrTokenExpired
+			}
+			return tokenDomain.ErrTokenDeletionFailed
+		}
+
+		user, err := repos.Users.FindByID(ctx, userId)
+		if err != nil {
+			return err
+		}
+
+		// Verification is read from the user, not the old token, so a guardian's consent applies on refresh.
+		isVerified, claimOpts := verificationClaims(user)
 
-	err = uc.tokenRepo.DeleteByID(existingToken.ID)
+		// Opaque sessions are stored in the transaction too, so none outlives a rollback.
+		txCtx := security.WithSessionStore(ctx, repos.Tokens)
+
+		token, err := uc.security.GenerateJWT(txCtx, &userId, nil, uc.lifetimes.Access, security.AccessTokenType, isVerified, auth, claimOpts...)
+		if err != nil {
+			return err
+		}
+
+		refreshToken, err := uc.security.GenerateJWT(txCtx, &userId, nil, uc.lifetimes.Refresh, security.RefreshTokenType, isVerified, auth, claimOpts...)
+		if err != nil {
+			return err
+		}
+
+		inDBToken, err := tokenDomain.CreateToken(userId, uc.security.HashOTP(refreshToken), time.Now().Add(uc.lifetimes.Refresh))
+		if err != nil {
+			return err
+		}
+		if err := repos.Tokens.Create(ctx, inDBToken); err != nil {
+			return tokenDomain.ErrTokenCreationFailed
+		}
+
+		output = &RefreshTokenOutput{Token: token, RefreshToken: refreshToken}
+		return nil
+	})
 	if err != nil {
-		return nil, tokenDomain.ErrTokenDeletionFailed
+		return nil, err
 	}
 
-	return &RefreshTokenOutput{Token: token, RefreshToken: refreshToken}, nil
+	return output, nil
 }
@@ -1,6 +1,7 @@
 package useCase
 
 import (
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -38,20 +39,27 @@ func TestRefreshToken_Success(t *testing.T) {
 		ExpiresAt: timeNow.Add(expiringTimeForRefreshToken - time.Hour),
 	}
 
-	tokenRepo.On("FindByToken", refreshToken).Return(existingToken, nil)
+	// The original authentication must be carried over, not renewed
+	auth := &security.AuthContext{Time: timeNow.Add(-time.Hour), Methods: []string{security.AuthMethodPassword}}
+
+	mockSecurity.On("ValidateJWT", refreshToken).Return(jwt.MapClaims{"type": security.RefreshTokenType}, nil)
+	mockSecurity.On("HashOTP", refreshToken).Return("hashed." + refreshToken)
+	tokenRepo.On("FindByToken", "hashed."+refreshToken).Return(existingToken, nil)
 	mockSecurity.On("GetJWTInfo", refreshToken).Return(fakeUser.ID, nil)
+	mockSecurity.On("GetJWTAuthContext", refreshToken).Return(auth, nil)
 	userRepo.On("FindByID", fakeUser.ID).Return(fakeUser, nil)
-	mockSecurity.On("GenerateJWT", &fakeUser.ID, (*string)(nil), time.Minute*15, "login", fakeUser.Verification.IsVerified).Return(newAccessToken, nil)
-	mockSecurity.On("GenerateJWT", &fakeUser.ID, (*string)(nil), expiringTimeForRefreshToken, "refresh_token", fakeUser.Verification.IsVerified).Return(newRefreshToken, nil)
+	mockSecurity.On("GenerateJWT", &fakeUser.ID, (*string)(nil), time.Minute*15, "login", fakeUser.Verification.IsVerified, auth).Return(newAccessToken, nil)
+	mockSecurity.On("GenerateJWT", &fakeUser.ID, (*string)(nil), expiringTimeForRefreshToken, "refresh_token", fakeUser.Verification.IsVerified, auth).Return(newRefreshToken, nil)
+	mockSecurity.On("HashOTP", newRefreshToken).Return("hashed." + newRefreshToken)
 	tokenRepo.On("Create", mock.MatchedBy(func(token *tokenDomain.Token) bool {
-		return token.Token == newRefreshToken && token.UserID == fakeUser.ID
+		return token.Token == "hashed."+newRefreshToken && token.UserID == fakeUser.ID
 	})).Return(nil)
 	tokenRepo.On("DeleteByID", existingToken.ID).Return(nil)
 
-	usecase := NewRefreshTokenUseCase(mockSecurity, userRepo, tokenRepo)
+	usecase := NewRefreshTokenUseCase(mockSecurity, mocks.NewMockUnitOfWork(userRepo, tokenRepo), testLifetimes)
 
 	// Act
-	output, err := usecase.Execute(RefreshTokenInput{RefreshToken: refreshToken})
+	output, err := usecase.Execute(t.Context(), RefreshTokenInput{RefreshToken: refreshToken})
 
 	// Assert
 	assert.NoError(t, err)
@@ -72,19 +80,40 @@ func TestRefreshToken_InvalidToken(t *testing.T) {
 
 	refreshToken := "invalid_token"
 
-	tokenRepo.On("FindByToken", refreshToken).Return(nil, tokenDomain.ErrTokenExpired)
+	mockSecurity.On("ValidateJWT", refreshToken).Return(jwt.MapClaims(nil), security.ErrInvalidToken)
 
-	usecase := NewRefreshTokenUseCase(mockSecurity, userRepo, tokenRepo)
+	usecase := NewRefreshTokenUseCase(mockSecurity, mocks.NewMockUnitOfWork(userRepo, tokenRepo), testLifetimes)
 
 	// Act
-	output, err := usecase.Execute(RefreshTokenInput{RefreshToken: refreshToken})
+	output, err := usecase.Execute(t.Context(), RefreshTokenInput{RefreshToken: refreshToken})
 
 	// Assert
 	assert.ErrorIs(t, err, tokenDomain.ErrTokenExpired)
 	assert.Nil(t, output)
 
-	tokenRepo.AssertExpectations(t)
-	// Les autres mocks ne devraient pas être appelés
+	mockSecurity.AssertExpectations(t)
+	tokenRepo.AssertNotCalled(t, "FindByToken", mock.Anything)
+}
+
+func TestRefreshToken_RejectsOtherTokenTypes(t *testing.T) {
+	for _, tokenType := range []string{security.AccessTokenType, "reset_password", "report_login"} {
+		t.Run(tokenType, func(t *testing.T) {
+			mockSecurity := new(mocks.MockSecurityService)
+			userRepo := new(mocks.MockUserRepository)
+			tokenRepo := new(mocks.MockTokenRepository)
+
+			mockSecurity.On("ValidateJWT", "other.token").Return(jwt.MapClaims{"type": tokenType}, nil)
+
+			usecase := NewRefreshTokenUseCase(mockSecurity, mocks.NewMockUnitOfWork(userRepo, tokenRepo), testLifetimes)
+
+			output, err := usecase.Execute(t.Context(), RefreshTokenInput{RefreshToken: "other.token"})
+
+			assert.ErrorIs(t, err, tokenDomain.ErrTokenType)
+			assert.Nil(t, output)
+			tokenRepo.AssertNotCalled(t, "FindByToken", mock.Anything)
+			mockSecurity.AssertNotCalled(t, "GenerateJWT", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
 }
 
 func TestRefreshToken_ExpiredToken(t *testing.T) {
@@ -105,18 +134,23 @@ func TestRefreshToken_ExpiredToken(t *testing.T) {
 		ExpiresAt: time.Now().Add(-1 * time.Hour), // Expiré il y a une heure
 	}
 
-	tokenRepo.On("FindByToken", refreshToken).Return(expiredToken, nil)
+	mockSecurity.On("ValidateJWT", refreshToken).Return(jwt.MapClaims{"type": security.RefreshTokenType}, nil)
+	mockSecurity.On("GetJWTInfo", refreshToken).Return(userID, nil)
+	mockSecurity.On("GetJWTAuthContext", refreshToken).Return(security.NewAuthContext(security.AuthMethodPassword), nil)
+	mockSecurity.On("HashOTP", refreshToken).Return("hashed." + refreshToken)
+	tokenRepo.On("FindByToken", "hashed."+refreshToken).Return(expiredToken, nil)
 
-	usecase := NewRefreshTokenUseCase(mockSecurity, userRepo, tokenRepo)
+	usecase := NewRefreshTokenUseCase(mockSecurity, mocks.NewMockUnitOfWork(userRepo, tokenRepo), testLifetimes)
 
 	// Act
-	output, err := usecase.Execute(RefreshTokenInput{RefreshToken: refreshToken})
+	output, err := usecase.Execute(t.Context(), RefreshTokenInput{RefreshToken: refreshToken})
 
 	// Assert
 	assert.ErrorIs(t, err, tokenDomain.ErrTokenExpired)
 	assert.Nil(t, output)
 
 	tokenRepo.AssertExpectations(t)
+	tokenRepo.AssertNotCalled(t, "DeleteByID", mock.Anything)
 }
 
 func TestRefreshToken_GetJWTInfoFails(t *testing.T) {
@@ -126,30 +160,23 @@ func TestRefreshToken_GetJWTInfoFails(t *testing.T) {
 	tokenRepo := new(mocks.MockTokenRepository)
 
 	refreshToken := "jwt_error_token"
-	userID := uuid.New()
-
-	validToken := &tokenDomain.Token{
-		ID:        uuid.New(),
-		UserID:    userID,
-		Token:     refreshToken,
-		CreatedAt: time.Now().Add(-1 * time.Hour),
-		ExpiresAt: time.Now().Add(23 * time.Hour),
-	}
 
-	tokenRepo.On("FindByToken", refreshToken).Return(validToken, nil)
+	mockSecurity.On("ValidateJWT", refreshToken).Return(jwt.MapClaims{"type": security.RefreshTokenType}, nil)
 	mockSecurity.On("GetJWTInfo", refreshToken).Return(uuid.Nil, security.ErrInvalidToken)
 
-	usecase := NewRefreshTokenUseCase(mockSecurity, userRepo, tokenRepo)
+	uow := mocks.NewMockUnitOfWork(userRepo, tokenRepo)
+	usecase := NewRefreshTokenUseCase(mockSecurity, uow, testLifetimes)
 
 	// Act
-	output, err := usecase.Execute(RefreshTokenInput{RefreshToken: refreshToken})
+	output, err := usecase.Execute(t.Context(), RefreshTokenInput{RefreshToken: refreshToken})
 
 	// Assert
 	assert.ErrorIs(t, err, security.ErrInvalidToken)
 	assert.Nil(t, output)
+	assert.Equal(t, 0, uow.Commits+uow.Rollbacks)
 
-	tokenRepo.AssertExpectations(t)
 	mockSecurity.AssertExpectations(t)
+	tokenRepo.AssertNotCalled(t, "FindByToken", mock.Anything)
 }
 
 func TestRefreshToken_UserNotFound(t *testing.T) {
@@ -169,14 +196,18 @@ func TestRefreshToken_UserNotFound(t *testing.T) {
 		ExpiresAt: time.Now().Add(23 * time.Hour),
 	}
 
-	tokenRepo.On("FindByToken", refreshToken).Return(validToken, nil)
+	mockSecurity.On("ValidateJWT", refreshToken).Return(jwt.MapClaims{"type": security.RefreshTokenType}, nil)
+	mockSecurity.On("HashOTP", refreshToken).Return("hashed." + refreshToken)
+	tokenRepo.On("FindByToken", "hashed."+refreshToken).Return(validToken, nil)
 	mockSecurity.On("GetJWTInfo", refreshToken).Return(userID, nil)
+	mockSecurity.On("GetJWTAuthContext", refreshToken).Return(security.NewAuthContext(security.AuthMethodPassword), nil)
+	tokenRepo.On("DeleteByID", validToken.ID).Return(nil)
 	userRepo.On("FindByID", userID).Return(nil, userDomain.ErrUserNotFound)
 
-	usecase := NewRefreshTokenUseCase(mockSecurity, userRepo, tokenRepo)
+	usecase := NewRefreshTokenUseCase(mockSecurity, mocks.NewMockUnitOfWork(userRepo, tokenRepo), testLifetimes)
 
 	// Act
-	output, err := usecase.Execute(RefreshTokenInput{RefreshToken: refreshToken})
+	output, err := usecase.Execute(t.Context(), RefreshTokenInput{RefreshToken: refreshToken})
 
 	// Assert
 	assert.ErrorIs(t, err, userDomain.ErrUserNotFound)
@@ -211,15 +242,19 @@ func TestRefreshToken_GenerateJWTFails(t *testing.T) {
 		ExpiresAt: time.Now().Add(23 * time.Hour),
 	}
 
-	tokenRepo.On("FindByToken", refreshToken).Return(validToken, nil)
+	mockSecurity.On("ValidateJWT", refreshToken).Return(jwt.MapClaims{"type": security.RefreshTokenType}, nil)
+	mockSecurity.On("HashOTP", refreshToken).Return("hashed." + refreshToken)
+	tokenRepo.On("FindByToken", "hashed."+refreshToken).Return(validToken, nil)
 	mockSecurity.On("GetJWTInfo", refreshToken).Return(userID, nil)
+	mockSecurity.On("GetJWTAuthContext", refreshToken).Return(security.NewAuthContext(security.AuthMethodPassword), nil)
+	tokenRepo.On("DeleteByID", validToken.ID).Return(nil)
 	userRepo.On("FindByID", userID).Return(fakeUser, nil)
-	mockSecurity.On("GenerateJWT", &userID, (*string)(nil), time.Minute*15, "login", fakeUser.Verification.IsVerified).Return("", security.ErrJWTGeneration)
+	mockSecurity.On("GenerateJWT", &userID, (*string)(nil), time.Minute*15, "login", fakeUser.Verification.IsVerified, mock.AnythingOfType("*security.AuthContext")).Return("", security.ErrJWTGeneration)
 
-	usecase := NewRefreshTokenUseCase(mockSecurity, userRepo, tokenRepo)
+	usecase := NewRefreshTokenUseCase(mockSecurity, mocks.NewMockUnitOfWork(userRepo, tokenRepo), testLifetimes)
 
 	// Act
-	output, err := usecase.Execute(RefreshTokenInput{RefreshToken: refreshToken})
+	output, err := usecase.Execute(t.Context(), RefreshTokenInput{RefreshToken: refreshToken})
 
 	// Assert
 	assert.ErrorIs(t, err, security.ErrJWTGeneration)
@@ -254,16 +289,20 @@ func TestRefreshToken_GenerateRefreshTokenFails(t *testing.T) {
 		ExpiresAt: time.Now().Add(23 * time.Hour),
 	}
 
-	tokenRepo.On("FindByToken", refreshToken).Return(validToken, nil)
+	mockSecurity.On("ValidateJWT", refreshToken).Return(jwt.MapClaims{"type": security.RefreshTokenType}, nil)
+	mockSecurity.On("HashOTP", refreshToken).Return("hashed." + refreshToken)
+	tokenRepo.On("FindByToken", "hashed."+refreshToken).Return(validToken, nil)
 	mockSecurity.On("GetJWTInfo", refreshToken).Return(userID, nil)
+	mockSecurity.On("GetJWTAuthContext", refreshToken).Return(security.NewAuthContext(security.AuthMethodPassword), nil)
+	tokenRepo.On("DeleteByID", validToken.ID).Return(nil)
 	userRepo.On("FindByID", userID).Return(fakeUser, nil)
-	mockSecurity.On("GenerateJWT", &userID, (*string)(nil), time.Minute*15, "login", fakeUser.Verification.IsVerified).Return("new_access_token", nil)
-	mockSecurity.On("GenerateJWT", &userID, (*string)(nil), time.Hour*24*7, "refresh_token", fakeUser.Verification.IsVerified).Return("", security.ErrJWTGeneration)
+	mockSecurity.On("GenerateJWT", &userID, (*string)(nil), time.Minute*15, "login", fakeUser.Verification.IsVerified, mock.AnythingOfType("*security.AuthContext")).Return("new_access_token", nil)
+	mockSecurity.On("GenerateJWT", &userID, (*string)(nil), time.Hour*24*7, "refresh_token", fakeUser.Verification.IsVerified, mock.AnythingOfType("*security.AuthContext")).Return("", security.ErrJWTGeneration)
 
-	usecase := NewRefreshTokenUseCase(mockSecurity, userRepo, tokenRepo)
+	usecase := NewRefreshTokenUseCase(mockSecurity, mocks.NewMockUnitOfWork(userRepo, tokenRepo), testLifetimes)
 
 	// Act
-	output, err := usecase.Execute(RefreshTokenInput{RefreshToken: refreshToken})
+	output, err := usecase.Execute(t.Context(), RefreshTokenInput{RefreshToken: refreshToken})
 
 	// Assert
 	assert.ErrorIs(t, err, security.ErrJWTGeneration)
@@ -299,19 +338,24 @@ func TestRefreshToken_CreateTokenFails(t *testing.T) {
 		ExpiresAt: time.Now().Add(23 * time.Hour),
 	}
 
-	tokenRepo.On("FindByToken", refreshToken).Return(validToken, nil)
+	mockSecurity.On("ValidateJWT", refreshToken).Return(jwt.MapClaims{"type": security.RefreshTokenType}, nil)
+	mockSecurity.On("HashOTP", refreshToken).Return("hashed." + refreshToken)
+	tokenRepo.On("FindByToken", "hashed."+refreshToken).Return(validToken, nil)
 	mockSecurity.On("GetJWTInfo", refreshToken).Return(userID, nil)
+	mockSecurity.On("GetJWTAuthContext", refreshToken).Return(security.NewAuthContext(security.AuthMethodPassword), nil)
+	tokenRepo.On("DeleteByID", validToken.ID).Return(nil)
 	userRepo.On("FindByID", userID).Return(fakeUser, nil)
-	mockSecurity.On("GenerateJWT", &userID, (*string)(nil), time.Minute*15, "login", fakeUser.Verification.IsVerified).Return("new_access_token", nil)
-	mockSecurity.On("GenerateJWT", &userID, (*string)(nil), time.Hour*24*7, "refresh_token", fakeUser.Verification.IsVerified).Return(newRefreshToken, nil)
+	mockSecurity.On("GenerateJWT", &userID, (*string)(nil), time.Minute*15, "login", fakeUser.Verification.IsVerified, mock.AnythingOfType("*security.AuthContext")).Return("new_access_token", nil)
+	mockSecurity.On("GenerateJWT", &userID, (*string)(nil), time.Hour*24*7, "refresh_token", fakeUser.Verification.IsVerified, mock.AnythingOfType("*security.AuthContext")).Return(newRefreshToken, nil)
+	mockSecurity.On("HashOTP", newRefreshToken).Return("hashed." + newRefreshToken)
 	tokenRepo.On("Create", mock.MatchedBy(func(token *tokenDomain.Token) bool {
-		return token.Token == newRefreshToken && token.UserID == userID
+		return token.Token == "hashed."+newRefreshToken && token.UserID == userID
 	})).Return(tokenDomain.ErrTokenCreationFailed)
 
-	usecase := NewRefreshTokenUseCase(mockSecurity, userRepo, tokenRepo)
+	usecase := NewRefreshTokenUseCase(mockSecurity, mocks.NewMockUnitOfWork(userRepo, tokenRepo), testLifetimes)
 
 	// Act
-	output, err := usecase.Execute(RefreshTokenInput{RefreshToken: refreshToken})
+	output, err := usecase.Execute(t.Context(), RefreshTokenInput{RefreshToken: refreshToken})
 
 	// Assert
 	assert.ErrorIs(t, err, tokenDomain.ErrTokenCreationFailed)
@@ -330,14 +374,6 @@ func TestRefreshToken_DeleteTokenFails(t *testing.T) {
 
 	refreshToken := "delete_token_error"
 	userID := uuid.New()
-	newRefreshToken := "new.refresh.token"
-
-	fakeUser, err := userDomain.CreateUser("test@example.com", "hashedpassword", "fr-FR", "local")
-	if err != nil {
-		t.Fatal(err)
-	}
-	fakeUser.ID = userID
-	fakeUser.Verification.IsVerified = true
 
 	validToken := &tokenDomain.Token{
 		ID:        uuid.New(),
@@ -347,26 +383,65 @@ func TestRefreshToken_DeleteTokenFails(t *testing.T) {
 		ExpiresAt: time.Now().Add(23 * time.Hour),
 	}
 
-	tokenRepo.On("FindByToken", refreshToken).Return(validToken, nil)
+	mockSecurity.On("ValidateJWT", refreshToken).Return(jwt.MapClaims{"type": security.RefreshTokenType}, nil)
 	mockSecurity.On("GetJWTInfo", refreshToken).Return(userID, nil)
-	userRepo.On("FindByID", userID).Return(fakeUser, nil)
-	mockSecurity.On("GenerateJWT", &userID, (*string)(nil), time.Minute*15, "login", fakeUser.Verification.IsVerified).Return("new_access_token", nil)
-	mockSecurity.On("GenerateJWT", &userID, (*string)(nil), time.Hour*24*7, "refresh_token", fakeUser.Verification.IsVerified).Return(newRefreshToken, nil)
-	tokenRepo.On("Create", mock.MatchedBy(func(token *tokenDomain.Token) bool {
-		return token.Token == newRefreshToken && token.UserID == userID
-	})).Return(nil)
+	mockSecurity.On("GetJWTAuthContext", refreshToken).Return(security.NewAuthContext(security.AuthMethodPassword), nil)
+	mockSecurity.On("HashOTP", refreshToken).Return("hashed." + refreshToken)
+	tokenRepo.On("FindByToken", "hashed."+refreshToken).Return(validToken, nil)
 	tokenRepo.On("DeleteByID", validToken.ID).Return(tokenDomain.ErrTokenDeletionFailed)
 
-	usecase := NewRefreshTokenUseCase(mockSecurity, userRepo, tokenRepo)
+	uow := mocks.NewMockUnitOfWork(userRepo, tokenRepo)
+	usecase := NewRefreshTokenUseCase(mockSecurity, uow, testLifetimes)
 
 	// Act
-	output, err := usecase.Execute(RefreshTokenInput{RefreshToken: refreshToken})
+	output, err := usecase.Execute(t.Context(), RefreshTokenInput{RefreshToken: refreshToken})
 
 	// Assert
 	assert.ErrorIs(t, err, tokenDomain.ErrTokenDeletionFailed)
 	assert.Nil(t, output)
+	assert.Equal(t, 1, uow.Rollbacks)
 
 	tokenRepo.AssertExpectations(t)
 	mockSecurity.AssertExpectations(t)
-	userRepo.AssertExpectations(t)
+	tokenRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestRefreshToken_ConcurrentRotation(t *testing.T) {
+	// Arrange
+	mockSecurity := new(mocks.MockSecurityService)
+	userRepo := new(mocks.MockUserRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+
+	refreshToken := "rotated_token"
+	userID := uuid.New()
+
+	validToken := &tokenDomain.Token{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Token:     refreshToken,
+		CreatedAt: time.Now().Add(-1 * time.Hour),
+		ExpiresAt: time.Now().Add(23 * time.Hour),
+	}
+
+	mockSecurity.On("ValidateJWT", refreshToken).Return(jwt.MapClaims{"type": security.RefreshTokenType}, nil)
+	mockSecurity.On("GetJWTInfo", refreshToken).Return(userID, nil)
+	mockSecurity.On("GetJWTAuthContext", refreshToken).Return(security.NewAuthContext(security.AuthMethodPassword), nil)
+	mockSecurity.On("HashOTP", refreshToken).Return("hashed." + refreshToken)
+	tokenRepo.On("FindByToken", "hashed."+refreshToken).Return(validToken, nil)
+	// Another request deleted the token between the lookup and the deletion.
+	tokenRepo.On("DeleteByID", validToken.ID).Return(tokenDomain.ErrTokenNotFound)
+
+	uow := mocks.NewMockUnitOfWork(userRepo, tokenRepo)
+	usecase := NewRefreshTokenUseCase(mockSecurity, uow, testLifetimes)
+
+	// Act
+	output, err := usecase.Execute(t.Context(), RefreshTokenInput{RefreshToken: refreshToken})
+
+	// Assert
+	assert.ErrorIs(t, err, tokenDomain.ErrTokenExpired)
+	assert.Nil(t, output)
+	assert.Equal(t, 1, uow.Rollbacks)
+
+	mockSecurity.AssertNotCalled(t, "GenerateJWT", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	tokenRepo.AssertNotCalled(t, "Create", mock.Anything)
 }
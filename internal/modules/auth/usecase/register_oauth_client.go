@@ -0,0 +1,73 @@
+package useCase
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"jamlink-backend/internal/modules/auth/domain/oauthclient"
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/shared/security"
+)
+
+type RegisterOAuthClientUseCase struct {
+	userRepo   userDomain.UserRepository
+	clientRepo oauthclient.ClientRepository
+	security   security.SecurityService
+}
+
+type RegisterOAuthClientInput struct {
+	CreatedBy uuid.UUID `json:"-"`
+	Name      string    `json:"name" binding:"required,max=100" example:"Recommendation worker"`
+	Scopes    []string  `json:"scopes" binding:"required,min=1" example:"events:write"`
+	// RedirectURIs is required for "Log in with JamLink", whose scopes must include "openid".
+	RedirectURIs []string `json:"redirect_uris" example:"https://booking.example.com/callback"`
+}
+
+// RegisterOAuthClientOutput is the only response containing the client secret.
+type RegisterOAuthClientOutput struct {
+	ClientID     uuid.UUID `json:"client_id"`
+	ClientSecret string    `json:"client_secret"`
+	Name         string    `json:"name" example:"Recommendation worker"`
+	Scopes       []string  `json:"scopes" example:"events:write"`
+	RedirectURIs []string  `json:"redirect_uris" example:"https://booking.example.com/callback"`
+}
+
+func NewRegisterOAuthClientUseCase(userRepo userDomain.UserRepository, clientRepo oauthclient.ClientRepository, security security.SecurityService) *RegisterOAuthClientUseCase {
+	return &RegisterOAuthClientUseCase{userRepo: userRepo, clientRepo: clientRepo, security: security}
+}
+
+func (uc *RegisterOAuthClientUseCase) Execute(ctx context.Context, input RegisterOAuthClientInput) (*RegisterOAuthClientOutput, error) {
+	creator, err := uc.userRepo.FindByID(ctx, input.CreatedBy)
+	if err != nil {
+		return nil, err
+	}
+	if !creator.IsAdmin() {
+		return nil, oauthclient.ErrRegisterForbidden
+	}
+
+	secret, err := uc.security.GenerateSecureRandomString(32)
+	if err != nil {
+		return nil, err
+	}
+
+	secretHash, err := uc.security.HashPassword(ctx, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := oauthclient.CreateClient(input.Name, secretHash, input.Scopes, input.RedirectURIs, creator.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.clientRepo.Create(ctx, client); err != nil {
+		return nil, err
+	}
+
+	return &RegisterOAuthClientOutput{
+		ClientID:     client.ID,
+		ClientSecret: secret,
+		Name:         client.Name,
+		Scopes:       input.Scopes,
+		RedirectURIs: input.RedirectURIs,
+	}, nil
+}
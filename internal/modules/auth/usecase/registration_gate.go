@@ -0,0 +1,18 @@
+package useCase
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"jamlink-backend/internal/modules/invitation/domain/invitation"
+)
+
+// RegistrationGate decides whether a new account may be created, and with which invitation code. It is
+// implemented by the invitations module.
+type RegistrationGate interface {
+	// Check validates the code without consuming it.
+	Check(ctx context.Context, inviteCode string) error
+	// Redeem consumes the code on behalf of the new user with invitations, the repository of the unit of
+	// work creating the user, so that the code is only consumed if the user is created.
+	Redeem(ctx context.Context, invitations invitation.InvitationRepository, inviteCode string, userID uuid.UUID) error
+}
@@ -0,0 +1,59 @@
+package useCase
+
+import (
+	"context"
+	"jamlink-backend/internal/modules/auth/domain/transaction"
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/shared/security"
+	"time"
+)
+
+type ReportSuspiciousLoginUseCase struct {
+	uow                  transaction.UnitOfWork
+	security             security.SecurityService
+	requestResetPassword *RequestResetPasswordUseCase
+}
+
+type ReportSuspiciousLoginInput struct {
+	Token string `json:"token" binding:"required"`
+}
+
+func NewReportSuspiciousLoginUseCase(uow transaction.UnitOfWork, security security.SecurityService, requestResetPassword *RequestResetPasswordUseCase) *ReportSuspiciousLoginUseCase {
+	return &ReportSuspiciousLoginUseCase{
+		uow:                  uow,
+		security:             security,
+		requestResetPassword: requestResetPassword,
+	}
+}
+
+// Execute handles the single-use "this wasn't me" link of a new sign-in email: every refresh token of
+// the user is revoked, known devices are forgotten so the next logins are reported again, and a password
+// reset email is sent. Access tokens already issued stay valid until they expire.
+func (uc *ReportSuspiciousLoginUseCase) Execute(ctx context.Context, input ReportSuspiciousLoginInput) error {
+	var user *userDomain.User
+
+	// The link is only used up with the revocation, so a failure leaves it valid for another try.
+	err := uc.uow.Do(ctx, func(repos transaction.Repositories) error {
+		userID, err := repos.KnownDevices.ConsumeReportToken(ctx, uc.security.HashOTP(input.Token), time.Now())
+		if err != nil {
+			return err
+		}
+
+		user, err = repos.Users.FindByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if err := repos.Tokens.DeleteUserTokens(ctx, user.ID); err != nil {
+			return err
+		}
+
+		return repos.KnownDevices.DeleteByUserID(ctx, user.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Sent once the sessions are revoked; should it fail, the user can still ask for a reset themselves.
+	return uc.requestResetPassword.Execute(ctx, RequestResetPasswordInput{Email: user.Email, PreferredLang: user.PreferredLang})
+}
@@ -0,0 +1,96 @@
+package useCase
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/auth/domain/device"
+	tokenDomain "jamlink-backend/internal/modules/auth/domain/token"
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/auth/mocks"
+	"jamlink-backend/internal/shared/email"
+	"jamlink-backend/internal/shared/security"
+	"testing"
+	"time"
+)
+
+func TestReportSuspiciousLogin_RevokesSessionsAndStartsReset(t *testing.T) {
+	tokenRepo := new(mocks.MockTokenRepository)
+	userRepo := new(mocks.MockUserRepository)
+	deviceRepo := new(mocks.MockKnownDeviceRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	mockEmail := new(mocks.MockEmailService)
+
+	user := &userDomain.User{ID: uuid.New(), Email: "user@example.com", PreferredLang: "fr-FR"}
+
+	mockSecurity.On("HashOTP", "report-token").Return("report-hash")
+	deviceRepo.On("ConsumeReportToken", "report-hash", mock.AnythingOfType("time.Time")).Return(user.ID, nil)
+	userRepo.On("FindByID", user.ID).Return(user, nil)
+	tokenRepo.On("DeleteUserTokens", user.ID).Return(nil)
+	deviceRepo.On("DeleteByUserID", user.ID).Return(nil)
+
+	// Password reset request
+	userRepo.On("FindByEmail", user.Email).Return(user, nil)
+	mockSecurity.On("GenerateJWT", &user.ID, &user.Email, time.Minute*15, "reset_password", false, (*security.AuthContext)(nil)).Return("reset.jwt", nil)
+	mockSecurity.On("HashOTP", "reset.jwt").Return("reset-hash")
+	tokenRepo.On("Create", mock.AnythingOfType("*token.Token")).Return(nil)
+	mockEmail.On("Send", user.Email, email.TemplateResetPassword, "fr-FR", mock.Anything).Return(nil)
+
+	requestReset := NewRequestResetPasswordUseCase(tokenRepo, userRepo, mockSecurity, mockEmail, testVerifyURL, testLifetimes)
+	uow := mocks.NewMockUnitOfWork(userRepo, tokenRepo)
+	uow.Repositories.KnownDevices = deviceRepo
+	usecase := NewReportSuspiciousLoginUseCase(uow, mockSecurity, requestReset)
+
+	err := usecase.Execute(t.Context(), ReportSuspiciousLoginInput{Token: "report-token"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, uow.Commits)
+	tokenRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+	deviceRepo.AssertExpectations(t)
+	mockEmail.AssertExpectations(t)
+}
+
+func TestReportSuspiciousLogin_InvalidOrUsedToken(t *testing.T) {
+	tokenRepo := new(mocks.MockTokenRepository)
+	deviceRepo := new(mocks.MockKnownDeviceRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+
+	mockSecurity.On("HashOTP", "login.jwt").Return("login-hash")
+	deviceRepo.On("ConsumeReportToken", "login-hash", mock.AnythingOfType("time.Time")).Return(uuid.Nil, device.ErrInvalidReportToken)
+
+	uow := mocks.NewMockUnitOfWork(new(mocks.MockUserRepository), tokenRepo)
+	uow.Repositories.KnownDevices = deviceRepo
+	usecase := NewReportSuspiciousLoginUseCase(uow, mockSecurity, nil)
+
+	err := usecase.Execute(t.Context(), ReportSuspiciousLoginInput{Token: "login.jwt"})
+
+	assert.ErrorIs(t, err, device.ErrInvalidReportToken)
+	tokenRepo.AssertNotCalled(t, "DeleteUserTokens", mock.Anything)
+	deviceRepo.AssertNotCalled(t, "DeleteByUserID", mock.Anything)
+}
+
+func TestReportSuspiciousLogin_RevocationFailsKeepsTheLink(t *testing.T) {
+	tokenRepo := new(mocks.MockTokenRepository)
+	userRepo := new(mocks.MockUserRepository)
+	deviceRepo := new(mocks.MockKnownDeviceRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+
+	user := &userDomain.User{ID: uuid.New(), Email: "user@example.com", PreferredLang: "fr-FR"}
+
+	mockSecurity.On("HashOTP", "report-token").Return("report-hash")
+	deviceRepo.On("ConsumeReportToken", "report-hash", mock.AnythingOfType("time.Time")).Return(user.ID, nil)
+	userRepo.On("FindByID", user.ID).Return(user, nil)
+	tokenRepo.On("DeleteUserTokens", user.ID).Return(tokenDomain.ErrTokenDeletionFailed)
+
+	uow := mocks.NewMockUnitOfWork(userRepo, tokenRepo)
+	uow.Repositories.KnownDevices = deviceRepo
+	usecase := NewReportSuspiciousLoginUseCase(uow, mockSecurity, nil)
+
+	err := usecase.Execute(t.Context(), ReportSuspiciousLoginInput{Token: "report-token"})
+
+	assert.ErrorIs(t, err, tokenDomain.ErrTokenDeletionFailed)
+	// Rolling back gives the report token back, so the link can be followed again.
+	assert.Equal(t, 1, uow.Rollbacks)
+	deviceRepo.AssertNotCalled(t, "DeleteByUserID", mock.Anything)
+}
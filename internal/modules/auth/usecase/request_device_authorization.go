@@ -0,0 +1,60 @@
+package useCase
+
+import (
+	"context"
+	"fmt"
+	"jamlink-backend/internal/modules/auth/domain/deviceauth"
+	"jamlink-backend/internal/shared/security"
+	"net/url"
+)
+
+type RequestDeviceAuthorizationUseCase struct {
+	deviceAuthRepo  deviceauth.DeviceAuthorizationRepository
+	security        security.SecurityService
+	verificationURI string
+}
+
+type RequestDeviceAuthorizationInput struct {
+	ClientID string `form:"client_id" json:"client_id" binding:"required,max=100" example:"jamlink-cli"`
+}
+
+// RequestDeviceAuthorizationOutput is the device authorization response of RFC 8628 section 3.2.
+type RequestDeviceAuthorizationOutput struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code" example:"WDJB-MJHT"`
+	VerificationURI         string `json:"verification_uri" example:"https://jamlink.app/device"`
+	VerificationURIComplete string `json:"verification_uri_complete" example:"https://jamlink.app/device?user_code=WDJB-MJHT"`
+	ExpiresIn               int    `json:"expires_in" example:"600"`
+	Interval                int    `json:"interval" example:"5"`
+}
+
+func NewRequestDeviceAuthorizationUseCase(deviceAuthRepo deviceauth.DeviceAuthorizationRepository, security security.SecurityService, verificationURI string) *RequestDeviceAuthorizationUseCase {
+	return &RequestDeviceAuthorizationUseCase{deviceAuthRepo: deviceAuthRepo, security: security, verificationURI: verificationURI}
+}
+
+func (uc *RequestDeviceAuthorizationUseCase) Execute(ctx context.Context, input RequestDeviceAuthorizationInput) (*RequestDeviceAuthorizationOutput, error) {
+	deviceCode, err := uc.security.GenerateSecureRandomString(32)
+	if err != nil {
+		return nil, err
+	}
+
+	userCode, err := deviceauth.GenerateUserCode()
+	if err != nil {
+		return nil, err
+	}
+
+	authorization := deviceauth.CreateDeviceAuthorization(uc.security.HashOTP(deviceCode), userCode, input.ClientID)
+	if err := uc.deviceAuthRepo.Create(ctx, authorization); err != nil {
+		return nil, err
+	}
+
+	verificationURI := uc.verificationURI
+	return &RequestDeviceAuthorizationOutput{
+		DeviceCode:              deviceCode,
+		UserCode:                userCode,
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: fmt.Sprintf("%s?user_code=%s", verificationURI, url.QueryEscape(userCode)),
+		ExpiresIn:               int(deviceauth.AuthorizationTTL.Seconds()),
+		Interval:                authorization.Interval,
+	}, nil
+}
@@ -0,0 +1,81 @@
+package useCase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/shared/email"
+	"jamlink-backend/internal/shared/security"
+	"time"
+)
+
+const (
+	GuardianConsentTokenType = "guardian_consent"
+	GuardianConsentTokenTTL  = 7 * 24 * time.Hour
+
+	// GuardianConsentCooldown is the least time between two consent emails for an account, so the
+	// endpoint cannot be used to flood a guardian's inbox.
+	GuardianConsentCooldown = 15 * time.Minute
+)
+
+type RequestGuardianConsentUseCase struct {
+	repo       user.UserRepository
+	security   security.SecurityService
+	email      email.EmailService
+	consentURL string
+}
+
+// RequestGuardianConsentInput takes the minor's own email, the guardian address being already known.
+type RequestGuardianConsentInput struct {
+	Email string `json:"email" binding:"required,email" example:"teen@example.com"`
+}
+
+func NewRequestGuardianConsentUseCase(repo user.UserRepository, security security.SecurityService, email email.EmailService, consentURL string) *RequestGuardianConsentUseCase {
+	return &RequestGuardianConsentUseCase{repo: repo, security: security, email: email, consentURL: consentURL}
+}
+
+// Execute sends the consent request again. Unknown addresses, accounts that need no consent and
+// requests made within GuardianConsentCooldown of the previous one are ignored silently, as the endpoint
+// must not reveal which accounts exist.
+func (uc *RequestGuardianConsentUseCase) Execute(ctx context.Context, input RequestGuardianConsentInput) error {
+	foundUser, err := uc.repo.FindByEmail(ctx, input.Email)
+
+	if errors.Is(err, user.ErrUserNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = uc.Notify(ctx, foundUser)
+	if errors.Is(err, user.ErrGuardianConsentRequestedRecently) {
+		return nil
+	}
+
+	return err
+}
+
+// Notify emails the guardian of a minor a link to confirm the account, at most once per
+// GuardianConsentCooldown.
+func (uc *RequestGuardianConsentUseCase) Notify(ctx context.Context, minor *user.User) error {
+	if !minor.AwaitingGuardianConsent() {
+		return nil
+	}
+
+	now := time.Now()
+	if err := uc.repo.MarkGuardianConsentRequested(ctx, minor.ID, now, now.Add(-GuardianConsentCooldown)); err != nil {
+		return err
+	}
+
+	token, err := uc.security.GenerateJWT(ctx, &minor.ID, &minor.Minor.GuardianEmail, GuardianConsentTokenTTL, GuardianConsentTokenType, false, nil)
+	if err != nil {
+		return err
+	}
+
+	return uc.email.Send(ctx, minor.Minor.GuardianEmail, email.TemplateGuardianConsent, minor.PreferredLang, map[string]string{
+		"URL":         fmt.Sprintf("%s?token=%s", uc.consentURL, token),
+		"CHILD_EMAIL": minor.Email,
+	})
+}
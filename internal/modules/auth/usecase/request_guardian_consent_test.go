@@ -0,0 +1,51 @@
+package useCase
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/auth/mocks"
+	"jamlink-backend/internal/shared/email"
+	"jamlink-backend/internal/shared/security"
+	"testing"
+	"time"
+)
+
+func TestRequestGuardianConsent_SendsTheRequestAgain(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	mockEmail := new(mocks.MockEmailService)
+
+	minor := &user.User{ID: uuid.New(), Email: "teen@example.com", PreferredLang: "fr-FR", Minor: user.MinorStatus{IsMinor: true, GuardianEmail: "parent@example.com"}}
+
+	mockRepo.On("FindByEmail", minor.Email).Return(minor, nil)
+	mockRepo.On("MarkGuardianConsentRequested", minor.ID, mock.AnythingOfType("time.Time"), mock.MatchedBy(func(notBefore time.Time) bool {
+		return time.Since(notBefore) >= GuardianConsentCooldown
+	})).Return(nil)
+	mockSecurity.On("GenerateJWT", &minor.ID, &minor.Minor.GuardianEmail, GuardianConsentTokenTTL, GuardianConsentTokenType, false, (*security.AuthContext)(nil)).Return("guardian.jwt", nil)
+	mockEmail.On("Send", "parent@example.com", email.TemplateGuardianConsent, "fr-FR", mock.Anything).Return(nil)
+
+	err := NewRequestGuardianConsentUseCase(mockRepo, mockSecurity, mockEmail, "https://example.com/guardian-consent").Execute(t.Context(), RequestGuardianConsentInput{Email: minor.Email})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockEmail.AssertExpectations(t)
+}
+
+func TestRequestGuardianConsent_IgnoresRequestsWithinTheCooldown(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	mockEmail := new(mocks.MockEmailService)
+
+	minor := &user.User{ID: uuid.New(), Email: "teen@example.com", Minor: user.MinorStatus{IsMinor: true, GuardianEmail: "parent@example.com"}}
+
+	mockRepo.On("FindByEmail", minor.Email).Return(minor, nil)
+	mockRepo.On("MarkGuardianConsentRequested", minor.ID, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(user.ErrGuardianConsentRequestedRecently)
+
+	err := NewRequestGuardianConsentUseCase(mockRepo, mockSecurity, mockEmail, "https://example.com/guardian-consent").Execute(t.Context(), RequestGuardianConsentInput{Email: minor.Email})
+
+	assert.NoError(t, err)
+	mockSecurity.AssertNotCalled(t, "GenerateJWT", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockEmail.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
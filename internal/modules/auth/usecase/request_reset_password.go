@@ -1,12 +1,14 @@
 package useCase
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"jamlink-backend/internal/modules/auth/domain/token"
 	"jamlink-backend/internal/modules/auth/domain/user"
 	"jamlink-backend/internal/shared/email"
+	"jamlink-backend/internal/shared/metrics"
 	"jamlink-backend/internal/shared/security"
-	"os"
 	"time"
 )
 
@@ -15,40 +17,55 @@ type RequestResetPasswordUseCase struct {
 	userRepo     user.UserRepository
 	security     security.SecurityService
 	emailService email.EmailService
+	resetURL     string
+	lifetimes    TokenLifetimes
 }
 
 type RequestResetPasswordInput struct {
-	Email string `json:"email" binding:"required,email" example:"user@example.com"`
+	Email         string `json:"email" binding:"required,email" example:"user@example.com"`
+	PreferredLang string `json:"-"`
 }
 
-func NewRequestResetPasswordUseCase(tokenRepo token.TokenRepository, userRepo user.UserRepository, security security.SecurityService, emailService email.EmailService) *RequestResetPasswordUseCase {
-	return &RequestResetPasswordUseCase{tokenRepo: tokenRepo, userRepo: userRepo, security: security, emailService: emailService}
+func NewRequestResetPasswordUseCase(tokenRepo token.TokenRepository, userRepo user.UserRepository, security security.SecurityService, emailService email.EmailService, resetURL string, lifetimes TokenLifetimes) *RequestResetPasswordUseCase {
+	return &RequestResetPasswordUseCase{tokenRepo: tokenRepo, userRepo: userRepo, security: security, emailService: emailService, resetURL: resetURL, lifetimes: lifetimes}
 }
 
-func (uc *RequestResetPasswordUseCase) Execute(input RequestResetPasswordInput) error {
-	foundUser, err := uc.userRepo.FindByEmail(input.Email)
+func (uc *RequestResetPasswordUseCase) Execute(ctx context.Context, input RequestResetPasswordInput) error {
+	foundUser, err := uc.userRepo.FindByEmail(ctx, input.Email)
+	if errors.Is(err, user.ErrUserNotFound) {
+		return uc.emailService.Send(ctx, input.Email, email.TemplateUnknownAccount, input.PreferredLang, map[string]string{})
+	}
+
 	if err != nil {
 		return err
 	}
+	// Requests for unknown accounts are left out of the funnel: they cannot be completed.
+	metrics.RecordFunnelStep(metrics.FunnelPasswordReset, metrics.StepRequested)
 
-	jwt, err := uc.security.GenerateJWT(&foundUser.ID, &foundUser.Email, time.Minute*15, "reset_password", foundUser.Verification.IsVerified)
+	jwt, err := uc.security.GenerateJWT(ctx, &foundUser.ID, &foundUser.Email, uc.lifetimes.ResetPassword, "reset_password", foundUser.Verification.IsVerified, nil)
 	if err != nil {
 		return err
 	}
 
-	createdToken, err := token.CreateToken(foundUser.ID, jwt, time.Now().Add(time.Minute*15))
+	createdToken, err := token.CreateToken(foundUser.ID, uc.security.HashOTP(jwt), time.Now().Add(uc.lifetimes.ResetPassword))
 
 	if err != nil {
 		return err
 	}
 
-	err = uc.tokenRepo.Create(createdToken)
+	err = uc.tokenRepo.Create(ctx, createdToken)
 
 	if err != nil {
 		return err
 	}
 
-	return uc.emailService.Send(foundUser.Email, email.TemplateResetPassword, foundUser.PreferredLang, map[string]string{
-		"URL": fmt.Sprintf("%s?token=%s", os.Getenv("FRONTEND_VERIFY_URL"), createdToken),
+	err = uc.emailService.Send(ctx, foundUser.Email, email.TemplateResetPassword, foundUser.PreferredLang, map[string]string{
+		"URL": fmt.Sprintf("%s?token=%s", uc.resetURL, jwt),
 	})
+	if err != nil {
+		return err
+	}
+	metrics.RecordFunnelStep(metrics.FunnelPasswordReset, metrics.StepEmailSent)
+
+	return nil
 }
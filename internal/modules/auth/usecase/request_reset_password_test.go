@@ -5,9 +5,11 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	tokenDomain "jamlink-backend/internal/modules/auth/domain/token"
 	userDomain "jamlink-backend/internal/modules/auth/domain/user"
 	"jamlink-backend/internal/modules/auth/mocks"
 	"jamlink-backend/internal/shared/email"
+	"jamlink-backend/internal/shared/security"
 	"testing"
 	"time"
 )
@@ -39,18 +41,22 @@ func TestRequestResetPassword_Success(t *testing.T) {
 		mock.MatchedBy(func(email *string) bool { return *email == userEmail }),
 		time.Minute*15,
 		"reset_password",
-		user.Verification.IsVerified).Return(jwtToken, nil)
-	mockTokenRepo.On("Create", mock.AnythingOfType("*token.Token")).Return(nil)
+		user.Verification.IsVerified,
+		(*security.AuthContext)(nil)).Return(jwtToken, nil)
+	mockSecurity.On("HashOTP", jwtToken).Return("hashed.token")
+	mockTokenRepo.On("Create", mock.MatchedBy(func(token *tokenDomain.Token) bool {
+		return token.UserID == userID && token.Token == "hashed.token"
+	})).Return(nil)
 	mockEmailService.On("Send",
 		userEmail,
 		email.TemplateResetPassword,
 		"fr",
-		mock.AnythingOfType("map[string]string")).Return(nil)
+		map[string]string{"URL": testVerifyURL + "?token=" + jwtToken}).Return(nil)
 
-	useCase := NewRequestResetPasswordUseCase(mockTokenRepo, mockUserRepo, mockSecurity, mockEmailService)
+	useCase := NewRequestResetPasswordUseCase(mockTokenRepo, mockUserRepo, mockSecurity, mockEmailService, testVerifyURL, testLifetimes)
 
 	// Act
-	err := useCase.Execute(RequestResetPasswordInput{
+	err := useCase.Execute(t.Context(), RequestResetPasswordInput{
 		Email: userEmail,
 	})
 
@@ -71,13 +77,42 @@ func TestRequestResetPassword_UserNotFound(t *testing.T) {
 
 	userEmail := "nonexistent@example.com"
 
+	// Setup expectations
+	mockUserRepo.On("FindByEmail", userEmail).Return(nil, userDomain.ErrUserNotFound)
+	mockEmailService.On("Send", userEmail, email.TemplateUnknownAccount, "fr-FR", map[string]string{}).Return(nil)
+
+	useCase := NewRequestResetPasswordUseCase(mockTokenRepo, mockUserRepo, mockSecurity, mockEmailService, testVerifyURL, testLifetimes)
+
+	// Act
+	err := useCase.Execute(t.Context(), RequestResetPasswordInput{
+		Email:         userEmail,
+		PreferredLang: "fr-FR",
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	mockUserRepo.AssertExpectations(t)
+	mockEmailService.AssertExpectations(t)
+	mockSecurity.AssertNotCalled(t, "GenerateJWT")
+	mockTokenRepo.AssertNotCalled(t, "Create")
+}
+
+func TestRequestResetPassword_LookupFails(t *testing.T) {
+	// Arrange
+	mockTokenRepo := new(mocks.MockTokenRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+	mockEmailService := new(mocks.MockEmailService)
+
+	userEmail := "user@example.com"
+
 	// Setup expectations
 	mockUserRepo.On("FindByEmail", userEmail).Return(nil, errors.New("utilisateur non trouvé"))
 
-	useCase := NewRequestResetPasswordUseCase(mockTokenRepo, mockUserRepo, mockSecurity, mockEmailService)
+	useCase := NewRequestResetPasswordUseCase(mockTokenRepo, mockUserRepo, mockSecurity, mockEmailService, testVerifyURL, testLifetimes)
 
 	// Act
-	err := useCase.Execute(RequestResetPasswordInput{
+	err := useCase.Execute(t.Context(), RequestResetPasswordInput{
 		Email: userEmail,
 	})
 
@@ -114,12 +149,13 @@ func TestRequestResetPassword_JWTGenerationFailed(t *testing.T) {
 		mock.MatchedBy(func(email *string) bool { return *email == userEmail }),
 		time.Minute*15,
 		"reset_password",
-		true).Return("", errors.New("erreur lors de la génération du JWT"))
+		true,
+		(*security.AuthContext)(nil)).Return("", errors.New("erreur lors de la génération du JWT"))
 
-	useCase := NewRequestResetPasswordUseCase(mockTokenRepo, mockUserRepo, mockSecurity, mockEmailService)
+	useCase := NewRequestResetPasswordUseCase(mockTokenRepo, mockUserRepo, mockSecurity, mockEmailService, testVerifyURL, testLifetimes)
 
 	// Act
-	err := useCase.Execute(RequestResetPasswordInput{
+	err := useCase.Execute(t.Context(), RequestResetPasswordInput{
 		Email: userEmail,
 	})
 
@@ -157,13 +193,15 @@ func TestRequestResetPassword_TokenCreationFailed(t *testing.T) {
 		mock.MatchedBy(func(email *string) bool { return *email == userEmail }),
 		time.Minute*15,
 		"reset_password",
-		true).Return(jwtToken, nil)
+		true,
+		(*security.AuthContext)(nil)).Return(jwtToken, nil)
+	mockSecurity.On("HashOTP", jwtToken).Return("hashed.token")
 	mockTokenRepo.On("Create", mock.AnythingOfType("*token.Token")).Return(errors.New("erreur de création du token"))
 
-	useCase := NewRequestResetPasswordUseCase(mockTokenRepo, mockUserRepo, mockSecurity, mockEmailService)
+	useCase := NewRequestResetPasswordUseCase(mockTokenRepo, mockUserRepo, mockSecurity, mockEmailService, testVerifyURL, testLifetimes)
 
 	// Act
-	err := useCase.Execute(RequestResetPasswordInput{
+	err := useCase.Execute(t.Context(), RequestResetPasswordInput{
 		Email: userEmail,
 	})
 
@@ -202,7 +240,9 @@ func TestRequestResetPassword_EmailSendingFailed(t *testing.T) {
 		mock.MatchedBy(func(email *string) bool { return *email == userEmail }),
 		time.Minute*15,
 		"reset_password",
-		true).Return(jwtToken, nil)
+		true,
+		(*security.AuthContext)(nil)).Return(jwtToken, nil)
+	mockSecurity.On("HashOTP", jwtToken).Return("hashed.token")
 	mockTokenRepo.On("Create", mock.AnythingOfType("*token.Token")).Return(nil)
 	mockEmailService.On("Send",
 		userEmail,
@@ -210,10 +250,10 @@ func TestRequestResetPassword_EmailSendingFailed(t *testing.T) {
 		"fr",
 		mock.AnythingOfType("map[string]string")).Return(errors.New("erreur d'envoi d'email"))
 
-	useCase := NewRequestResetPasswordUseCase(mockTokenRepo, mockUserRepo, mockSecurity, mockEmailService)
+	useCase := NewRequestResetPasswordUseCase(mockTokenRepo, mockUserRepo, mockSecurity, mockEmailService, testVerifyURL, testLifetimes)
 
 	// Act
-	err := useCase.Execute(RequestResetPasswordInput{
+	err := useCase.Execute(t.Context(), RequestResetPasswordInput{
 		Email: userEmail,
 	})
 
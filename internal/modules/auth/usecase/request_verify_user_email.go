@@ -1,52 +1,111 @@
 package useCase
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"jamlink-backend/internal/modules/auth/domain/otp"
 	"jamlink-backend/internal/modules/auth/domain/user"
 	"jamlink-backend/internal/shared/email"
+	"jamlink-backend/internal/shared/metrics"
 	"jamlink-backend/internal/shared/security"
-	"os"
 	"time"
 )
 
 type RequestVerifyUserEmailUseCase struct {
-	security security.SecurityService
-	email    email.EmailService
-	repo     user.UserRepository
+	security  security.SecurityService
+	email     email.EmailService
+	repo      user.UserRepository
+	codeRepo  otp.VerificationCodeRepository
+	verifyURL string
 }
 
 type RequestVerifyUserEmailInput struct {
 	Email string `json:"email" binding:"required,email" example:"user@example.com"`
 }
 
-func NewRequestVerifyUserEmailUseCase(security security.SecurityService, repo user.UserRepository, email email.EmailService) *RequestVerifyUserEmailUseCase {
-	return &RequestVerifyUserEmailUseCase{security: security, repo: repo, email: email}
+func NewRequestVerifyUserEmailUseCase(security security.SecurityService, repo user.UserRepository, email email.EmailService, codeRepo otp.VerificationCodeRepository, verifyURL string) *RequestVerifyUserEmailUseCase {
+	return &RequestVerifyUserEmailUseCase{security: security, repo: repo, email: email, codeRepo: codeRepo, verifyURL: verifyURL}
 }
 
-func (uc *RequestVerifyUserEmailUseCase) Execute(input RequestVerifyUserEmailInput) error {
-	foundUser, err := uc.repo.FindByEmail(input.Email)
+func (uc *RequestVerifyUserEmailUseCase) Execute(ctx context.Context, input RequestVerifyUserEmailInput) error {
+	foundUser, err := uc.repo.FindByEmail(ctx, input.Email)
 
-	if err != nil {
-		return user.ErrUserNotFound
+	if errors.Is(err, user.ErrUserNotFound) {
+		// Nobody to notify: the caller must not be able to tell unknown addresses apart.
+		return nil
 	}
 
-	token, err := uc.security.GenerateJWT(nil, &input.Email, time.Hour*24, "verify_email", foundUser.Verification.IsVerified)
 	if err != nil {
 		return err
 	}
 
 	if foundUser.Verification.IsVerified || foundUser.Verification.VerifiedAt != nil {
-		return errors.New("your account is already verified")
+		return uc.email.Send(ctx, foundUser.Email, email.TemplateAlreadyVerified, foundUser.PreferredLang, map[string]string{})
+	}
+	metrics.RecordFunnelStep(metrics.FunnelVerification, metrics.StepRequested)
+
+	if err := uc.throttle(ctx, foundUser, time.Now()); err != nil {
+		return err
+	}
+
+	token, err := uc.security.GenerateJWT(ctx, nil, &input.Email, time.Hour*24, "verify_email", foundUser.Verification.IsVerified, nil)
+	if err != nil {
+		return err
 	}
 
-	err = uc.email.Send(foundUser.Email, email.TemplateVerification, foundUser.PreferredLang, map[string]string{
-		"URL": fmt.Sprintf("%s?token=%s", os.Getenv("FRONTEND_VERIFY_URL"), token),
+	code, err := uc.issueCode(ctx, foundUser)
+	if err != nil {
+		return err
+	}
+
+	err = uc.email.Send(ctx, foundUser.Email, email.TemplateVerification, foundUser.PreferredLang, map[string]string{
+		"URL":  fmt.Sprintf("%s?token=%s", uc.verifyURL, token),
+		"CODE": code,
 	})
 
 	if err != nil {
 		return err
 	}
+	metrics.RecordFunnelStep(metrics.FunnelVerification, metrics.StepEmailSent)
+
+	return nil
+}
+
+// throttle refuses to send another code within otp.ResendCooldown of the last one, or past
+// otp.MaxCodesPerHour codes in the last hour. Otherwise every new code would bring MaxAttempts fresh
+// guesses.
+func (uc *RequestVerifyUserEmailUseCase) throttle(ctx context.Context, foundUser *user.User, now time.Time) error {
+	recent, err := uc.codeRepo.CountCreatedSince(ctx, foundUser.ID, now.Add(-otp.ResendCooldown))
+	if err != nil {
+		return err
+	}
+	if recent > 0 {
+		return otp.ErrTooManyCodes
+	}
+
+	lastHour, err := uc.codeRepo.CountCreatedSince(ctx, foundUser.ID, now.Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if lastHour >= otp.MaxCodesPerHour {
+		return otp.ErrTooManyCodes
+	}
 
 	return nil
 }
+
+// issueCode creates a fresh code and returns it in clear for the email. Only the latest code of a user
+// is accepted; the previous ones are kept for the throttle and deleted once the email is verified.
+func (uc *RequestVerifyUserEmailUseCase) issueCode(ctx context.Context, foundUser *user.User) (string, error) {
+	code, err := uc.security.GenerateNumericCode(otp.CodeLength)
+	if err != nil {
+		return "", err
+	}
+
+	if err := uc.codeRepo.Create(ctx, otp.CreateVerificationCode(foundUser.ID, uc.security.HashOTP(code), time.Now().Add(otp.CodeTTL))); err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
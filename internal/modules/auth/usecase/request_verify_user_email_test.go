@@ -4,26 +4,26 @@ import (
 	"errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/auth/domain/otp"
 	userDomain "jamlink-backend/internal/modules/auth/domain/user"
 	"jamlink-backend/internal/modules/auth/mocks"
 	"jamlink-backend/internal/shared/email"
-	"os"
+	"jamlink-backend/internal/shared/security"
 	"testing"
 	"time"
 )
 
+const testVerifyURL = "https://example.com/verify"
+
 func TestGetVerificationEmail_Success(t *testing.T) {
 	// Arrange
 	mockSecurity := new(mocks.MockSecurityService)
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockEmailService := new(mocks.MockEmailService)
+	mockCodeRepo := new(mocks.MockVerificationCodeRepository)
 
 	userEmail := "user@example.com"
 	jwtToken := "verification.jwt.token"
-	verifyURL := "https://example.com/verify"
-
-	os.Setenv("FRONTEND_VERIFY_URL", verifyURL)
-	defer os.Unsetenv("FRONTEND_VERIFY_URL")
 
 	// Create a user with unverified status
 	user := &userDomain.User{
@@ -41,22 +41,29 @@ func TestGetVerificationEmail_Success(t *testing.T) {
 		mock.MatchedBy(func(email *string) bool { return *email == userEmail }),
 		mock.AnythingOfType("time.Duration"),
 		"verify_email",
-		false).Return(jwtToken, nil)
+		false,
+		(*security.AuthContext)(nil)).Return(jwtToken, nil)
+	mockSecurity.On("GenerateNumericCode", otp.CodeLength).Return("123456", nil)
+	mockSecurity.On("HashOTP", "123456").Return("hashed-code")
+	mockCodeRepo.On("CountCreatedSince", user.ID, mock.Anything).Return(int64(0), nil)
+	mockCodeRepo.On("Create", mock.MatchedBy(func(code *otp.VerificationCode) bool {
+		return code.UserID == user.ID && code.CodeHash == "hashed-code" && code.ExpiresAt.After(time.Now())
+	})).Return(nil)
 
 	mockEmailService.On("Send",
 		userEmail,
 		email.TemplateVerification,
 		"en",
 		mock.MatchedBy(func(data map[string]string) bool {
-			expectedURL := verifyURL + "?token=" + jwtToken
-			return data["URL"] == expectedURL
+			expectedURL := testVerifyURL + "?token=" + jwtToken
+			return data["URL"] == expectedURL && data["CODE"] == "123456"
 		})).Return(nil)
 
 	// Create the use case
-	useCase := NewRequestVerifyUserEmailUseCase(mockSecurity, mockUserRepo, mockEmailService)
+	useCase := NewRequestVerifyUserEmailUseCase(mockSecurity, mockUserRepo, mockEmailService, mockCodeRepo, testVerifyURL)
 
 	// Act
-	err := useCase.Execute(RequestVerifyUserEmailInput{
+	err := useCase.Execute(t.Context(), RequestVerifyUserEmailInput{
 		Email: userEmail,
 	})
 
@@ -65,6 +72,7 @@ func TestGetVerificationEmail_Success(t *testing.T) {
 	mockUserRepo.AssertExpectations(t)
 	mockSecurity.AssertExpectations(t)
 	mockEmailService.AssertExpectations(t)
+	mockCodeRepo.AssertExpectations(t)
 }
 
 func TestGetVerificationEmail_UserNotFound(t *testing.T) {
@@ -72,35 +80,59 @@ func TestGetVerificationEmail_UserNotFound(t *testing.T) {
 	mockSecurity := new(mocks.MockSecurityService)
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockEmailService := new(mocks.MockEmailService)
+	mockCodeRepo := new(mocks.MockVerificationCodeRepository)
 
 	userEmail := "nonexistent@example.com"
 
-	mockUserRepo.On("FindByEmail", userEmail).Return(nil, errors.New("user not found"))
+	mockUserRepo.On("FindByEmail", userEmail).Return(nil, userDomain.ErrUserNotFound)
 
 	// Create the use case
-	useCase := NewRequestVerifyUserEmailUseCase(mockSecurity, mockUserRepo, mockEmailService)
+	useCase := NewRequestVerifyUserEmailUseCase(mockSecurity, mockUserRepo, mockEmailService, mockCodeRepo, testVerifyURL)
 
 	// Act
-	err := useCase.Execute(RequestVerifyUserEmailInput{
+	err := useCase.Execute(t.Context(), RequestVerifyUserEmailInput{
 		Email: userEmail,
 	})
 
 	// Assert
-	assert.Error(t, err)
-	assert.Equal(t, userDomain.ErrUserNotFound, err)
+	assert.NoError(t, err)
 	mockUserRepo.AssertExpectations(t)
 	mockSecurity.AssertNotCalled(t, "GenerateJWT")
 	mockEmailService.AssertNotCalled(t, "Send")
 }
 
+func TestGetVerificationEmail_LookupFails(t *testing.T) {
+	// Arrange
+	mockSecurity := new(mocks.MockSecurityService)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockEmailService := new(mocks.MockEmailService)
+	mockCodeRepo := new(mocks.MockVerificationCodeRepository)
+
+	userEmail := "user@example.com"
+
+	mockUserRepo.On("FindByEmail", userEmail).Return(nil, errors.New("connection refused"))
+
+	// Create the use case
+	useCase := NewRequestVerifyUserEmailUseCase(mockSecurity, mockUserRepo, mockEmailService, mockCodeRepo, testVerifyURL)
+
+	// Act
+	err := useCase.Execute(t.Context(), RequestVerifyUserEmailInput{
+		Email: userEmail,
+	})
+
+	// Assert
+	assert.EqualError(t, err, "connection refused")
+	mockEmailService.AssertNotCalled(t, "Send")
+}
+
 func TestGetVerificationEmail_AlreadyVerified(t *testing.T) {
 	// Arrange
 	mockSecurity := new(mocks.MockSecurityService)
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockEmailService := new(mocks.MockEmailService)
+	mockCodeRepo := new(mocks.MockVerificationCodeRepository)
 
 	userEmail := "verified@example.com"
-	jwtToken := "verification.jwt.token"
 
 	// Create a verified user
 	verifiedTime := time.Now()
@@ -110,30 +142,26 @@ func TestGetVerificationEmail_AlreadyVerified(t *testing.T) {
 			IsVerified: true,
 			VerifiedAt: &verifiedTime,
 		},
+		PreferredLang: "fr-FR",
 	}
 
 	mockUserRepo.On("FindByEmail", userEmail).Return(user, nil)
-	mockSecurity.On("GenerateJWT",
-		mock.Anything,
-		mock.MatchedBy(func(email *string) bool { return *email == userEmail }),
-		mock.AnythingOfType("time.Duration"),
-		"verify_email",
-		true).Return(jwtToken, nil)
+	mockEmailService.On("Send", userEmail, email.TemplateAlreadyVerified, "fr-FR", map[string]string{}).Return(nil)
 
 	// Create the use case
-	useCase := NewRequestVerifyUserEmailUseCase(mockSecurity, mockUserRepo, mockEmailService)
+	useCase := NewRequestVerifyUserEmailUseCase(mockSecurity, mockUserRepo, mockEmailService, mockCodeRepo, testVerifyURL)
 
 	// Act
-	err := useCase.Execute(RequestVerifyUserEmailInput{
+	err := useCase.Execute(t.Context(), RequestVerifyUserEmailInput{
 		Email: userEmail,
 	})
 
 	// Assert
-	assert.Error(t, err)
-	assert.Equal(t, "your account is already verified", err.Error())
+	assert.NoError(t, err)
 	mockUserRepo.AssertExpectations(t)
-	mockSecurity.AssertExpectations(t)
-	mockEmailService.AssertNotCalled(t, "Send")
+	mockEmailService.AssertExpectations(t)
+	mockSecurity.AssertNotCalled(t, "GenerateJWT")
+	mockCodeRepo.AssertNotCalled(t, "Create", mock.Anything)
 }
 
 func TestGetVerificationEmail_JWTGenerationFailure(t *testing.T) {
@@ -141,6 +169,7 @@ func TestGetVerificationEmail_JWTGenerationFailure(t *testing.T) {
 	mockSecurity := new(mocks.MockSecurityService)
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockEmailService := new(mocks.MockEmailService)
+	mockCodeRepo := new(mocks.MockVerificationCodeRepository)
 
 	userEmail := "user@example.com"
 
@@ -154,18 +183,20 @@ func TestGetVerificationEmail_JWTGenerationFailure(t *testing.T) {
 	}
 
 	mockUserRepo.On("FindByEmail", userEmail).Return(user, nil)
+	mockCodeRepo.On("CountCreatedSince", user.ID, mock.Anything).Return(int64(0), nil)
 	mockSecurity.On("GenerateJWT",
 		mock.Anything,
 		mock.MatchedBy(func(email *string) bool { return *email == userEmail }),
 		mock.AnythingOfType("time.Duration"),
 		"verify_email",
-		false).Return("", errors.New("jwt generation error"))
+		false,
+		(*security.AuthContext)(nil)).Return("", errors.New("jwt generation error"))
 
 	// Create the use case
-	useCase := NewRequestVerifyUserEmailUseCase(mockSecurity, mockUserRepo, mockEmailService)
+	useCase := NewRequestVerifyUserEmailUseCase(mockSecurity, mockUserRepo, mockEmailService, mockCodeRepo, testVerifyURL)
 
 	// Act
-	err := useCase.Execute(RequestVerifyUserEmailInput{
+	err := useCase.Execute(t.Context(), RequestVerifyUserEmailInput{
 		Email: userEmail,
 	})
 
@@ -182,13 +213,10 @@ func TestGetVerificationEmail_EmailSendingFailure(t *testing.T) {
 	mockSecurity := new(mocks.MockSecurityService)
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockEmailService := new(mocks.MockEmailService)
+	mockCodeRepo := new(mocks.MockVerificationCodeRepository)
 
 	userEmail := "user@example.com"
 	jwtToken := "verification.jwt.token"
-	verifyURL := "https://example.com/verify"
-
-	os.Setenv("FRONTEND_VERIFY_URL", verifyURL)
-	defer os.Unsetenv("FRONTEND_VERIFY_URL")
 
 	// Create an unverified user
 	user := &userDomain.User{
@@ -206,22 +234,27 @@ func TestGetVerificationEmail_EmailSendingFailure(t *testing.T) {
 		mock.MatchedBy(func(email *string) bool { return *email == userEmail }),
 		mock.AnythingOfType("time.Duration"),
 		"verify_email",
-		false).Return(jwtToken, nil)
+		false,
+		(*security.AuthContext)(nil)).Return(jwtToken, nil)
+	mockSecurity.On("GenerateNumericCode", otp.CodeLength).Return("654321", nil)
+	mockSecurity.On("HashOTP", "654321").Return("hashed-code")
+	mockCodeRepo.On("CountCreatedSince", user.ID, mock.Anything).Return(int64(0), nil)
+	mockCodeRepo.On("Create", mock.Anything).Return(nil)
 
 	mockEmailService.On("Send",
 		userEmail,
 		email.TemplateVerification,
 		"fr",
 		mock.MatchedBy(func(data map[string]string) bool {
-			expectedURL := verifyURL + "?token=" + jwtToken
+			expectedURL := testVerifyURL + "?token=" + jwtToken
 			return data["URL"] == expectedURL
 		})).Return(errors.New("email sending error"))
 
 	// Create the use case
-	useCase := NewRequestVerifyUserEmailUseCase(mockSecurity, mockUserRepo, mockEmailService)
+	useCase := NewRequestVerifyUserEmailUseCase(mockSecurity, mockUserRepo, mockEmailService, mockCodeRepo, testVerifyURL)
 
 	// Act
-	err := useCase.Execute(RequestVerifyUserEmailInput{
+	err := useCase.Execute(t.Context(), RequestVerifyUserEmailInput{
 		Email: userEmail,
 	})
 
@@ -232,3 +265,39 @@ func TestGetVerificationEmail_EmailSendingFailure(t *testing.T) {
 	mockSecurity.AssertExpectations(t)
 	mockEmailService.AssertExpectations(t)
 }
+
+func TestGetVerificationEmail_ThrottlesCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		recent   int64
+		lastHour int64
+	}{
+		{"within the cooldown", 1, 1},
+		{"hourly cap reached", 0, otp.MaxCodesPerHour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSecurity := new(mocks.MockSecurityService)
+			mockUserRepo := new(mocks.MockUserRepository)
+			mockEmailService := new(mocks.MockEmailService)
+			mockCodeRepo := new(mocks.MockVerificationCodeRepository)
+
+			user := &userDomain.User{Email: "user@example.com"}
+			mockUserRepo.On("FindByEmail", user.Email).Return(user, nil)
+			mockCodeRepo.On("CountCreatedSince", user.ID, mock.MatchedBy(func(since time.Time) bool {
+				return time.Since(since) < otp.ResendCooldown+time.Second
+			})).Return(tt.recent, nil)
+			mockCodeRepo.On("CountCreatedSince", user.ID, mock.MatchedBy(func(since time.Time) bool {
+				return time.Since(since) > otp.ResendCooldown+time.Second
+			})).Return(tt.lastHour, nil).Maybe()
+
+			useCase := NewRequestVerifyUserEmailUseCase(mockSecurity, mockUserRepo, mockEmailService, mockCodeRepo, testVerifyURL)
+			err := useCase.Execute(t.Context(), RequestVerifyUserEmailInput{Email: user.Email})
+
+			assert.ErrorIs(t, err, otp.ErrTooManyCodes)
+			mockCodeRepo.AssertNotCalled(t, "Create", mock.Anything)
+			mockEmailService.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
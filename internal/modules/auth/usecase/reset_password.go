@@ -1,17 +1,18 @@
 package useCase
 
 import (
+	"context"
 	tokenDomain "jamlink-backend/internal/modules/auth/domain/token"
-	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/auth/domain/transaction"
 	"jamlink-backend/internal/modules/auth/domain/user/invariants"
+	"jamlink-backend/internal/shared/metrics"
 	"jamlink-backend/internal/shared/security"
 	"time"
 )
 
 type ResetPasswordUseCase struct {
-	tokenRepo tokenDomain.TokenRepository
-	userRepo  userDomain.UserRepository
-	security  security.SecurityService
+	uow      transaction.UnitOfWork
+	security security.SecurityService
 }
 
 type ResetPasswordInput struct {
@@ -20,11 +21,11 @@ type ResetPasswordInput struct {
 	NewPasswordValidation string `json:"new_password_validation" binding:"required"`
 }
 
-func NewResetPasswordUseCase(tokenRepo tokenDomain.TokenRepository, userRepo userDomain.UserRepository, security security.SecurityService) *ResetPasswordUseCase {
-	return &ResetPasswordUseCase{tokenRepo, userRepo, security}
+func NewResetPasswordUseCase(uow transaction.UnitOfWork, security security.SecurityService) *ResetPasswordUseCase {
+	return &ResetPasswordUseCase{uow, security}
 }
 
-func (uc *ResetPasswordUseCase) Execute(input ResetPasswordInput) error {
+func (uc *ResetPasswordUseCase) Execute(ctx context.Context, input ResetPasswordInput) error {
 	if input.NewPasswordValidation != input.NewPassword {
 		return tokenDomain.ErrPasswordDoesntMatch
 	}
@@ -33,7 +34,7 @@ func (uc *ResetPasswordUseCase) Execute(input ResetPasswordInput) error {
 		return err
 	}
 
-	claims, err := uc.security.ValidateJWT(input.Token)
+	claims, err := uc.security.ValidateJWT(ctx, input.Token)
 	if err != nil {
 		return err
 	}
@@ -56,29 +57,37 @@ func (uc *ResetPasswordUseCase) Execute(input ResetPasswordInput) error {
 		return security.ErrInvalidUserEmail
 	}
 
-	token, err := uc.tokenRepo.FindByToken(input.Token)
-	if err != nil {
-		return tokenDomain.ErrTokenNotFound
-	}
-
-	user, err := uc.userRepo.FindByEmail(email)
+	hashedPassword, err := uc.security.HashPassword(ctx, input.NewPassword)
 	if err != nil {
 		return err
 	}
 
-	hashedPassword, err := uc.security.HashPassword(input.NewPassword)
-	if err != nil {
-		return err
-	}
-	user.Password = hashedPassword
+	// The password only changes if the token is consumed with it, so a token cannot be used twice:
+	// DeleteByID fails for the second of two concurrent resets.
+	err = uc.uow.Do(ctx, func(repos transaction.Repositories) error {
+		token, err := repos.Tokens.FindByToken(ctx, uc.security.HashOTP(input.Token))
+		if err != nil {
+			return tokenDomain.ErrTokenNotFound
+		}
 
-	if err := uc.userRepo.Update(user); err != nil {
-		return err
-	}
+		user, err := repos.Users.FindByEmail(ctx, email)
+		if err != nil {
+			return err
+		}
+
+		user.Password = hashedPassword
 
-	if err := uc.tokenRepo.DeleteByID(token.ID); err != nil {
+		if err := repos.Users.Update(ctx, user); err != nil {
+			return err
+		}
+
+		return repos.Tokens.DeleteByID(ctx, token.ID)
+	})
+	if err != nil {
 		return err
 	}
 
+	metrics.RecordFunnelStep(metrics.FunnelPasswordReset, metrics.StepCompleted)
+
 	return nil
 }
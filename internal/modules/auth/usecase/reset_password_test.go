@@ -50,7 +50,8 @@ func TestResetPassword_SuccessfulReset(t *testing.T) {
 
 	// Setup expectations
 	mockSecurity.On("ValidateJWT", validToken).Return(claims, nil)
-	mockTokenRepo.On("FindByToken", validToken).Return(token, nil)
+	mockSecurity.On("HashOTP", validToken).Return("hashed.reset.token")
+	mockTokenRepo.On("FindByToken", "hashed.reset.token").Return(token, nil)
 	mockUserRepo.On("FindByEmail", email).Return(user, nil)
 	mockSecurity.On("HashPassword", "NewSecurePassword123!").Return("new-hashed-password", nil)
 	mockUserRepo.On("Update", mock.MatchedBy(func(u *userDomain.User) bool {
@@ -58,10 +59,10 @@ func TestResetPassword_SuccessfulReset(t *testing.T) {
 	})).Return(nil)
 	mockTokenRepo.On("DeleteByID", tokenID).Return(nil)
 
-	useCase := NewResetPasswordUseCase(mockTokenRepo, mockUserRepo, mockSecurity)
+	useCase := NewResetPasswordUseCase(mocks.NewMockUnitOfWork(mockUserRepo, mockTokenRepo), mockSecurity)
 
 	// Act
-	err := useCase.Execute(ResetPasswordInput{
+	err := useCase.Execute(t.Context(), ResetPasswordInput{
 		Token:                 validToken,
 		NewPassword:           "NewSecurePassword123!",
 		NewPasswordValidation: "NewSecurePassword123!",
@@ -81,10 +82,10 @@ func TestResetPassword_PasswordMismatch(t *testing.T) {
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockTokenRepo := new(mocks.MockTokenRepository)
 
-	useCase := NewResetPasswordUseCase(mockTokenRepo, mockUserRepo, mockSecurity)
+	useCase := NewResetPasswordUseCase(mocks.NewMockUnitOfWork(mockUserRepo, mockTokenRepo), mockSecurity)
 
 	// Act
-	err := useCase.Execute(ResetPasswordInput{
+	err := useCase.Execute(t.Context(), ResetPasswordInput{
 		Token:                 "valid.jwt.token",
 		NewPassword:           "Password123!",
 		NewPasswordValidation: "DifferentPassword123!",
@@ -104,10 +105,10 @@ func TestResetPassword_InvalidPassword(t *testing.T) {
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockTokenRepo := new(mocks.MockTokenRepository)
 
-	useCase := NewResetPasswordUseCase(mockTokenRepo, mockUserRepo, mockSecurity)
+	useCase := NewResetPasswordUseCase(mocks.NewMockUnitOfWork(mockUserRepo, mockTokenRepo), mockSecurity)
 
 	// Act
-	err := useCase.Execute(ResetPasswordInput{
+	err := useCase.Execute(t.Context(), ResetPasswordInput{
 		Token:                 "valid.jwt.token",
 		NewPassword:           "weak",
 		NewPasswordValidation: "weak",
@@ -131,10 +132,10 @@ func TestResetPassword_InvalidToken(t *testing.T) {
 	emptyClaims := jwt.MapClaims{}
 	mockSecurity.On("ValidateJWT", invalidToken).Return(emptyClaims, errors.New("token invalide"))
 
-	useCase := NewResetPasswordUseCase(mockTokenRepo, mockUserRepo, mockSecurity)
+	useCase := NewResetPasswordUseCase(mocks.NewMockUnitOfWork(mockUserRepo, mockTokenRepo), mockSecurity)
 
 	// Act
-	err := useCase.Execute(ResetPasswordInput{
+	err := useCase.Execute(t.Context(), ResetPasswordInput{
 		Token:                 invalidToken,
 		NewPassword:           "NewSecurePassword123!",
 		NewPasswordValidation: "NewSecurePassword123!",
@@ -166,10 +167,10 @@ func TestResetPassword_InvalidTokenType(t *testing.T) {
 
 	mockSecurity.On("ValidateJWT", validToken).Return(claims, nil)
 
-	useCase := NewResetPasswordUseCase(mockTokenRepo, mockUserRepo, mockSecurity)
+	useCase := NewResetPasswordUseCase(mocks.NewMockUnitOfWork(mockUserRepo, mockTokenRepo), mockSecurity)
 
 	// Act
-	err := useCase.Execute(ResetPasswordInput{
+	err := useCase.Execute(t.Context(), ResetPasswordInput{
 		Token:                 validToken,
 		NewPassword:           "NewSecurePassword123!",
 		NewPasswordValidation: "NewSecurePassword123!",
@@ -200,10 +201,10 @@ func TestResetPassword_ExpiredToken(t *testing.T) {
 
 	mockSecurity.On("ValidateJWT", validToken).Return(claims, nil)
 
-	useCase := NewResetPasswordUseCase(mockTokenRepo, mockUserRepo, mockSecurity)
+	useCase := NewResetPasswordUseCase(mocks.NewMockUnitOfWork(mockUserRepo, mockTokenRepo), mockSecurity)
 
 	// Act
-	err := useCase.Execute(ResetPasswordInput{
+	err := useCase.Execute(t.Context(), ResetPasswordInput{
 		Token:                 validToken,
 		NewPassword:           "NewSecurePassword123!",
 		NewPasswordValidation: "NewSecurePassword123!",
@@ -234,10 +235,10 @@ func TestResetPassword_MissingEmail(t *testing.T) {
 
 	mockSecurity.On("ValidateJWT", validToken).Return(claims, nil)
 
-	useCase := NewResetPasswordUseCase(mockTokenRepo, mockUserRepo, mockSecurity)
+	useCase := NewResetPasswordUseCase(mocks.NewMockUnitOfWork(mockUserRepo, mockTokenRepo), mockSecurity)
 
 	// Act
-	err := useCase.Execute(ResetPasswordInput{
+	err := useCase.Execute(t.Context(), ResetPasswordInput{
 		Token:                 validToken,
 		NewPassword:           "NewSecurePassword123!",
 		NewPasswordValidation: "NewSecurePassword123!",
@@ -267,12 +268,14 @@ func TestResetPassword_TokenNotFound(t *testing.T) {
 	}
 
 	mockSecurity.On("ValidateJWT", validToken).Return(claims, nil)
-	mockTokenRepo.On("FindByToken", validToken).Return(nil, tokenDomain.ErrTokenNotFound)
+	mockSecurity.On("HashPassword", "NewSecurePassword123!").Return("new-hashed-password", nil)
+	mockSecurity.On("HashOTP", validToken).Return("hashed.reset.token")
+	mockTokenRepo.On("FindByToken", "hashed.reset.token").Return(nil, tokenDomain.ErrTokenNotFound)
 
-	useCase := NewResetPasswordUseCase(mockTokenRepo, mockUserRepo, mockSecurity)
+	useCase := NewResetPasswordUseCase(mocks.NewMockUnitOfWork(mockUserRepo, mockTokenRepo), mockSecurity)
 
 	// Act
-	err := useCase.Execute(ResetPasswordInput{
+	err := useCase.Execute(t.Context(), ResetPasswordInput{
 		Token:                 validToken,
 		NewPassword:           "NewSecurePassword123!",
 		NewPasswordValidation: "NewSecurePassword123!",
@@ -314,13 +317,15 @@ func TestResetPassword_UserNotFound(t *testing.T) {
 	}
 
 	mockSecurity.On("ValidateJWT", validToken).Return(claims, nil)
-	mockTokenRepo.On("FindByToken", validToken).Return(token, nil)
+	mockSecurity.On("HashOTP", validToken).Return("hashed.reset.token")
+	mockSecurity.On("HashPassword", "NewSecurePassword123!").Return("new-hashed-password", nil)
+	mockTokenRepo.On("FindByToken", "hashed.reset.token").Return(token, nil)
 	mockUserRepo.On("FindByEmail", email).Return(nil, userDomain.ErrUserNotFound)
 
-	useCase := NewResetPasswordUseCase(mockTokenRepo, mockUserRepo, mockSecurity)
+	useCase := NewResetPasswordUseCase(mocks.NewMockUnitOfWork(mockUserRepo, mockTokenRepo), mockSecurity)
 
 	// Act
-	err := useCase.Execute(ResetPasswordInput{
+	err := useCase.Execute(t.Context(), ResetPasswordInput{
 		Token:                 validToken,
 		NewPassword:           "NewSecurePassword123!",
 		NewPasswordValidation: "NewSecurePassword123!",
@@ -341,9 +346,6 @@ func TestResetPassword_HashPasswordError(t *testing.T) {
 	mockTokenRepo := new(mocks.MockTokenRepository)
 
 	validToken := "valid.jwt.token"
-	tokenID := uuid.New()
-	userID := uuid.New()
-	email := "user@example.com"
 
 	// Mock claims for token validation
 	claims := jwt.MapClaims{
@@ -352,31 +354,13 @@ func TestResetPassword_HashPasswordError(t *testing.T) {
 		"email": "user@example.com",
 	}
 
-	// Create mock token
-	token := &tokenDomain.Token{
-		ID:        tokenID,
-		UserID:    userID,
-		Token:     validToken,
-		CreatedAt: time.Now(),
-		ExpiresAt: time.Now().Add(time.Hour),
-	}
-
-	// Create user
-	user := &userDomain.User{
-		ID:       userID,
-		Email:    email,
-		Password: "old-hashed-password",
-	}
-
 	mockSecurity.On("ValidateJWT", validToken).Return(claims, nil)
-	mockTokenRepo.On("FindByToken", validToken).Return(token, nil)
-	mockUserRepo.On("FindByEmail", email).Return(user, nil)
 	mockSecurity.On("HashPassword", "NewSecurePassword123!").Return("", errors.New("erreur de hashage"))
 
-	useCase := NewResetPasswordUseCase(mockTokenRepo, mockUserRepo, mockSecurity)
+	useCase := NewResetPasswordUseCase(mocks.NewMockUnitOfWork(mockUserRepo, mockTokenRepo), mockSecurity)
 
 	// Act
-	err := useCase.Execute(ResetPasswordInput{
+	err := useCase.Execute(t.Context(), ResetPasswordInput{
 		Token:                 validToken,
 		NewPassword:           "NewSecurePassword123!",
 		NewPasswordValidation: "NewSecurePassword123!",
@@ -387,7 +371,49 @@ func TestResetPassword_HashPasswordError(t *testing.T) {
 	assert.Contains(t, err.Error(), "erreur de hashage")
 
 	mockSecurity.AssertExpectations(t)
-	mockTokenRepo.AssertExpectations(t)
-	mockUserRepo.AssertExpectations(t)
-	mockUserRepo.AssertNotCalled(t, "Update")
+	mockTokenRepo.AssertNotCalled(t, "FindByToken", mock.Anything)
+	mockUserRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestResetPassword_TokenDeletionFailureRollsBackThePassword(t *testing.T) {
+	// Arrange
+	mockSecurity := new(mocks.MockSecurityService)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockTokenRepo := new(mocks.MockTokenRepository)
+
+	validToken := "valid.jwt.token"
+	tokenID := uuid.New()
+	email := "user@example.com"
+
+	claims := jwt.MapClaims{
+		"type":  "reset_password",
+		"exp":   float64(time.Now().Add(time.Hour).Unix()),
+		"email": email,
+	}
+	token := &tokenDomain.Token{ID: tokenID, Token: validToken, ExpiresAt: time.Now().Add(time.Hour)}
+	user := &userDomain.User{ID: uuid.New(), Email: email, Password: "old-hashed-password"}
+	deletionErr := errors.New("connection reset")
+
+	mockSecurity.On("ValidateJWT", validToken).Return(claims, nil)
+	mockSecurity.On("HashOTP", validToken).Return("hashed.reset.token")
+	mockTokenRepo.On("FindByToken", "hashed.reset.token").Return(token, nil)
+	mockUserRepo.On("FindByEmail", email).Return(user, nil)
+	mockSecurity.On("HashPassword", "NewSecurePassword123!").Return("new-hashed-password", nil)
+	mockUserRepo.On("Update", mock.Anything).Return(nil)
+	mockTokenRepo.On("DeleteByID", tokenID).Return(deletionErr)
+
+	uow := mocks.NewMockUnitOfWork(mockUserRepo, mockTokenRepo)
+	useCase := NewResetPasswordUseCase(uow, mockSecurity)
+
+	// Act
+	err := useCase.Execute(t.Context(), ResetPasswordInput{
+		Token:                 validToken,
+		NewPassword:           "NewSecurePassword123!",
+		NewPasswordValidation: "NewSecurePassword123!",
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, deletionErr)
+	assert.Equal(t, 1, uow.Rollbacks)
+	assert.Equal(t, 0, uow.Commits)
 }
@@ -0,0 +1,36 @@
+package useCase
+
+import (
+	"context"
+	"jamlink-backend/internal/modules/auth/domain/token"
+	"jamlink-backend/internal/modules/auth/domain/user"
+)
+
+// RevokeUserSessionsUseCase signs a user out everywhere: refresh tokens can no longer be used, and with
+// the opaque token strategy, access tokens stop working at once. JWT access tokens stay valid until
+// they expire.
+type RevokeUserSessionsUseCase struct {
+	userRepo  user.UserRepository
+	tokenRepo token.TokenRepository
+}
+
+type RevokeUserSessionsInput struct {
+	Email string
+}
+
+func NewRevokeUserSessionsUseCase(userRepo user.UserRepository, tokenRepo token.TokenRepository) *RevokeUserSessionsUseCase {
+	return &RevokeUserSessionsUseCase{userRepo: userRepo, tokenRepo: tokenRepo}
+}
+
+func (uc *RevokeUserSessionsUseCase) Execute(ctx context.Context, input RevokeUserSessionsInput) (*user.User, error) {
+	foundUser, err := uc.userRepo.FindByEmail(ctx, input.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.tokenRepo.DeleteUserTokens(ctx, foundUser.ID); err != nil {
+		return nil, err
+	}
+
+	return foundUser, nil
+}
@@ -0,0 +1,41 @@
+package useCase
+
+import (
+	"jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/auth/mocks"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevokeUserSessionsUseCase_Execute_DeletesTokens(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockTokenRepo := new(mocks.MockTokenRepository)
+	uc := NewRevokeUserSessionsUseCase(mockUserRepo, mockTokenRepo)
+	found := &user.User{ID: uuid.New(), Email: "test@example.com"}
+
+	mockUserRepo.On("FindByEmail", "test@example.com").Return(found, nil)
+	mockTokenRepo.On("DeleteUserTokens", found.ID).Return(nil)
+
+	revoked, err := uc.Execute(t.Context(), RevokeUserSessionsInput{Email: "test@example.com"})
+
+	require.NoError(t, err)
+	assert.Equal(t, found.ID, revoked.ID)
+	mockTokenRepo.AssertExpectations(t)
+}
+
+func TestRevokeUserSessionsUseCase_Execute_UnknownUser(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockTokenRepo := new(mocks.MockTokenRepository)
+	uc := NewRevokeUserSessionsUseCase(mockUserRepo, mockTokenRepo)
+
+	mockUserRepo.On("FindByEmail", "nobody@example.com").Return(nil, user.ErrUserNotFound)
+
+	_, err := uc.Execute(t.Context(), RevokeUserSessionsInput{Email: "nobody@example.com"})
+
+	assert.ErrorIs(t, err, user.ErrUserNotFound)
+	mockTokenRepo.AssertNotCalled(t, "DeleteUserTokens", mock.Anything)
+}
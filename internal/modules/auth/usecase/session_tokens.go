@@ -0,0 +1,50 @@
+package useCase
+
+import (
+	"context"
+	tokenDomain "jamlink-backend/internal/modules/auth/domain/token"
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/shared/security"
+	"time"
+)
+
+// TokenLifetimes are the lifetimes of the tokens issued by the use cases, set from config.TokenConfig.
+type TokenLifetimes struct {
+	Access            time.Duration
+	Refresh           time.Duration
+	ResetPassword     time.Duration
+	Impersonation     time.Duration
+	ClientCredentials time.Duration
+}
+
+// issueSessionTokens generates the access and refresh tokens of a new session and stores the hash of
+// both, so the refresh token can later be rotated by RefreshTokenUseCase.
+func issueSessionTokens(ctx context.Context, securitySvc security.SecurityService, tokenRepo tokenDomain.TokenRepository, lifetimes TokenLifetimes, u *userDomain.User, auth *security.AuthContext) (string, string, error) {
+	isVerified, claimOpts := verificationClaims(u)
+
+	token, err := securitySvc.GenerateJWT(ctx, &u.ID, nil, lifetimes.Access, security.AccessTokenType, isVerified, auth, claimOpts...)
+	if err != nil {
+		return "", "", err
+	}
+	inDBToken, err := tokenDomain.CreateToken(u.ID, securitySvc.HashOTP(token), time.Now().Add(lifetimes.Access))
+	if err != nil {
+		return "", "", err
+	}
+	if err := tokenRepo.Create(ctx, inDBToken); err != nil {
+		return "", "", err
+	}
+
+	refreshToken, err := securitySvc.GenerateJWT(ctx, &u.ID, nil, lifetimes.Refresh, security.RefreshTokenType, isVerified, auth, claimOpts...)
+	if err != nil {
+		return "", "", err
+	}
+	inDBRefreshToken, err := tokenDomain.CreateToken(u.ID, securitySvc.HashOTP(refreshToken), time.Now().Add(lifetimes.Refresh))
+	if err != nil {
+		return "", "", err
+	}
+	if err := tokenRepo.Create(ctx, inDBRefreshToken); err != nil {
+		return "", "", err
+	}
+
+	return token, refreshToken, nil
+}
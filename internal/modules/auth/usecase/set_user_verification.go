@@ -0,0 +1,42 @@
+package useCase
+
+import (
+	"context"
+	"jamlink-backend/internal/modules/auth/domain/user"
+	"time"
+)
+
+// SetUserVerificationUseCase verifies or unverifies an account on behalf of support, for users who
+// cannot receive the verification email.
+type SetUserVerificationUseCase struct {
+	repo user.UserRepository
+}
+
+type SetUserVerificationInput struct {
+	Email    string
+	Verified bool
+}
+
+func NewSetUserVerificationUseCase(repo user.UserRepository) *SetUserVerificationUseCase {
+	return &SetUserVerificationUseCase{repo: repo}
+}
+
+func (uc *SetUserVerificationUseCase) Execute(ctx context.Context, input SetUserVerificationInput) (*user.User, error) {
+	foundUser, err := uc.repo.FindByEmail(ctx, input.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	foundUser.Verification.IsVerified = input.Verified
+	foundUser.Verification.VerifiedAt = nil
+	if input.Verified {
+		now := time.Now()
+		foundUser.Verification.VerifiedAt = &now
+	}
+
+	if err := uc.repo.Update(ctx, foundUser); err != nil {
+		return nil, err
+	}
+
+	return foundUser, nil
+}
@@ -0,0 +1,56 @@
+package useCase
+
+import (
+	"jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/auth/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetUserVerificationUseCase_Execute_Verifies(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	uc := NewSetUserVerificationUseCase(mockRepo)
+	found := &user.User{Email: "test@example.com"}
+
+	mockRepo.On("FindByEmail", "test@example.com").Return(found, nil)
+	mockRepo.On("Update", mock.AnythingOfType("*user.User")).Return(nil)
+
+	updated, err := uc.Execute(t.Context(), SetUserVerificationInput{Email: "test@example.com", Verified: true})
+
+	require.NoError(t, err)
+	assert.True(t, updated.Verification.IsVerified)
+	require.NotNil(t, updated.Verification.VerifiedAt)
+	assert.WithinDuration(t, time.Now(), *updated.Verification.VerifiedAt, time.Second)
+}
+
+func TestSetUserVerificationUseCase_Execute_Unverifies(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	uc := NewSetUserVerificationUseCase(mockRepo)
+	verifiedAt := time.Now().Add(-time.Hour)
+	found := &user.User{Email: "test@example.com", Verification: user.UserVerification{IsVerified: true, VerifiedAt: &verifiedAt}}
+
+	mockRepo.On("FindByEmail", "test@example.com").Return(found, nil)
+	mockRepo.On("Update", mock.AnythingOfType("*user.User")).Return(nil)
+
+	updated, err := uc.Execute(t.Context(), SetUserVerificationInput{Email: "test@example.com", Verified: false})
+
+	require.NoError(t, err)
+	assert.False(t, updated.Verification.IsVerified)
+	assert.Nil(t, updated.Verification.VerifiedAt)
+}
+
+func TestSetUserVerificationUseCase_Execute_UnknownUser(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	uc := NewSetUserVerificationUseCase(mockRepo)
+
+	mockRepo.On("FindByEmail", "nobody@example.com").Return(nil, user.ErrUserNotFound)
+
+	_, err := uc.Execute(t.Context(), SetUserVerificationInput{Email: "nobody@example.com", Verified: true})
+
+	assert.ErrorIs(t, err, user.ErrUserNotFound)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
@@ -0,0 +1,16 @@
+package useCase
+
+import (
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/shared/security"
+)
+
+// verificationClaims returns the isVerified value of the session tokens of u, and the claim naming the
+// missing step when the email is verified but a guardian has not consented yet.
+func verificationClaims(u *userDomain.User) (bool, []security.ClaimOption) {
+	if u.Verification.IsVerified && u.AwaitingGuardianConsent() {
+		return false, []security.ClaimOption{security.WithPendingVerification(security.PendingGuardianConsent)}
+	}
+
+	return u.IsVerified(), nil
+}
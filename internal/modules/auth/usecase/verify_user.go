@@ -1,7 +1,9 @@
 package useCase
 
 import (
+	"context"
 	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/shared/metrics"
 	"jamlink-backend/internal/shared/security"
 	"time"
 )
@@ -19,8 +21,8 @@ func NewVerifyUserUseCase(repo userDomain.UserRepository, security security.Secu
 	return &VerifyUserUseCase{repo: repo, security: security}
 }
 
-func (uc *VerifyUserUseCase) Execute(input VerifyUserInput) error {
-	claims, err := uc.security.ValidateJWT(input.Token)
+func (uc *VerifyUserUseCase) Execute(ctx context.Context, input VerifyUserInput) error {
+	claims, err := uc.security.ValidateJWT(ctx, input.Token)
 
 	if err != nil {
 		return err
@@ -32,7 +34,7 @@ func (uc *VerifyUserUseCase) Execute(input VerifyUserInput) error {
 		return security.ErrInvalidUserEmail
 	}
 
-	user, err := uc.repo.FindByEmail(mail)
+	user, err := uc.repo.FindByEmail(ctx, mail)
 
 	if err != nil {
 		return err
@@ -41,11 +43,12 @@ func (uc *VerifyUserUseCase) Execute(input VerifyUserInput) error {
 	user.Verification.IsVerified = true
 	now := time.Now()
 	user.Verification.VerifiedAt = &now
-	err = uc.repo.Update(user)
+	err = uc.repo.Update(ctx, user)
 
 	if err != nil {
 		return err
 	}
+	metrics.RecordFunnelStep(metrics.FunnelVerification, metrics.StepCompleted)
 
 	return nil
 }
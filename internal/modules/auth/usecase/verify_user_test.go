@@ -48,7 +48,7 @@ func TestVerifyUserUseCase_Execute_Success(t *testing.T) {
 		On("Update", mock.AnythingOfType("*user.User")).
 		Return(nil)
 
-	err := verifyUC.Execute(input)
+	err := verifyUC.Execute(t.Context(), input)
 
 	assert.NoError(t, err)
 	assert.True(t, user.Verification.IsVerified)
@@ -76,7 +76,7 @@ func TestVerifyUserUseCase_Execute_InvalidToken(t *testing.T) {
 		On("ValidateJWT", "invalid-token").
 		Return(claims, errors.New("invalid token"))
 
-	err := verifyUC.Execute(input)
+	err := verifyUC.Execute(t.Context(), input)
 
 	assert.Error(t, err, security.ErrInvalidToken)
 
@@ -101,7 +101,7 @@ func TestVerifyUserUseCase_Execute_NoMailInClaims(t *testing.T) {
 		On("ValidateJWT", "some-token").
 		Return(claims, nil)
 
-	err := verifyUC.Execute(input)
+	err := verifyUC.Execute(t.Context(), input)
 
 	assert.ErrorIs(t, err, security.ErrInvalidUserEmail)
 	mockSec.AssertExpectations(t)
@@ -0,0 +1,62 @@
+package useCase
+
+import (
+	"context"
+	"crypto/subtle"
+	"jamlink-backend/internal/modules/auth/domain/otp"
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/shared/metrics"
+	"jamlink-backend/internal/shared/security"
+	"time"
+)
+
+type VerifyUserWithCodeUseCase struct {
+	repo     userDomain.UserRepository
+	codeRepo otp.VerificationCodeRepository
+	security security.SecurityService
+}
+
+type VerifyUserWithCodeInput struct {
+	Email string `json:"email" binding:"required,email" example:"user@example.com"`
+	Code  string `json:"code" binding:"required,len=6,numeric" example:"123456"`
+}
+
+func NewVerifyUserWithCodeUseCase(repo userDomain.UserRepository, codeRepo otp.VerificationCodeRepository, security security.SecurityService) *VerifyUserWithCodeUseCase {
+	return &VerifyUserWithCodeUseCase{repo: repo, codeRepo: codeRepo, security: security}
+}
+
+func (uc *VerifyUserWithCodeUseCase) Execute(ctx context.Context, input VerifyUserWithCodeInput) error {
+	user, err := uc.repo.FindByEmail(ctx, input.Email)
+	if err != nil {
+		return otp.ErrInvalidCode
+	}
+
+	code, err := uc.codeRepo.FindLatestByUserID(ctx, user.ID)
+	if err != nil {
+		return otp.ErrInvalidCode
+	}
+
+	if code.IsExpired(time.Now()) {
+		return otp.ErrCodeExpired
+	}
+
+	// Every guess uses an attempt before being checked, so that parallel guesses are counted too.
+	if err := uc.codeRepo.UseAttempt(ctx, code.ID, otp.MaxAttempts); err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(uc.security.HashOTP(input.Code)), []byte(code.CodeHash)) != 1 {
+		return otp.ErrInvalidCode
+	}
+
+	user.Verification.IsVerified = true
+	now := time.Now()
+	user.Verification.VerifiedAt = &now
+
+	if err := uc.repo.Update(ctx, user); err != nil {
+		return err
+	}
+	metrics.RecordFunnelStep(metrics.FunnelVerification, metrics.StepCompleted)
+
+	return uc.codeRepo.DeleteByUserID(ctx, user.ID)
+}
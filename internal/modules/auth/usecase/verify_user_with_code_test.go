@@ -0,0 +1,112 @@
+package useCase
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/auth/domain/otp"
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/auth/mocks"
+	"testing"
+	"time"
+)
+
+func TestVerifyUserWithCode_Success(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockCodeRepo := new(mocks.MockVerificationCodeRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+
+	user := &userDomain.User{ID: uuid.New(), Email: "user@example.com"}
+	code := otp.CreateVerificationCode(user.ID, "hashed-code", time.Now().Add(otp.CodeTTL))
+
+	mockUserRepo.On("FindByEmail", user.Email).Return(user, nil)
+	mockCodeRepo.On("FindLatestByUserID", user.ID).Return(code, nil)
+	mockCodeRepo.On("UseAttempt", code.ID, otp.MaxAttempts).Return(nil)
+	mockSecurity.On("HashOTP", "123456").Return("hashed-code")
+	mockUserRepo.On("Update", mock.MatchedBy(func(u *userDomain.User) bool {
+		return u.Verification.IsVerified && u.Verification.VerifiedAt != nil
+	})).Return(nil)
+	mockCodeRepo.On("DeleteByUserID", user.ID).Return(nil)
+
+	usecase := NewVerifyUserWithCodeUseCase(mockUserRepo, mockCodeRepo, mockSecurity)
+	err := usecase.Execute(t.Context(), VerifyUserWithCodeInput{Email: user.Email, Code: "123456"})
+
+	assert.NoError(t, err)
+	mockUserRepo.AssertExpectations(t)
+	mockCodeRepo.AssertExpectations(t)
+	mockSecurity.AssertExpectations(t)
+}
+
+func TestVerifyUserWithCode_WrongCodeUsesAnAttempt(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockCodeRepo := new(mocks.MockVerificationCodeRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+
+	user := &userDomain.User{ID: uuid.New(), Email: "user@example.com"}
+	code := otp.CreateVerificationCode(user.ID, "hashed-code", time.Now().Add(otp.CodeTTL))
+
+	mockUserRepo.On("FindByEmail", user.Email).Return(user, nil)
+	mockCodeRepo.On("FindLatestByUserID", user.ID).Return(code, nil)
+	mockCodeRepo.On("UseAttempt", code.ID, otp.MaxAttempts).Return(nil)
+	mockSecurity.On("HashOTP", "000000").Return("other-hash")
+
+	usecase := NewVerifyUserWithCodeUseCase(mockUserRepo, mockCodeRepo, mockSecurity)
+	err := usecase.Execute(t.Context(), VerifyUserWithCodeInput{Email: user.Email, Code: "000000"})
+
+	assert.ErrorIs(t, err, otp.ErrInvalidCode)
+	mockCodeRepo.AssertExpectations(t)
+	mockUserRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestVerifyUserWithCode_Expired(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockCodeRepo := new(mocks.MockVerificationCodeRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+
+	user := &userDomain.User{ID: uuid.New(), Email: "user@example.com"}
+	code := otp.CreateVerificationCode(user.ID, "hashed-code", time.Now().Add(-time.Minute))
+
+	mockUserRepo.On("FindByEmail", user.Email).Return(user, nil)
+	mockCodeRepo.On("FindLatestByUserID", user.ID).Return(code, nil)
+
+	usecase := NewVerifyUserWithCodeUseCase(mockUserRepo, mockCodeRepo, mockSecurity)
+	err := usecase.Execute(t.Context(), VerifyUserWithCodeInput{Email: user.Email, Code: "123456"})
+
+	assert.ErrorIs(t, err, otp.ErrCodeExpired)
+	mockSecurity.AssertNotCalled(t, "HashOTP", mock.Anything)
+}
+
+func TestVerifyUserWithCode_TooManyAttempts(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockCodeRepo := new(mocks.MockVerificationCodeRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+
+	user := &userDomain.User{ID: uuid.New(), Email: "user@example.com"}
+	code := otp.CreateVerificationCode(user.ID, "hashed-code", time.Now().Add(otp.CodeTTL))
+	code.Attempts = otp.MaxAttempts
+
+	mockUserRepo.On("FindByEmail", user.Email).Return(user, nil)
+	mockCodeRepo.On("FindLatestByUserID", user.ID).Return(code, nil)
+	mockCodeRepo.On("UseAttempt", code.ID, otp.MaxAttempts).Return(otp.ErrTooManyAttempts)
+
+	usecase := NewVerifyUserWithCodeUseCase(mockUserRepo, mockCodeRepo, mockSecurity)
+	err := usecase.Execute(t.Context(), VerifyUserWithCodeInput{Email: user.Email, Code: "123456"})
+
+	assert.ErrorIs(t, err, otp.ErrTooManyAttempts)
+	mockSecurity.AssertNotCalled(t, "HashOTP", mock.Anything)
+}
+
+func TestVerifyUserWithCode_UnknownEmail(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockCodeRepo := new(mocks.MockVerificationCodeRepository)
+	mockSecurity := new(mocks.MockSecurityService)
+
+	mockUserRepo.On("FindByEmail", "ghost@example.com").Return(nil, errors.New("record not found"))
+
+	usecase := NewVerifyUserWithCodeUseCase(mockUserRepo, mockCodeRepo, mockSecurity)
+	err := usecase.Execute(t.Context(), VerifyUserWithCodeInput{Email: "ghost@example.com", Code: "123456"})
+
+	assert.ErrorIs(t, err, otp.ErrInvalidCode)
+	mockCodeRepo.AssertNotCalled(t, "FindLatestByUserID", mock.Anything)
+}
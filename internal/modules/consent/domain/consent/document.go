@@ -0,0 +1,53 @@
+package consent
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DocumentType string
+
+const (
+	DocumentTerms   DocumentType = "terms"
+	DocumentPrivacy DocumentType = "privacy"
+)
+
+// RequiredDocuments must be accepted, in their active version, to use the protected routes.
+var RequiredDocuments = []DocumentType{DocumentTerms, DocumentPrivacy}
+
+// Document is one published version of a legal document. The active version of a type is the most
+// recent one whose EffectiveAt has passed, so a new version can be announced before it applies.
+type Document struct {
+	ID          uuid.UUID    `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"-"`
+	Type        DocumentType `gorm:"type:varchar(32);not null;uniqueIndex:idx_document_type_version" json:"type"`
+	Version     string       `gorm:"type:varchar(32);not null;uniqueIndex:idx_document_type_version" json:"version"`
+	URL         string       `gorm:"type:varchar(255);not null" json:"url"`
+	EffectiveAt time.Time    `gorm:"not null;index" json:"effectiveAt"`
+	CreatedAt   time.Time    `gorm:"autoCreateTime" json:"-"`
+}
+
+func CreateDocument(docType DocumentType, version string, url string, effectiveAt time.Time) (*Document, error) {
+	if docType != DocumentTerms && docType != DocumentPrivacy {
+		return nil, ErrInvalidDocumentType
+	}
+
+	version = strings.TrimSpace(version)
+	if version == "" || len(version) > 32 {
+		return nil, ErrInvalidVersion
+	}
+
+	if effectiveAt.IsZero() {
+		effectiveAt = time.Now()
+	}
+
+	return &Document{
+		ID:          uuid.New(),
+		Type:        docType,
+		Version:     version,
+		URL:         url,
+		EffectiveAt: effectiveAt,
+		CreatedAt:   time.Now(),
+	}, nil
+}
@@ -0,0 +1,14 @@
+package consent
+
+import "jamlink-backend/internal/shared/apperror"
+
+var (
+	ErrDocumentNotFound     = apperror.NotFound("document_not_found", "document not found")
+	ErrDocumentExists       = apperror.Conflict("document_exists", "this document version already exists")
+	ErrInvalidDocumentType  = apperror.BadRequest("invalid_document_type", "invalid document type")
+	ErrInvalidVersion       = apperror.BadRequest("invalid_document_version", "invalid document version")
+	ErrOutdatedVersion      = apperror.Conflict("outdated_document_version", "the accepted version is not the current one")
+	ErrTermsNotAccepted     = apperror.BadRequest("terms_not_accepted", "the terms of service and privacy policy must be accepted")
+	ErrConsentNotFound      = apperror.NotFound("consent_not_found", "consent not found")
+	ErrPublishNotAuthorized = apperror.Forbidden("document_publish_forbidden", "only admins can publish documents")
+)
@@ -0,0 +1,64 @@
+package consent
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Kind string
+
+const (
+	KindTerms     Kind = "terms"
+	KindPrivacy   Kind = "privacy"
+	KindMarketing Kind = "marketing"
+)
+
+// Record is one entry of the consent ledger. Records are never updated nor deleted with the user's
+// data being kept: a withdrawal is a new record with Granted set to false.
+type Record struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"-"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index:idx_consent_user_kind" json:"-"`
+	Kind       Kind      `gorm:"type:varchar(32);not null;index:idx_consent_user_kind" json:"kind"`
+	Version    string    `gorm:"type:varchar(32)" json:"version,omitempty"`
+	Granted    bool      `gorm:"not null" json:"granted"`
+	IP         string    `gorm:"type:varchar(64)" json:"-"`
+	UserAgent  string    `gorm:"type:varchar(255)" json:"-"`
+	RecordedAt time.Time `gorm:"not null;index" json:"recordedAt"`
+}
+
+func (Record) TableName() string {
+	return "consent_records"
+}
+
+// Source is where a consent was given, kept as evidence.
+type Source struct {
+	IP        string
+	UserAgent string
+}
+
+func CreateAcceptance(userID uuid.UUID, document *Document, source Source) *Record {
+	return newRecord(userID, Kind(document.Type), document.Version, true, source)
+}
+
+func CreateMarketingConsent(userID uuid.UUID, granted bool, source Source) *Record {
+	return newRecord(userID, KindMarketing, "", granted, source)
+}
+
+func newRecord(userID uuid.UUID, kind Kind, version string, granted bool, source Source) *Record {
+	userAgent := source.UserAgent
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	return &Record{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Kind:       kind,
+		Version:    version,
+		Granted:    granted,
+		IP:         source.IP,
+		UserAgent:  userAgent,
+		RecordedAt: time.Now(),
+	}
+}
@@ -0,0 +1,23 @@
+package consent
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DocumentRepository interface {
+	Create(ctx context.Context, document *Document) error
+	// FindActive returns ErrDocumentNotFound when no version of the type is in effect at the given time.
+	FindActive(ctx context.Context, docType DocumentType, at time.Time) (*Document, error)
+	FindByVersion(ctx context.Context, docType DocumentType, version string) (*Document, error)
+	FindAll(ctx context.Context) ([]Document, error)
+}
+
+type RecordRepository interface {
+	Create(ctx context.Context, records ...*Record) error
+	// FindLatest returns ErrConsentNotFound when the user never gave this kind of consent.
+	FindLatest(ctx context.Context, userID uuid.UUID, kind Kind) (*Record, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]Record, error)
+}
@@ -0,0 +1,17 @@
+package consentinfra
+
+import (
+	"embed"
+	"io/fs"
+
+	"jamlink-backend/internal/infra/db/migrate"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrations returns the SQL migrations of the consent module.
+func Migrations() migrate.Source {
+	files, _ := fs.Sub(migrations, "migrations")
+	return migrate.Source{Module: "consent", Dir: "internal/modules/consent/infra/migrations", Files: files}
+}
@@ -0,0 +1,27 @@
+-- Baseline of a schema previously created by gorm's AutoMigrate: IF NOT EXISTS lets databases
+-- created that way adopt it.
+CREATE TABLE IF NOT EXISTS documents (
+	id uuid DEFAULT gen_random_uuid(),
+	type varchar(32) NOT NULL,
+	version varchar(32) NOT NULL,
+	url varchar(255) NOT NULL,
+	effective_at timestamptz NOT NULL,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_document_type_version ON documents (type, version);
+CREATE INDEX IF NOT EXISTS idx_documents_effective_at ON documents (effective_at);
+
+CREATE TABLE IF NOT EXISTS consent_records (
+	id uuid DEFAULT gen_random_uuid(),
+	user_id uuid NOT NULL,
+	kind varchar(32) NOT NULL,
+	version varchar(32),
+	granted boolean NOT NULL,
+	ip varchar(64),
+	user_agent varchar(255),
+	recorded_at timestamptz NOT NULL,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_consent_user_kind ON consent_records (user_id, kind);
+CREATE INDEX IF NOT EXISTS idx_consent_records_recorded_at ON consent_records (recorded_at);
@@ -0,0 +1 @@
+ALTER TABLE consent_records DROP CONSTRAINT IF EXISTS fk_consent_records_user;
@@ -0,0 +1,5 @@
+-- The consents of a deleted user, with the IP address and user agent they were given from, go with the
+-- account. Records left behind by deletions made before this key are removed first.
+DELETE FROM consent_records WHERE user_id NOT IN (SELECT id FROM users);
+
+ALTER TABLE consent_records ADD CONSTRAINT fk_consent_records_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/consent/domain/consent"
+)
+
+type MockDocumentRepository struct {
+	mock.Mock
+}
+
+func (m *MockDocumentRepository) Create(_ context.Context, document *consent.Document) error {
+	args := m.Called(document)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRepository) FindActive(_ context.Context, docType consent.DocumentType, at time.Time) (*consent.Document, error) {
+	args := m.Called(docType, at)
+	document := args.Get(0)
+	if document == nil {
+		return nil, args.Error(1)
+	}
+	return document.(*consent.Document), args.Error(1)
+}
+
+func (m *MockDocumentRepository) FindByVersion(_ context.Context, docType consent.DocumentType, version string) (*consent.Document, error) {
+	args := m.Called(docType, version)
+	document := args.Get(0)
+	if document == nil {
+		return nil, args.Error(1)
+	}
+	return document.(*consent.Document), args.Error(1)
+}
+
+func (m *MockDocumentRepository) FindAll(_ context.Context) ([]consent.Document, error) {
+	args := m.Called()
+	return args.Get(0).([]consent.Document), args.Error(1)
+}
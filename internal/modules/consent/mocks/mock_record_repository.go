@@ -0,0 +1,32 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/consent/domain/consent"
+)
+
+type MockRecordRepository struct {
+	mock.Mock
+}
+
+func (m *MockRecordRepository) Create(_ context.Context, records ...*consent.Record) error {
+	args := m.Called(records)
+	return args.Error(0)
+}
+
+func (m *MockRecordRepository) FindLatest(_ context.Context, userID uuid.UUID, kind consent.Kind) (*consent.Record, error) {
+	args := m.Called(userID, kind)
+	record := args.Get(0)
+	if record == nil {
+		return nil, args.Error(1)
+	}
+	return record.(*consent.Record), args.Error(1)
+}
+
+func (m *MockRecordRepository) FindByUserID(_ context.Context, userID uuid.UUID) ([]consent.Record, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]consent.Record), args.Error(1)
+}
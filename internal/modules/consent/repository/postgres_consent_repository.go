@@ -0,0 +1,94 @@
+package consentRepository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"jamlink-backend/internal/modules/consent/domain/consent"
+)
+
+type PostgresDocumentRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresDocumentRepository(db *gorm.DB) *PostgresDocumentRepository {
+	return &PostgresDocumentRepository{db: db}
+}
+
+func (r *PostgresDocumentRepository) Create(ctx context.Context, document *consent.Document) error {
+	return r.db.WithContext(ctx).Create(document).Error
+}
+
+func (r *PostgresDocumentRepository) FindActive(ctx context.Context, docType consent.DocumentType, at time.Time) (*consent.Document, error) {
+	var document consent.Document
+
+	err := r.db.WithContext(ctx).Where("type = ? AND effective_at <= ?", docType, at).Order("effective_at DESC").First(&document).Error
+	if err != nil {
+		return nil, notFoundAs(err, consent.ErrDocumentNotFound)
+	}
+
+	return &document, nil
+}
+
+func (r *PostgresDocumentRepository) FindByVersion(ctx context.Context, docType consent.DocumentType, version string) (*consent.Document, error) {
+	var document consent.Document
+
+	if err := r.db.WithContext(ctx).Where("type = ? AND version = ?", docType, version).First(&document).Error; err != nil {
+		return nil, notFoundAs(err, consent.ErrDocumentNotFound)
+	}
+
+	return &document, nil
+}
+
+func (r *PostgresDocumentRepository) FindAll(ctx context.Context) ([]consent.Document, error) {
+	var documents []consent.Document
+
+	err := r.db.WithContext(ctx).Order("type, effective_at DESC").Find(&documents).Error
+
+	return documents, err
+}
+
+type PostgresRecordRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresRecordRepository(db *gorm.DB) *PostgresRecordRepository {
+	return &PostgresRecordRepository{db: db}
+}
+
+func (r *PostgresRecordRepository) Create(ctx context.Context, records ...*consent.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(records).Error
+}
+
+func (r *PostgresRecordRepository) FindLatest(ctx context.Context, userID uuid.UUID, kind consent.Kind) (*consent.Record, error) {
+	var record consent.Record
+
+	err := r.db.WithContext(ctx).Where("user_id = ? AND kind = ?", userID, kind).Order("recorded_at DESC").First(&record).Error
+	if err != nil {
+		return nil, notFoundAs(err, consent.ErrConsentNotFound)
+	}
+
+	return &record, nil
+}
+
+func (r *PostgresRecordRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]consent.Record, error) {
+	var records []consent.Record
+
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("recorded_at DESC").Find(&records).Error
+
+	return records, err
+}
+
+// notFoundAs translates gorm's not-found error into the given domain error.
+func notFoundAs(err error, domainErr error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domainErr
+	}
+	return err
+}
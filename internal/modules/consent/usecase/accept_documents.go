@@ -0,0 +1,59 @@
+package consentUseCase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"jamlink-backend/internal/modules/consent/domain/consent"
+)
+
+type AcceptDocumentsInput struct {
+	UserID         uuid.UUID      `json:"-"`
+	TermsVersion   string         `json:"termsVersion" example:"2025-01"`
+	PrivacyVersion string         `json:"privacyVersion" example:"2025-01"`
+	Source         consent.Source `json:"-"`
+}
+
+type AcceptDocumentsUseCase struct {
+	docs    consent.DocumentRepository
+	records consent.RecordRepository
+}
+
+func NewAcceptDocumentsUseCase(docs consent.DocumentRepository, records consent.RecordRepository) *AcceptDocumentsUseCase {
+	return &AcceptDocumentsUseCase{docs: docs, records: records}
+}
+
+// Execute records the acceptance of the active versions. Each version sent must be the active one, so a
+// user cannot accept a text they were not shown; a document left empty is not accepted.
+func (uc *AcceptDocumentsUseCase) Execute(ctx context.Context, input AcceptDocumentsInput) error {
+	active, err := activeRequiredDocuments(ctx, uc.docs, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var records []*consent.Record
+	for _, document := range active {
+		version := versionFor(document.Type, input.TermsVersion, input.PrivacyVersion)
+		if version == "" {
+			continue
+		}
+		if version != document.Version {
+			return consent.ErrOutdatedVersion
+		}
+		records = append(records, consent.CreateAcceptance(input.UserID, document, input.Source))
+	}
+
+	if len(records) == 0 && len(active) > 0 {
+		return consent.ErrTermsNotAccepted
+	}
+
+	return uc.records.Create(ctx, records...)
+}
+
+func versionFor(docType consent.DocumentType, termsVersion, privacyVersion string) string {
+	if docType == consent.DocumentTerms {
+		return termsVersion
+	}
+	return privacyVersion
+}
@@ -0,0 +1,57 @@
+package consentUseCase
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/consent/domain/consent"
+	"jamlink-backend/internal/modules/consent/mocks"
+)
+
+func TestAcceptDocuments_Success(t *testing.T) {
+	docs := new(mocks.MockDocumentRepository)
+	records := new(mocks.MockRecordRepository)
+	userID := uuid.New()
+
+	activeDocuments(docs, "2025-02", "2024-06")
+	records.On("Create", mock.MatchedBy(func(created []*consent.Record) bool {
+		return len(created) == 2 &&
+			created[0].Kind == consent.KindTerms && created[0].Version == "2025-02" && created[0].Granted &&
+			created[1].Kind == consent.KindPrivacy && created[1].Version == "2024-06" && created[1].UserID == userID
+	})).Return(nil)
+
+	err := NewAcceptDocumentsUseCase(docs, records).Execute(t.Context(), AcceptDocumentsInput{
+		UserID:         userID,
+		TermsVersion:   "2025-02",
+		PrivacyVersion: "2024-06",
+		Source:         consent.Source{IP: "203.0.113.7"},
+	})
+
+	assert.NoError(t, err)
+	records.AssertExpectations(t)
+}
+
+func TestAcceptDocuments_OutdatedVersion(t *testing.T) {
+	docs := new(mocks.MockDocumentRepository)
+	records := new(mocks.MockRecordRepository)
+
+	activeDocuments(docs, "2025-02", "2024-06")
+
+	err := NewAcceptDocumentsUseCase(docs, records).Execute(t.Context(), AcceptDocumentsInput{UserID: uuid.New(), TermsVersion: "2025-01"})
+
+	assert.ErrorIs(t, err, consent.ErrOutdatedVersion)
+	records.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestAcceptDocuments_NothingAccepted(t *testing.T) {
+	docs := new(mocks.MockDocumentRepository)
+	records := new(mocks.MockRecordRepository)
+
+	activeDocuments(docs, "2025-02", "2024-06")
+
+	err := NewAcceptDocumentsUseCase(docs, records).Execute(t.Context(), AcceptDocumentsInput{UserID: uuid.New()})
+
+	assert.ErrorIs(t, err, consent.ErrTermsNotAccepted)
+}
@@ -0,0 +1,64 @@
+package consentUseCase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"jamlink-backend/internal/modules/consent/domain/consent"
+)
+
+// activeDocumentsTTL bounds how long a newly effective version may go unnoticed by the gate, which runs
+// on every protected request.
+const activeDocumentsTTL = time.Minute
+
+// ConsentGate tells whether a user must accept the current terms or privacy policy before going on.
+type ConsentGate struct {
+	docs    consent.DocumentRepository
+	records consent.RecordRepository
+
+	mu        sync.Mutex
+	active    []*consent.Document
+	fetchedAt time.Time
+}
+
+func NewConsentGate(docs consent.DocumentRepository, records consent.RecordRepository) *ConsentGate {
+	return &ConsentGate{docs: docs, records: records}
+}
+
+func (g *ConsentGate) RequiresReaccept(ctx context.Context, userID uuid.UUID) (bool, error) {
+	active, err := g.activeDocuments(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for _, document := range active {
+		record, err := latestConsent(ctx, g.records, userID, consent.Kind(document.Type))
+		if err != nil {
+			return false, err
+		}
+		if !isAccepted(record, document) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (g *ConsentGate) activeDocuments(ctx context.Context) ([]*consent.Document, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.active != nil && time.Since(g.fetchedAt) < activeDocumentsTTL {
+		return g.active, nil
+	}
+
+	active, err := activeRequiredDocuments(ctx, g.docs, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	g.active, g.fetchedAt = active, time.Now()
+	return active, nil
+}
@@ -0,0 +1,87 @@
+package consentUseCase
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/consent/domain/consent"
+	"jamlink-backend/internal/modules/consent/mocks"
+)
+
+func activeDocuments(docs *mocks.MockDocumentRepository, termsVersion, privacyVersion string) {
+	docs.On("FindActive", consent.DocumentTerms, mock.Anything).Return(&consent.Document{Type: consent.DocumentTerms, Version: termsVersion}, nil)
+	docs.On("FindActive", consent.DocumentPrivacy, mock.Anything).Return(&consent.Document{Type: consent.DocumentPrivacy, Version: privacyVersion}, nil)
+}
+
+func TestConsentGate_UpToDate(t *testing.T) {
+	docs := new(mocks.MockDocumentRepository)
+	records := new(mocks.MockRecordRepository)
+	userID := uuid.New()
+
+	activeDocuments(docs, "2025-01", "2024-06")
+	records.On("FindLatest", userID, consent.KindTerms).Return(&consent.Record{Version: "2025-01", Granted: true}, nil)
+	records.On("FindLatest", userID, consent.KindPrivacy).Return(&consent.Record{Version: "2024-06", Granted: true}, nil)
+
+	required, err := NewConsentGate(docs, records).RequiresReaccept(t.Context(), userID)
+
+	assert.NoError(t, err)
+	assert.False(t, required)
+}
+
+func TestConsentGate_NewTermsVersion(t *testing.T) {
+	docs := new(mocks.MockDocumentRepository)
+	records := new(mocks.MockRecordRepository)
+	userID := uuid.New()
+
+	activeDocuments(docs, "2025-02", "2024-06")
+	records.On("FindLatest", userID, consent.KindTerms).Return(&consent.Record{Version: "2025-01", Granted: true}, nil)
+
+	required, err := NewConsentGate(docs, records).RequiresReaccept(t.Context(), userID)
+
+	assert.NoError(t, err)
+	assert.True(t, required)
+}
+
+func TestConsentGate_NeverAccepted(t *testing.T) {
+	docs := new(mocks.MockDocumentRepository)
+	records := new(mocks.MockRecordRepository)
+	userID := uuid.New()
+
+	activeDocuments(docs, "2025-01", "2024-06")
+	records.On("FindLatest", userID, consent.KindTerms).Return(nil, consent.ErrConsentNotFound)
+
+	required, err := NewConsentGate(docs, records).RequiresReaccept(t.Context(), userID)
+
+	assert.NoError(t, err)
+	assert.True(t, required)
+}
+
+func TestConsentGate_NothingPublished(t *testing.T) {
+	docs := new(mocks.MockDocumentRepository)
+	records := new(mocks.MockRecordRepository)
+
+	docs.On("FindActive", mock.Anything, mock.Anything).Return(nil, consent.ErrDocumentNotFound)
+
+	required, err := NewConsentGate(docs, records).RequiresReaccept(t.Context(), uuid.New())
+
+	assert.NoError(t, err)
+	assert.False(t, required)
+	records.AssertNotCalled(t, "FindLatest", mock.Anything, mock.Anything)
+}
+
+func TestConsentGate_CachesActiveDocuments(t *testing.T) {
+	docs := new(mocks.MockDocumentRepository)
+	records := new(mocks.MockRecordRepository)
+	userID := uuid.New()
+
+	activeDocuments(docs, "2025-01", "2024-06")
+	records.On("FindLatest", userID, mock.Anything).Return(&consent.Record{Version: "2025-01", Granted: true}, nil)
+
+	gate := NewConsentGate(docs, records)
+	_, _ = gate.RequiresReaccept(t.Context(), userID)
+	_, _ = gate.RequiresReaccept(t.Context(), userID)
+
+	docs.AssertNumberOfCalls(t, "FindActive", 2)
+}
@@ -0,0 +1,43 @@
+package consentUseCase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"jamlink-backend/internal/modules/consent/domain/consent"
+)
+
+// activeRequiredDocuments returns the versions of the required documents in effect at the given time.
+// Types with no published version yet are left out: nothing can be accepted for them.
+func activeRequiredDocuments(ctx context.Context, docs consent.DocumentRepository, at time.Time) ([]*consent.Document, error) {
+	active := make([]*consent.Document, 0, len(consent.RequiredDocuments))
+
+	for _, docType := range consent.RequiredDocuments {
+		document, err := docs.FindActive(ctx, docType, at)
+		if errors.Is(err, consent.ErrDocumentNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		active = append(active, document)
+	}
+
+	return active, nil
+}
+
+// latestConsent returns nil without error when the user never gave this kind of consent.
+func latestConsent(ctx context.Context, records consent.RecordRepository, userID uuid.UUID, kind consent.Kind) (*consent.Record, error) {
+	record, err := records.FindLatest(ctx, userID, kind)
+	if errors.Is(err, consent.ErrConsentNotFound) {
+		return nil, nil
+	}
+	return record, err
+}
+
+// isAccepted tells whether the latest record is a granted acceptance of this exact document version.
+func isAccepted(record *consent.Record, document *consent.Document) bool {
+	return record != nil && record.Granted && record.Version == document.Version
+}
@@ -0,0 +1,67 @@
+package consentUseCase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"jamlink-backend/internal/modules/consent/domain/consent"
+)
+
+type DocumentStatus struct {
+	Type            consent.DocumentType `json:"type"`
+	ActiveVersion   string               `json:"activeVersion"`
+	URL             string               `json:"url"`
+	AcceptedVersion string               `json:"acceptedVersion,omitempty"`
+	AcceptedAt      *time.Time           `json:"acceptedAt,omitempty"`
+}
+
+type ConsentStatusOutput struct {
+	Documents        []DocumentStatus `json:"documents"`
+	MarketingOptIn   bool             `json:"marketingOptIn"`
+	ReacceptRequired bool             `json:"reacceptRequired"`
+}
+
+type GetConsentStatusUseCase struct {
+	docs    consent.DocumentRepository
+	records consent.RecordRepository
+}
+
+func NewGetConsentStatusUseCase(docs consent.DocumentRepository, records consent.RecordRepository) *GetConsentStatusUseCase {
+	return &GetConsentStatusUseCase{docs: docs, records: records}
+}
+
+func (uc *GetConsentStatusUseCase) Execute(ctx context.Context, userID uuid.UUID) (*ConsentStatusOutput, error) {
+	active, err := activeRequiredDocuments(ctx, uc.docs, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ConsentStatusOutput{Documents: make([]DocumentStatus, 0, len(active))}
+
+	for _, document := range active {
+		record, err := latestConsent(ctx, uc.records, userID, consent.Kind(document.Type))
+		if err != nil {
+			return nil, err
+		}
+
+		status := DocumentStatus{Type: document.Type, ActiveVersion: document.Version, URL: document.URL}
+		if record != nil && record.Granted {
+			status.AcceptedVersion = record.Version
+			status.AcceptedAt = &record.RecordedAt
+		}
+		if !isAccepted(record, document) {
+			output.ReacceptRequired = true
+		}
+
+		output.Documents = append(output.Documents, status)
+	}
+
+	marketing, err := latestConsent(ctx, uc.records, userID, consent.KindMarketing)
+	if err != nil {
+		return nil, err
+	}
+	output.MarketingOptIn = marketing != nil && marketing.Granted
+
+	return output, nil
+}
@@ -0,0 +1,20 @@
+package consentUseCase
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"jamlink-backend/internal/modules/consent/domain/consent"
+)
+
+type ListConsentHistoryUseCase struct {
+	records consent.RecordRepository
+}
+
+func NewListConsentHistoryUseCase(records consent.RecordRepository) *ListConsentHistoryUseCase {
+	return &ListConsentHistoryUseCase{records: records}
+}
+
+// Execute returns every consent record of the user, most recent first.
+func (uc *ListConsentHistoryUseCase) Execute(ctx context.Context, userID uuid.UUID) ([]consent.Record, error) {
+	return uc.records.FindByUserID(ctx, userID)
+}
@@ -0,0 +1,21 @@
+package consentUseCase
+
+import (
+	"context"
+	"time"
+
+	"jamlink-backend/internal/modules/consent/domain/consent"
+)
+
+type ListDocumentsUseCase struct {
+	docs consent.DocumentRepository
+}
+
+func NewListDocumentsUseCase(docs consent.DocumentRepository) *ListDocumentsUseCase {
+	return &ListDocumentsUseCase{docs: docs}
+}
+
+// Execute returns the versions in effect now, which the sign-up form must display and send back.
+func (uc *ListDocumentsUseCase) Execute(ctx context.Context) ([]*consent.Document, error) {
+	return activeRequiredDocuments(ctx, uc.docs, time.Now())
+}
@@ -0,0 +1,58 @@
+package consentUseCase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/consent/domain/consent"
+)
+
+type PublishDocumentInput struct {
+	PublishedBy uuid.UUID            `json:"-"`
+	Type        consent.DocumentType `json:"type" binding:"required" example:"terms"`
+	Version     string               `json:"version" binding:"required" example:"2025-01"`
+	URL         string               `json:"url" binding:"required,url" example:"https://jamlink.app/legal/terms-2025-01"`
+	// EffectiveAt defaults to now. Users have to accept the new version once it is in effect.
+	EffectiveAt time.Time `json:"effectiveAt"`
+}
+
+type PublishDocumentUseCase struct {
+	userRepo userDomain.UserRepository
+	docs     consent.DocumentRepository
+}
+
+func NewPublishDocumentUseCase(userRepo userDomain.UserRepository, docs consent.DocumentRepository) *PublishDocumentUseCase {
+	return &PublishDocumentUseCase{userRepo: userRepo, docs: docs}
+}
+
+func (uc *PublishDocumentUseCase) Execute(ctx context.Context, input PublishDocumentInput) (*consent.Document, error) {
+	publisher, err := uc.userRepo.FindByID(ctx, input.PublishedBy)
+	if err != nil {
+		return nil, err
+	}
+	if !publisher.IsAdmin() {
+		return nil, consent.ErrPublishNotAuthorized
+	}
+
+	document, err := consent.CreateDocument(input.Type, input.Version, input.URL, input.EffectiveAt)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = uc.docs.FindByVersion(ctx, document.Type, document.Version)
+	switch {
+	case err == nil:
+		return nil, consent.ErrDocumentExists
+	case !errors.Is(err, consent.ErrDocumentNotFound):
+		return nil, err
+	}
+
+	if err := uc.docs.Create(ctx, document); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
@@ -0,0 +1,60 @@
+package consentUseCase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"jamlink-backend/internal/modules/consent/domain/consent"
+)
+
+// RegistrationConsents checks and records the consents given on the sign-up form.
+type RegistrationConsents struct {
+	docs    consent.DocumentRepository
+	records consent.RecordRepository
+}
+
+func NewRegistrationConsents(docs consent.DocumentRepository, records consent.RecordRepository) *RegistrationConsents {
+	return &RegistrationConsents{docs: docs, records: records}
+}
+
+// Check requires the active version of every published required document.
+func (r *RegistrationConsents) Check(ctx context.Context, termsVersion, privacyVersion string) error {
+	_, err := r.acceptances(ctx, uuid.Nil, termsVersion, privacyVersion, consent.Source{})
+	return err
+}
+
+// Record stores the acceptances and the marketing choice, including an explicit refusal.
+func (r *RegistrationConsents) Record(ctx context.Context, userID uuid.UUID, termsVersion, privacyVersion string, marketingOptIn bool, ip, userAgent string) error {
+	source := consent.Source{IP: ip, UserAgent: userAgent}
+
+	records, err := r.acceptances(ctx, userID, termsVersion, privacyVersion, source)
+	if err != nil {
+		return err
+	}
+
+	records = append(records, consent.CreateMarketingConsent(userID, marketingOptIn, source))
+
+	return r.records.Create(ctx, records...)
+}
+
+func (r *RegistrationConsents) acceptances(ctx context.Context, userID uuid.UUID, termsVersion, privacyVersion string, source consent.Source) ([]*consent.Record, error) {
+	active, err := activeRequiredDocuments(ctx, r.docs, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*consent.Record, 0, len(active)+1)
+	for _, document := range active {
+		version := versionFor(document.Type, termsVersion, privacyVersion)
+		if version == "" {
+			return nil, consent.ErrTermsNotAccepted
+		}
+		if version != document.Version {
+			return nil, consent.ErrOutdatedVersion
+		}
+		records = append(records, consent.CreateAcceptance(userID, document, source))
+	}
+
+	return records, nil
+}
@@ -0,0 +1,39 @@
+package consentUseCase
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/consent/domain/consent"
+	"jamlink-backend/internal/modules/consent/mocks"
+)
+
+func TestRegistrationConsents_Check(t *testing.T) {
+	docs := new(mocks.MockDocumentRepository)
+	activeDocuments(docs, "2025-01", "2024-06")
+
+	consents := NewRegistrationConsents(docs, new(mocks.MockRecordRepository))
+
+	assert.NoError(t, consents.Check(t.Context(), "2025-01", "2024-06"))
+	assert.ErrorIs(t, consents.Check(t.Context(), "", "2024-06"), consent.ErrTermsNotAccepted)
+	assert.ErrorIs(t, consents.Check(t.Context(), "2024-01", "2024-06"), consent.ErrOutdatedVersion)
+}
+
+func TestRegistrationConsents_RecordsMarketingRefusal(t *testing.T) {
+	docs := new(mocks.MockDocumentRepository)
+	records := new(mocks.MockRecordRepository)
+	userID := uuid.New()
+
+	activeDocuments(docs, "2025-01", "2024-06")
+	records.On("Create", mock.MatchedBy(func(created []*consent.Record) bool {
+		marketing := created[len(created)-1]
+		return len(created) == 3 && marketing.Kind == consent.KindMarketing && !marketing.Granted && marketing.UserAgent == "Mozilla/5.0"
+	})).Return(nil)
+
+	err := NewRegistrationConsents(docs, records).Record(t.Context(), userID, "2025-01", "2024-06", false, "203.0.113.7", "Mozilla/5.0")
+
+	assert.NoError(t, err)
+	records.AssertExpectations(t)
+}
@@ -0,0 +1,35 @@
+package consentUseCase
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"jamlink-backend/internal/modules/consent/domain/consent"
+)
+
+type SetMarketingConsentInput struct {
+	UserID uuid.UUID      `json:"-"`
+	OptIn  *bool          `json:"optIn" binding:"required" example:"true"`
+	Source consent.Source `json:"-"`
+}
+
+type SetMarketingConsentUseCase struct {
+	records consent.RecordRepository
+}
+
+func NewSetMarketingConsentUseCase(records consent.RecordRepository) *SetMarketingConsentUseCase {
+	return &SetMarketingConsentUseCase{records: records}
+}
+
+// Execute appends a record only when the choice changes, so the history reads as a list of decisions.
+func (uc *SetMarketingConsentUseCase) Execute(ctx context.Context, input SetMarketingConsentInput) error {
+	latest, err := latestConsent(ctx, uc.records, input.UserID, consent.KindMarketing)
+	if err != nil {
+		return err
+	}
+
+	if latest != nil && latest.Granted == *input.OptIn {
+		return nil
+	}
+
+	return uc.records.Create(ctx, consent.CreateMarketingConsent(input.UserID, *input.OptIn, input.Source))
+}
@@ -0,0 +1,40 @@
+package consentUseCase
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/consent/domain/consent"
+	"jamlink-backend/internal/modules/consent/mocks"
+)
+
+func TestSetMarketingConsent_Withdrawal(t *testing.T) {
+	records := new(mocks.MockRecordRepository)
+	userID := uuid.New()
+	optIn := false
+
+	records.On("FindLatest", userID, consent.KindMarketing).Return(&consent.Record{Kind: consent.KindMarketing, Granted: true}, nil)
+	records.On("Create", mock.MatchedBy(func(created []*consent.Record) bool {
+		return len(created) == 1 && created[0].Kind == consent.KindMarketing && !created[0].Granted
+	})).Return(nil)
+
+	err := NewSetMarketingConsentUseCase(records).Execute(t.Context(), SetMarketingConsentInput{UserID: userID, OptIn: &optIn})
+
+	assert.NoError(t, err)
+	records.AssertExpectations(t)
+}
+
+func TestSetMarketingConsent_Unchanged(t *testing.T) {
+	records := new(mocks.MockRecordRepository)
+	userID := uuid.New()
+	optIn := true
+
+	records.On("FindLatest", userID, consent.KindMarketing).Return(&consent.Record{Kind: consent.KindMarketing, Granted: true}, nil)
+
+	err := NewSetMarketingConsentUseCase(records).Execute(t.Context(), SetMarketingConsentInput{UserID: userID, OptIn: &optIn})
+
+	assert.NoError(t, err)
+	records.AssertNotCalled(t, "Create", mock.Anything)
+}
@@ -0,0 +1,20 @@
+package invitation
+
+import (
+	"errors"
+
+	"jamlink-backend/internal/shared/apperror"
+)
+
+var (
+	ErrInvitationNotFound      = apperror.NotFound("invitation_not_found", "invitation not found")
+	ErrInvitationExpired       = apperror.BadRequest("invitation_expired", "invitation expired")
+	ErrInvitationExhausted     = apperror.BadRequest("invitation_exhausted", "invitation has no uses left")
+	ErrInvitationRequired      = apperror.Forbidden("invitation_required", "an invitation code is required to register")
+	ErrRegistrationClosed      = apperror.Forbidden("registration_closed", "registrations are closed")
+	ErrInvalidMaxUses          = apperror.BadRequest("invalid_invitation_max_uses", "invalid number of uses")
+	ErrInvalidExpiry           = apperror.BadRequest("invalid_invitation_expiry", "invalid invitation expiry")
+	ErrInvitationQuotaReached  = apperror.Forbidden("invitation_quota_reached", "too many active invitations")
+	ErrNotInvitationOwner      = apperror.Forbidden("not_invitation_owner", "invitation belongs to another user")
+	ErrInvalidRegistrationMode = errors.New("invalid registration mode")
+)
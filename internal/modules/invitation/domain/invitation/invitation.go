@@ -0,0 +1,70 @@
+package invitation
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Invitation struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Code      string     `gorm:"type:varchar(32);not null;uniqueIndex" json:"code"`
+	CreatedBy uuid.UUID  `gorm:"type:uuid;not null;index" json:"-"`
+	MaxUses   int        `gorm:"not null" json:"maxUses"`
+	Uses      int        `gorm:"not null;default:0" json:"uses"`
+	ExpiresAt *time.Time `gorm:"default:null" json:"expiresAt"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+// Redemption records which invitation, and therefore which user, brought a new user in.
+type Redemption struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	InvitationID uuid.UUID `gorm:"type:uuid;not null;index"`
+	InviterID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	InviteeID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
+	RedeemedAt   time.Time `gorm:"not null"`
+}
+
+func (Redemption) TableName() string {
+	return "invitation_redemptions"
+}
+
+// CreateInvitation builds an invitation usable maxUses times; a nil expiresAt never expires.
+func CreateInvitation(createdBy uuid.UUID, code string, maxUses int, expiresAt *time.Time) (*Invitation, error) {
+	if maxUses < 1 {
+		return nil, ErrInvalidMaxUses
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, ErrInvalidExpiry
+	}
+
+	return &Invitation{
+		ID:        uuid.New(),
+		Code:      code,
+		CreatedBy: createdBy,
+		MaxUses:   maxUses,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// Usable reports why the invitation cannot be redeemed at the given time, if it cannot.
+func (i *Invitation) Usable(at time.Time) error {
+	if i.ExpiresAt != nil && !i.ExpiresAt.After(at) {
+		return ErrInvitationExpired
+	}
+	if i.Uses >= i.MaxUses {
+		return ErrInvitationExhausted
+	}
+	return nil
+}
+
+func CreateRedemption(invitation *Invitation, inviteeID uuid.UUID) *Redemption {
+	return &Redemption{
+		ID:           uuid.New(),
+		InvitationID: invitation.ID,
+		InviterID:    invitation.CreatedBy,
+		InviteeID:    inviteeID,
+		RedeemedAt:   time.Now(),
+	}
+}
@@ -0,0 +1,18 @@
+package invitation
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *Invitation) error
+	FindByCode(ctx context.Context, code string) (*Invitation, error)
+	FindByCreator(ctx context.Context, userID uuid.UUID) ([]Invitation, error)
+	CountActiveByCreator(ctx context.Context, userID uuid.UUID) (int64, error)
+	// Redeem consumes one use of the invitation and records the redemption. It returns
+	// ErrInvitationExhausted or ErrInvitationExpired when the invitation can no longer be used.
+	Redeem(ctx context.Context, invitation *Invitation, inviteeID uuid.UUID) error
+	FindRedemptionsByInviter(ctx context.Context, userID uuid.UUID) ([]Redemption, error)
+}
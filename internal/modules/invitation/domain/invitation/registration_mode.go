@@ -0,0 +1,23 @@
+package invitation
+
+import "strings"
+
+type RegistrationMode string
+
+const (
+	RegistrationOpen       RegistrationMode = "open"
+	RegistrationInviteOnly RegistrationMode = "invite-only"
+	RegistrationClosed     RegistrationMode = "closed"
+)
+
+// ParseRegistrationMode defaults to open when the setting is empty.
+func ParseRegistrationMode(value string) (RegistrationMode, error) {
+	switch mode := RegistrationMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "":
+		return RegistrationOpen, nil
+	case RegistrationOpen, RegistrationInviteOnly, RegistrationClosed:
+		return mode, nil
+	default:
+		return "", ErrInvalidRegistrationMode
+	}
+}
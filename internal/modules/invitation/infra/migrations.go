@@ -0,0 +1,17 @@
+package invitationinfra
+
+import (
+	"embed"
+	"io/fs"
+
+	"jamlink-backend/internal/infra/db/migrate"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrations returns the SQL migrations of the invitation module.
+func Migrations() migrate.Source {
+	files, _ := fs.Sub(migrations, "migrations")
+	return migrate.Source{Module: "invitation", Dir: "internal/modules/invitation/infra/migrations", Files: files}
+}
@@ -0,0 +1,26 @@
+-- Baseline of a schema previously created by gorm's AutoMigrate: IF NOT EXISTS lets databases
+-- created that way adopt it.
+CREATE TABLE IF NOT EXISTS invitations (
+	id uuid DEFAULT gen_random_uuid(),
+	code varchar(32) NOT NULL,
+	created_by uuid NOT NULL,
+	max_uses bigint NOT NULL,
+	uses bigint NOT NULL DEFAULT 0,
+	expires_at timestamptz DEFAULT null,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_invitations_code ON invitations (code);
+CREATE INDEX IF NOT EXISTS idx_invitations_created_by ON invitations (created_by);
+
+CREATE TABLE IF NOT EXISTS invitation_redemptions (
+	id uuid DEFAULT gen_random_uuid(),
+	invitation_id uuid NOT NULL,
+	inviter_id uuid NOT NULL,
+	invitee_id uuid NOT NULL,
+	redeemed_at timestamptz NOT NULL,
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_invitation_redemptions_invitee_id ON invitation_redemptions (invitee_id);
+CREATE INDEX IF NOT EXISTS idx_invitation_redemptions_inviter_id ON invitation_redemptions (inviter_id);
+CREATE INDEX IF NOT EXISTS idx_invitation_redemptions_invitation_id ON invitation_redemptions (invitation_id);
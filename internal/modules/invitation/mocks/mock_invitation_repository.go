@@ -0,0 +1,47 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/invitation/domain/invitation"
+)
+
+type MockInvitationRepository struct {
+	mock.Mock
+}
+
+func (m *MockInvitationRepository) Create(_ context.Context, inv *invitation.Invitation) error {
+	args := m.Called(inv)
+	return args.Error(0)
+}
+
+func (m *MockInvitationRepository) FindByCode(_ context.Context, code string) (*invitation.Invitation, error) {
+	args := m.Called(code)
+	inv := args.Get(0)
+	if inv == nil {
+		return nil, args.Error(1)
+	}
+	return inv.(*invitation.Invitation), args.Error(1)
+}
+
+func (m *MockInvitationRepository) FindByCreator(_ context.Context, userID uuid.UUID) ([]invitation.Invitation, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]invitation.Invitation), args.Error(1)
+}
+
+func (m *MockInvitationRepository) CountActiveByCreator(_ context.Context, userID uuid.UUID) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockInvitationRepository) Redeem(_ context.Context, inv *invitation.Invitation, inviteeID uuid.UUID) error {
+	args := m.Called(inv, inviteeID)
+	return args.Error(0)
+}
+
+func (m *MockInvitationRepository) FindRedemptionsByInviter(_ context.Context, userID uuid.UUID) ([]invitation.Redemption, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]invitation.Redemption), args.Error(1)
+}
@@ -0,0 +1,84 @@
+package invitationRepository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"jamlink-backend/internal/modules/invitation/domain/invitation"
+)
+
+type PostgresInvitationRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresInvitationRepository(db *gorm.DB) *PostgresInvitationRepository {
+	return &PostgresInvitationRepository{db: db}
+}
+
+func (r *PostgresInvitationRepository) Create(ctx context.Context, inv *invitation.Invitation) error {
+	return r.db.WithContext(ctx).Create(inv).Error
+}
+
+func (r *PostgresInvitationRepository) FindByCode(ctx context.Context, code string) (*invitation.Invitation, error) {
+	var inv invitation.Invitation
+
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&inv).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, invitation.ErrInvitationNotFound
+		}
+		return nil, err
+	}
+
+	return &inv, nil
+}
+
+func (r *PostgresInvitationRepository) FindByCreator(ctx context.Context, userID uuid.UUID) ([]invitation.Invitation, error) {
+	var invitations []invitation.Invitation
+
+	err := r.db.WithContext(ctx).Where("created_by = ?", userID).Order("created_at DESC").Find(&invitations).Error
+
+	return invitations, err
+}
+
+func (r *PostgresInvitationRepository) CountActiveByCreator(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).Model(&invitation.Invitation{}).
+		Where("created_by = ? AND uses < max_uses AND (expires_at IS NULL OR expires_at > ?)", userID, time.Now()).
+		Count(&count).Error
+
+	return count, err
+}
+
+// Redeem increments the use counter with a conditional update, so two registrations racing for the
+// last use of a code cannot both succeed.
+func (r *PostgresInvitationRepository) Redeem(ctx context.Context, inv *invitation.Invitation, inviteeID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&invitation.Invitation{}).
+			Where("id = ? AND uses < max_uses AND (expires_at IS NULL OR expires_at > ?)", inv.ID, time.Now()).
+			UpdateColumn("uses", gorm.Expr("uses + 1"))
+
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			if inv.ExpiresAt != nil && !inv.ExpiresAt.After(time.Now()) {
+				return invitation.ErrInvitationExpired
+			}
+			return invitation.ErrInvitationExhausted
+		}
+
+		return tx.Create(invitation.CreateRedemption(inv, inviteeID)).Error
+	})
+}
+
+func (r *PostgresInvitationRepository) FindRedemptionsByInviter(ctx context.Context, userID uuid.UUID) ([]invitation.Redemption, error) {
+	var redemptions []invitation.Redemption
+
+	err := r.db.WithContext(ctx).Where("inviter_id = ?", userID).Order("redeemed_at DESC").Find(&redemptions).Error
+
+	return redemptions, err
+}
@@ -0,0 +1,99 @@
+package invitationUseCase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/invitation/domain/invitation"
+	"jamlink-backend/internal/shared/security"
+)
+
+// Regular users get a small referral budget; admins are only bound by what they ask for.
+const (
+	userMaxUsesPerInvitation = 5
+	userMaxActiveInvitations = 3
+	userDefaultExpiry        = 7 * 24 * time.Hour
+	userMaxExpiry            = 30 * 24 * time.Hour
+
+	// invitationCodeBytes gives 12 URL-safe characters once base64 encoded.
+	invitationCodeBytes = 9
+)
+
+type CreateInvitationInput struct {
+	CreatedBy      uuid.UUID `json:"-"`
+	MaxUses        int       `json:"maxUses" example:"1"`
+	ExpiresInHours int       `json:"expiresInHours" example:"168"`
+}
+
+type CreateInvitationUseCase struct {
+	userRepo       userDomain.UserRepository
+	invitationRepo invitation.InvitationRepository
+	security       security.SecurityService
+}
+
+func NewCreateInvitationUseCase(userRepo userDomain.UserRepository, invitationRepo invitation.InvitationRepository, security security.SecurityService) *CreateInvitationUseCase {
+	return &CreateInvitationUseCase{userRepo: userRepo, invitationRepo: invitationRepo, security: security}
+}
+
+// Execute defaults to a single use. Invitations of regular users always expire, admins may omit the
+// expiry to create a code that stays valid until its uses run out.
+func (uc *CreateInvitationUseCase) Execute(ctx context.Context, input CreateInvitationInput) (*invitation.Invitation, error) {
+	creator, err := uc.userRepo.FindByID(ctx, input.CreatedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	maxUses := input.MaxUses
+	if maxUses == 0 {
+		maxUses = 1
+	}
+
+	expiresIn := time.Duration(input.ExpiresInHours) * time.Hour
+	if expiresIn < 0 {
+		return nil, invitation.ErrInvalidExpiry
+	}
+
+	if !creator.IsAdmin() {
+		if maxUses > userMaxUsesPerInvitation {
+			return nil, invitation.ErrInvalidMaxUses
+		}
+		if expiresIn == 0 {
+			expiresIn = userDefaultExpiry
+		}
+		if expiresIn > userMaxExpiry {
+			return nil, invitation.ErrInvalidExpiry
+		}
+
+		active, err := uc.invitationRepo.CountActiveByCreator(ctx, creator.ID)
+		if err != nil {
+			return nil, err
+		}
+		if active >= userMaxActiveInvitations {
+			return nil, invitation.ErrInvitationQuotaReached.With("max", userMaxActiveInvitations)
+		}
+	}
+
+	var expiresAt *time.Time
+	if expiresIn > 0 {
+		at := time.Now().Add(expiresIn)
+		expiresAt = &at
+	}
+
+	code, err := uc.security.GenerateSecureRandomString(invitationCodeBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	inv, err := invitation.CreateInvitation(creator.ID, code, maxUses, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.invitationRepo.Create(ctx, inv); err != nil {
+		return nil, err
+	}
+
+	return inv, nil
+}
@@ -0,0 +1,98 @@
+package invitationUseCase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	authMocks "jamlink-backend/internal/modules/auth/mocks"
+	"jamlink-backend/internal/modules/invitation/domain/invitation"
+	"jamlink-backend/internal/modules/invitation/mocks"
+)
+
+func TestCreateInvitation_UserDefaults(t *testing.T) {
+	mockUserRepo := new(authMocks.MockUserRepository)
+	mockInvitationRepo := new(mocks.MockInvitationRepository)
+	mockSecurity := new(authMocks.MockSecurityService)
+
+	userID := uuid.New()
+	mockUserRepo.On("FindByID", userID).Return(&userDomain.User{ID: userID, Role: userDomain.RoleUser}, nil)
+	mockInvitationRepo.On("CountActiveByCreator", userID).Return(int64(0), nil)
+	mockSecurity.On("GenerateSecureRandomString", invitationCodeBytes).Return("k3J9xQ2mZ7aB", nil)
+	mockInvitationRepo.On("Create", mock.AnythingOfType("*invitation.Invitation")).Return(nil)
+
+	useCase := NewCreateInvitationUseCase(mockUserRepo, mockInvitationRepo, mockSecurity)
+
+	inv, err := useCase.Execute(t.Context(), CreateInvitationInput{CreatedBy: userID})
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, inv) {
+		assert.Equal(t, "k3J9xQ2mZ7aB", inv.Code)
+		assert.Equal(t, 1, inv.MaxUses)
+		assert.Equal(t, userID, inv.CreatedBy)
+		if assert.NotNil(t, inv.ExpiresAt) {
+			assert.WithinDuration(t, time.Now().Add(userDefaultExpiry), *inv.ExpiresAt, time.Minute)
+		}
+	}
+	mockInvitationRepo.AssertExpectations(t)
+}
+
+func TestCreateInvitation_UserLimits(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name        string
+		input       CreateInvitationInput
+		active      int64
+		expectedErr error
+	}{
+		{"too many uses", CreateInvitationInput{CreatedBy: userID, MaxUses: userMaxUsesPerInvitation + 1}, 0, invitation.ErrInvalidMaxUses},
+		{"expiry too far", CreateInvitationInput{CreatedBy: userID, ExpiresInHours: 24 * 31}, 0, invitation.ErrInvalidExpiry},
+		{"negative expiry", CreateInvitationInput{CreatedBy: userID, ExpiresInHours: -1}, 0, invitation.ErrInvalidExpiry},
+		{"quota reached", CreateInvitationInput{CreatedBy: userID}, userMaxActiveInvitations, invitation.ErrInvitationQuotaReached},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := new(authMocks.MockUserRepository)
+			mockInvitationRepo := new(mocks.MockInvitationRepository)
+			mockSecurity := new(authMocks.MockSecurityService)
+
+			mockUserRepo.On("FindByID", userID).Return(&userDomain.User{ID: userID, Role: userDomain.RoleUser}, nil)
+			mockInvitationRepo.On("CountActiveByCreator", userID).Return(tt.active, nil)
+
+			useCase := NewCreateInvitationUseCase(mockUserRepo, mockInvitationRepo, mockSecurity)
+
+			inv, err := useCase.Execute(t.Context(), tt.input)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Nil(t, inv)
+			mockInvitationRepo.AssertNotCalled(t, "Create", mock.Anything)
+		})
+	}
+}
+
+func TestCreateInvitation_AdminWithoutExpiry(t *testing.T) {
+	mockUserRepo := new(authMocks.MockUserRepository)
+	mockInvitationRepo := new(mocks.MockInvitationRepository)
+	mockSecurity := new(authMocks.MockSecurityService)
+
+	adminID := uuid.New()
+	mockUserRepo.On("FindByID", adminID).Return(&userDomain.User{ID: adminID, Role: userDomain.RoleAdmin}, nil)
+	mockSecurity.On("GenerateSecureRandomString", invitationCodeBytes).Return("betaTesters1", nil)
+	mockInvitationRepo.On("Create", mock.AnythingOfType("*invitation.Invitation")).Return(nil)
+
+	useCase := NewCreateInvitationUseCase(mockUserRepo, mockInvitationRepo, mockSecurity)
+
+	inv, err := useCase.Execute(t.Context(), CreateInvitationInput{CreatedBy: adminID, MaxUses: 500})
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, inv) {
+		assert.Equal(t, 500, inv.MaxUses)
+		assert.Nil(t, inv.ExpiresAt)
+	}
+	mockInvitationRepo.AssertNotCalled(t, "CountActiveByCreator", mock.Anything)
+}
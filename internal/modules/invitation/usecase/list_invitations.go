@@ -0,0 +1,55 @@
+package invitationUseCase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"jamlink-backend/internal/modules/invitation/domain/invitation"
+)
+
+type ReferralOutput struct {
+	InvitationID uuid.UUID `json:"invitationId"`
+	UserID       uuid.UUID `json:"userId"`
+	RedeemedAt   time.Time `json:"redeemedAt"`
+}
+
+type ListInvitationsOutput struct {
+	Invitations []invitation.Invitation `json:"invitations"`
+	Referrals   []ReferralOutput        `json:"referrals"`
+}
+
+type ListInvitationsUseCase struct {
+	invitationRepo invitation.InvitationRepository
+}
+
+func NewListInvitationsUseCase(invitationRepo invitation.InvitationRepository) *ListInvitationsUseCase {
+	return &ListInvitationsUseCase{invitationRepo: invitationRepo}
+}
+
+// Execute returns the invitations a user created and the users who registered with them.
+func (uc *ListInvitationsUseCase) Execute(ctx context.Context, userID uuid.UUID) (*ListInvitationsOutput, error) {
+	invitations, err := uc.invitationRepo.FindByCreator(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	redemptions, err := uc.invitationRepo.FindRedemptionsByInviter(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ListInvitationsOutput{
+		Invitations: invitations,
+		Referrals:   make([]ReferralOutput, 0, len(redemptions)),
+	}
+	for _, redemption := range redemptions {
+		output.Referrals = append(output.Referrals, ReferralOutput{
+			InvitationID: redemption.InvitationID,
+			UserID:       redemption.InviteeID,
+			RedeemedAt:   redemption.RedeemedAt,
+		})
+	}
+
+	return output, nil
+}
@@ -0,0 +1,59 @@
+package invitationUseCase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"jamlink-backend/internal/modules/invitation/domain/invitation"
+)
+
+// RegistrationGate applies the registration mode to sign-ups. In open mode a code is optional but
+// still checked and redeemed when given, so referrals are tracked.
+type RegistrationGate struct {
+	mode           invitation.RegistrationMode
+	invitationRepo invitation.InvitationRepository
+}
+
+func NewRegistrationGate(mode invitation.RegistrationMode, invitationRepo invitation.InvitationRepository) *RegistrationGate {
+	return &RegistrationGate{mode: mode, invitationRepo: invitationRepo}
+}
+
+// Check tells whether a sign-up with this code may proceed, without consuming the code.
+func (g *RegistrationGate) Check(ctx context.Context, code string) error {
+	_, err := g.usableInvitation(ctx, g.invitationRepo, code)
+	return err
+}
+
+// Redeem consumes the code for the new user with invitations, the repository of the transaction that
+// creates the user. It does nothing when no code is required nor given.
+func (g *RegistrationGate) Redeem(ctx context.Context, invitations invitation.InvitationRepository, code string, userID uuid.UUID) error {
+	inv, err := g.usableInvitation(ctx, invitations, code)
+	if err != nil || inv == nil {
+		return err
+	}
+
+	return invitations.Redeem(ctx, inv, userID)
+}
+
+func (g *RegistrationGate) usableInvitation(ctx context.Context, invitations invitation.InvitationRepository, code string) (*invitation.Invitation, error) {
+	switch {
+	case g.mode == invitation.RegistrationClosed:
+		return nil, invitation.ErrRegistrationClosed
+	case code == "" && g.mode == invitation.RegistrationInviteOnly:
+		return nil, invitation.ErrInvitationRequired
+	case code == "":
+		return nil, nil
+	}
+
+	inv, err := invitations.FindByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := inv.Usable(time.Now()); err != nil {
+		return nil, err
+	}
+
+	return inv, nil
+}
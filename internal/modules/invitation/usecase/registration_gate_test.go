@@ -0,0 +1,70 @@
+package invitationUseCase
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"jamlink-backend/internal/modules/invitation/domain/invitation"
+	"jamlink-backend/internal/modules/invitation/mocks"
+)
+
+func TestRegistrationGate_Check(t *testing.T) {
+	usable := &invitation.Invitation{Code: "usable", MaxUses: 2, Uses: 1}
+	exhausted := &invitation.Invitation{Code: "exhausted", MaxUses: 1, Uses: 1}
+
+	tests := []struct {
+		mode        invitation.RegistrationMode
+		code        string
+		expectedErr error
+	}{
+		{invitation.RegistrationOpen, "", nil},
+		{invitation.RegistrationOpen, "usable", nil},
+		{invitation.RegistrationOpen, "exhausted", invitation.ErrInvitationExhausted},
+		{invitation.RegistrationInviteOnly, "", invitation.ErrInvitationRequired},
+		{invitation.RegistrationInviteOnly, "usable", nil},
+		{invitation.RegistrationInviteOnly, "unknown", invitation.ErrInvitationNotFound},
+		{invitation.RegistrationClosed, "usable", invitation.ErrRegistrationClosed},
+	}
+
+	for _, tt := range tests {
+		mockRepo := new(mocks.MockInvitationRepository)
+		mockRepo.On("FindByCode", "usable").Return(usable, nil)
+		mockRepo.On("FindByCode", "exhausted").Return(exhausted, nil)
+		mockRepo.On("FindByCode", "unknown").Return(nil, invitation.ErrInvitationNotFound)
+
+		err := NewRegistrationGate(tt.mode, mockRepo).Check(t.Context(), tt.code)
+
+		if tt.expectedErr == nil {
+			assert.NoError(t, err, "mode %s, code %q", tt.mode, tt.code)
+		} else {
+			assert.ErrorIs(t, err, tt.expectedErr, "mode %s, code %q", tt.mode, tt.code)
+		}
+	}
+}
+
+func TestRegistrationGate_RedeemsWithTheGivenRepository(t *testing.T) {
+	mockRepo := new(mocks.MockInvitationRepository)
+	txRepo := new(mocks.MockInvitationRepository)
+	inv := &invitation.Invitation{ID: uuid.New(), Code: "usable", MaxUses: 1}
+	userID := uuid.New()
+
+	txRepo.On("FindByCode", "usable").Return(inv, nil)
+	txRepo.On("Redeem", inv, userID).Return(nil)
+
+	gate := NewRegistrationGate(invitation.RegistrationInviteOnly, mockRepo)
+
+	assert.NoError(t, gate.Redeem(t.Context(), txRepo, "usable", userID))
+	txRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Redeem", mock.Anything, mock.Anything)
+}
+
+func TestRegistrationGate_RedeemWithoutCodeInOpenMode(t *testing.T) {
+	mockRepo := new(mocks.MockInvitationRepository)
+
+	gate := NewRegistrationGate(invitation.RegistrationOpen, mockRepo)
+
+	assert.NoError(t, gate.Redeem(t.Context(), mockRepo, "", uuid.New()))
+	mockRepo.AssertNotCalled(t, "Redeem", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,59 @@
+package invitationUseCase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	"jamlink-backend/internal/modules/invitation/domain/invitation"
+	"jamlink-backend/internal/shared/email"
+)
+
+type SendInvitationInput struct {
+	SenderID uuid.UUID `json:"-"`
+	Code     string    `json:"-"`
+	Email    string    `json:"email" binding:"required,email" example:"friend@example.com"`
+	Lang     string    `json:"-"`
+}
+
+type SendInvitationUseCase struct {
+	userRepo       userDomain.UserRepository
+	invitationRepo invitation.InvitationRepository
+	emailService   email.EmailService
+	inviteURL      string
+}
+
+func NewSendInvitationUseCase(userRepo userDomain.UserRepository, invitationRepo invitation.InvitationRepository, emailService email.EmailService, inviteURL string) *SendInvitationUseCase {
+	return &SendInvitationUseCase{userRepo: userRepo, invitationRepo: invitationRepo, emailService: emailService, inviteURL: inviteURL}
+}
+
+// Execute emails one of the sender's own invitations, in the language of the request since the
+// recipient has no account yet.
+func (uc *SendInvitationUseCase) Execute(ctx context.Context, input SendInvitationInput) error {
+	inv, err := uc.invitationRepo.FindByCode(ctx, input.Code)
+	if err != nil {
+		return err
+	}
+
+	if inv.CreatedBy != input.SenderID {
+		return invitation.ErrNotInvitationOwner
+	}
+
+	if err := inv.Usable(time.Now()); err != nil {
+		return err
+	}
+
+	sender, err := uc.userRepo.FindByID(ctx, input.SenderID)
+	if err != nil {
+		return err
+	}
+
+	return uc.emailService.Send(ctx, input.Email, email.TemplateInvitation, input.Lang, map[string]string{
+		"INVITER": sender.Email,
+		"CODE":    inv.Code,
+		"URL":     fmt.Sprintf("%s?code=%s", uc.inviteURL, url.QueryEscape(inv.Code)),
+	})
+}
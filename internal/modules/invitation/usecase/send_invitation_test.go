@@ -0,0 +1,73 @@
+package invitationUseCase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	userDomain "jamlink-backend/internal/modules/auth/domain/user"
+	authMocks "jamlink-backend/internal/modules/auth/mocks"
+	"jamlink-backend/internal/modules/invitation/domain/invitation"
+	"jamlink-backend/internal/modules/invitation/mocks"
+	"jamlink-backend/internal/shared/email"
+)
+
+func TestSendInvitation_Success(t *testing.T) {
+	mockUserRepo := new(authMocks.MockUserRepository)
+	mockInvitationRepo := new(mocks.MockInvitationRepository)
+	mockEmail := new(authMocks.MockEmailService)
+
+	senderID := uuid.New()
+	inv := &invitation.Invitation{Code: "k3J9xQ2mZ7aB", CreatedBy: senderID, MaxUses: 1}
+
+	mockInvitationRepo.On("FindByCode", inv.Code).Return(inv, nil)
+	mockUserRepo.On("FindByID", senderID).Return(&userDomain.User{ID: senderID, Email: "sender@example.com"}, nil)
+	mockEmail.On("Send", "friend@example.com", email.TemplateInvitation, "fr-FR", map[string]string{
+		"INVITER": "sender@example.com",
+		"CODE":    inv.Code,
+		"URL":     "https://example.com/join?code=k3J9xQ2mZ7aB",
+	}).Return(nil)
+
+	useCase := NewSendInvitationUseCase(mockUserRepo, mockInvitationRepo, mockEmail, "https://example.com/join")
+
+	err := useCase.Execute(t.Context(), SendInvitationInput{SenderID: senderID, Code: inv.Code, Email: "friend@example.com", Lang: "fr-FR"})
+
+	assert.NoError(t, err)
+	mockEmail.AssertExpectations(t)
+}
+
+func TestSendInvitation_NotOwner(t *testing.T) {
+	mockUserRepo := new(authMocks.MockUserRepository)
+	mockInvitationRepo := new(mocks.MockInvitationRepository)
+	mockEmail := new(authMocks.MockEmailService)
+
+	inv := &invitation.Invitation{Code: "k3J9xQ2mZ7aB", CreatedBy: uuid.New(), MaxUses: 1}
+	mockInvitationRepo.On("FindByCode", inv.Code).Return(inv, nil)
+
+	useCase := NewSendInvitationUseCase(mockUserRepo, mockInvitationRepo, mockEmail, "https://example.com/join")
+
+	err := useCase.Execute(t.Context(), SendInvitationInput{SenderID: uuid.New(), Code: inv.Code, Email: "friend@example.com"})
+
+	assert.ErrorIs(t, err, invitation.ErrNotInvitationOwner)
+	mockEmail.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSendInvitation_Expired(t *testing.T) {
+	mockUserRepo := new(authMocks.MockUserRepository)
+	mockInvitationRepo := new(mocks.MockInvitationRepository)
+	mockEmail := new(authMocks.MockEmailService)
+
+	senderID := uuid.New()
+	expiredAt := time.Now().Add(-time.Hour)
+	inv := &invitation.Invitation{Code: "k3J9xQ2mZ7aB", CreatedBy: senderID, MaxUses: 1, ExpiresAt: &expiredAt}
+	mockInvitationRepo.On("FindByCode", inv.Code).Return(inv, nil)
+
+	useCase := NewSendInvitationUseCase(mockUserRepo, mockInvitationRepo, mockEmail, "https://example.com/join")
+
+	err := useCase.Execute(t.Context(), SendInvitationInput{SenderID: senderID, Code: inv.Code, Email: "friend@example.com"})
+
+	assert.ErrorIs(t, err, invitation.ErrInvitationExpired)
+	mockEmail.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
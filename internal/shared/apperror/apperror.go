@@ -0,0 +1,110 @@
+// Package apperror is the catalogue of the errors reported to API clients. Each error has a stable
+// code that clients can switch on, the HTTP status it is answered with, a message that is safe to
+// show, and params carrying the values a client needs to write its own message, such as the minimum
+// length of a password. The domain packages define their errors with the constructors of this
+// package; any other error is reported as ErrInternal, so that the messages of the database or of a
+// library never reach a client.
+package apperror
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+)
+
+// Error is an error of the catalogue. Errors are compared by code, so an error given params with
+// With still matches its definition with errors.Is.
+type Error struct {
+	Code    string
+	Status  int
+	Message string
+	Params  map[string]any
+}
+
+var catalogue = map[string]*Error{}
+
+// Errors that do not belong to a domain.
+var (
+	ErrInternal         = define(http.StatusInternalServerError, "internal_error", "an unexpected error occurred")
+	ErrInvalidRequest   = define(http.StatusBadRequest, "invalid_request", "the request body is invalid")
+	ErrRequestTimeout   = define(http.StatusServiceUnavailable, "request_timeout", "the request took too long")
+	ErrRequestCancelled = define(http.StatusServiceUnavailable, "request_cancelled", "the request was cancelled")
+)
+
+// define registers an error in the catalogue. Codes are part of the API, so defining one twice is a
+// programming error.
+func define(status int, code, message string) *Error {
+	if _, exists := catalogue[code]; exists {
+		panic("apperror: duplicate code " + code)
+	}
+
+	err := &Error{Code: code, Status: status, Message: message}
+	catalogue[code] = err
+	return err
+}
+
+func BadRequest(code, message string) *Error {
+	return define(http.StatusBadRequest, code, message)
+}
+
+func Unauthorized(code, message string) *Error {
+	return define(http.StatusUnauthorized, code, message)
+}
+
+func Forbidden(code, message string) *Error {
+	return define(http.StatusForbidden, code, message)
+}
+
+func NotFound(code, message string) *Error {
+	return define(http.StatusNotFound, code, message)
+}
+
+func Conflict(code, message string) *Error {
+	return define(http.StatusConflict, code, message)
+}
+
+func TooManyRequests(code, message string) *Error {
+	return define(http.StatusTooManyRequests, code, message)
+}
+
+func ServiceUnavailable(code, message string) *Error {
+	return define(http.StatusServiceUnavailable, code, message)
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// With returns a copy of e with the param key set to value. Params are sent to clients: they must not
+// hold anything a client should not see, such as another user's email.
+func (e *Error) With(key string, value any) *Error {
+	params := maps.Clone(e.Params)
+	if params == nil {
+		params = map[string]any{}
+	}
+	params[key] = value
+
+	return &Error{Code: e.Code, Status: e.Status, Message: e.Message, Params: params}
+}
+
+// From returns the error of the catalogue found in the chain of err. The errors of a cancelled request
+// are reported as such; any other error is reported as ErrInternal.
+func From(err error) *Error {
+	var appErr *Error
+	switch {
+	case errors.As(err, &appErr):
+		return appErr
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrRequestTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrRequestCancelled
+	default:
+		return ErrInternal
+	}
+}
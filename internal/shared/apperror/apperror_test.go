@@ -0,0 +1,78 @@
+package apperror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTest = BadRequest("test_error", "test error")
+
+func TestWith_KeepsIdentity(t *testing.T) {
+	withParam := errTest.With("min", 8)
+
+	assert.ErrorIs(t, withParam, errTest)
+	assert.Equal(t, map[string]any{"min": 8}, withParam.Params)
+	assert.Nil(t, errTest.Params, "the definition must not be modified")
+}
+
+func TestDefine_PanicsOnDuplicateCode(t *testing.T) {
+	assert.Panics(t, func() { Conflict("test_error", "another test error") })
+}
+
+func TestFrom(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want *Error
+	}{
+		{"catalogue error", errTest, errTest},
+		{"wrapped catalogue error", fmt.Errorf("register: %w", errTest), errTest},
+		{"joined catalogue error", errors.Join(errTest, errors.New("cause")), errTest},
+		{"deadline exceeded", fmt.Errorf("query: %w", context.DeadlineExceeded), ErrRequestTimeout},
+		{"cancelled", context.Canceled, ErrRequestCancelled},
+		{"unknown error", errors.New("record not found"), ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Same(t, tt.want, From(tt.err))
+		})
+	}
+}
+
+func TestProblem(t *testing.T) {
+	problem := errTest.With("min", 8).Problem("/auth/register", "req-1")
+
+	body, err := json.Marshal(problem)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"type": "urn:jamlink:problem:test_error",
+		"title": "Bad Request",
+		"status": 400,
+		"detail": "test error",
+		"instance": "/auth/register",
+		"code": "test_error",
+		"params": {"min": 8},
+		"requestId": "req-1"
+	}`, string(body))
+}
+
+func TestProblem_OmitsEmptyFields(t *testing.T) {
+	body, err := json.Marshal(ErrInternal.Problem("", ""))
+	require.NoError(t, err)
+
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(body, &fields))
+	assert.NotContains(t, fields, "instance")
+	assert.NotContains(t, fields, "params")
+	assert.NotContains(t, fields, "requestId")
+	assert.EqualValues(t, http.StatusInternalServerError, fields["status"])
+}
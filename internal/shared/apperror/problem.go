@@ -0,0 +1,37 @@
+package apperror
+
+import "net/http"
+
+// ProblemContentType is the media type of Problem, see RFC 7807.
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypePrefix starts the type of every problem, followed by its code. The URN only names the
+// error: it is not meant to be dereferenced.
+const ProblemTypePrefix = "urn:jamlink:problem:"
+
+// Problem is the RFC 7807 body of an error response. Code and Params repeat the error of the
+// catalogue, for clients that switch on it rather than on the type URI.
+type Problem struct {
+	Type      string         `json:"type" example:"urn:jamlink:problem:email_already_exists"`
+	Title     string         `json:"title" example:"Conflict"`
+	Status    int            `json:"status" example:"409"`
+	Detail    string         `json:"detail" example:"email already exists"`
+	Instance  string         `json:"instance,omitempty" example:"/auth/register"`
+	Code      string         `json:"code" example:"email_already_exists"`
+	Params    map[string]any `json:"params,omitempty"`
+	RequestID string         `json:"requestId,omitempty"`
+}
+
+// Problem describes e as the answer to the request for instance, the path of the request.
+func (e *Error) Problem(instance, requestID string) Problem {
+	return Problem{
+		Type:      ProblemTypePrefix + e.Code,
+		Title:     http.StatusText(e.Status),
+		Status:    e.Status,
+		Detail:    e.Message,
+		Instance:  instance,
+		Code:      e.Code,
+		Params:    e.Params,
+		RequestID: requestID,
+	}
+}
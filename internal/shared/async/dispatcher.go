@@ -0,0 +1,112 @@
+package async
+
+import (
+	"context"
+	"fmt"
+	"jamlink-backend/internal/shared/apperror"
+	"jamlink-backend/internal/shared/logging"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var tracer = otel.Tracer("jamlink-backend/internal/shared/async")
+
+// ErrQueueFull is returned by Dispatch when the job cannot be queued, because the queue is full or the
+// pool is shutting down. The job is not run: the caller must fail its request rather than report it as
+// accepted.
+var ErrQueueFull = apperror.ServiceUnavailable("server_busy", "the server is busy, try again later")
+
+// Dispatcher runs work off the request path. Errors are logged since nobody is waiting for them, with
+// the logger of ctx so that they can be traced back to the request, and each job is a span of the
+// request's trace. The job outlives the request: the context it gets keeps the values of ctx, such as
+// the logger and the principal, but is not cancelled with it.
+type Dispatcher interface {
+	Dispatch(ctx context.Context, name string, job func(ctx context.Context) error) error
+}
+
+type queuedJob struct {
+	ctx  context.Context
+	name string
+	run  func(ctx context.Context) error
+}
+
+// WorkerPool runs jobs on a fixed number of workers from a bounded queue, so that a burst of requests
+// can neither start unbounded goroutines nor pile up jobs in memory: once the queue is full, Dispatch
+// sheds the job.
+type WorkerPool struct {
+	queue chan queuedJob
+	wg    sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func NewWorkerPool(workers, queueSize int) *WorkerPool {
+	p := &WorkerPool{queue: make(chan queuedJob, queueSize)}
+
+	p.wg.Add(workers)
+	for range workers {
+		go p.work()
+	}
+
+	return p
+}
+
+// Dispatch queues job without blocking, and returns ErrQueueFull when it cannot.
+func (p *WorkerPool) Dispatch(ctx context.Context, name string, job func(ctx context.Context) error) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrQueueFull
+	}
+
+	select {
+	case p.queue <- queuedJob{ctx: context.WithoutCancel(ctx), name: name, run: job}:
+		return nil
+	default:
+		logging.FromContext(ctx).Warn("background job shed, queue full", "job", name)
+		return ErrQueueFull
+	}
+}
+
+func (p *WorkerPool) work() {
+	defer p.wg.Done()
+
+	for job := range p.queue {
+		jobCtx, span := tracer.Start(job.ctx, job.name)
+
+		if err := job.run(jobCtx); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			logging.FromContext(job.ctx).Error("background job failed", "job", job.name, "error", err)
+		}
+		span.End()
+	}
+}
+
+// Shutdown stops accepting jobs and waits for the queued ones to finish. When ctx ends first, it
+// returns an error counting the jobs that were still queued: they will not run.
+func (p *WorkerPool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d background jobs not run: %w", len(p.queue), ctx.Err())
+	}
+}
@@ -0,0 +1,87 @@
+package async
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerPool_ShedsJobsWhenTheQueueIsFull(t *testing.T) {
+	pool := NewWorkerPool(1, 1)
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	require.NoError(t, pool.Dispatch(t.Context(), "blocking", func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}))
+	<-started
+	require.NoError(t, pool.Dispatch(t.Context(), "queued", func(context.Context) error { return nil }))
+
+	err := pool.Dispatch(t.Context(), "shed", func(context.Context) error { return nil })
+
+	assert.ErrorIs(t, err, ErrQueueFull)
+	close(release)
+	require.NoError(t, pool.Shutdown(t.Context()))
+}
+
+func TestWorkerPool_ShutdownRunsQueuedJobs(t *testing.T) {
+	pool := NewWorkerPool(2, 10)
+	var ran atomic.Int32
+
+	for range 10 {
+		require.NoError(t, pool.Dispatch(t.Context(), "count", func(context.Context) error {
+			ran.Add(1)
+			return nil
+		}))
+	}
+
+	require.NoError(t, pool.Shutdown(t.Context()))
+	assert.EqualValues(t, 10, ran.Load())
+	assert.ErrorIs(t, pool.Dispatch(t.Context(), "late", func(context.Context) error { return nil }), ErrQueueFull)
+}
+
+func TestWorkerPool_ShutdownReportsJobsNotRun(t *testing.T) {
+	pool := NewWorkerPool(1, 2)
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{}, 3)
+	blocking := func(context.Context) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}
+
+	require.NoError(t, pool.Dispatch(t.Context(), "blocking", blocking))
+	<-started
+	require.NoError(t, pool.Dispatch(t.Context(), "blocking", blocking))
+	require.NoError(t, pool.Dispatch(t.Context(), "blocking", blocking))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+
+	err := pool.Shutdown(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "2 background jobs not run")
+}
+
+func TestWorkerPool_JobOutlivesTheRequest(t *testing.T) {
+	pool := NewWorkerPool(1, 1)
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+
+	require.NoError(t, pool.Dispatch(ctx, "job", func(jobCtx context.Context) error {
+		cancel()
+		done <- jobCtx.Err()
+		return nil
+	}))
+
+	assert.NoError(t, <-done)
+	require.NoError(t, pool.Shutdown(t.Context()))
+}
@@ -0,0 +1,23 @@
+package email
+
+import "time"
+
+// FormatDate formats a date for the body of an email in lang.
+func FormatDate(t time.Time, lang string) string {
+	switch lang {
+	case "fr-FR":
+		return t.Format("02/01/2006")
+	default:
+		return t.Format("January 2, 2006")
+	}
+}
+
+// FormatDateTime formats a date and time for the body of an email in lang, with the time zone.
+func FormatDateTime(t time.Time, lang string) string {
+	switch lang {
+	case "fr-FR":
+		return t.Format("02/01/2006 15:04 MST")
+	default:
+		return t.Format("January 2, 2006 3:04 PM MST")
+	}
+}
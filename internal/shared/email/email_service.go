@@ -1,5 +1,7 @@
 package email
 
+import "context"
+
 type EmailService interface {
-	Send(to string, template TemplateType, lang string, data map[string]string) error
+	Send(ctx context.Context, to string, template TemplateType, lang string, data map[string]string) error
 }
@@ -3,10 +3,32 @@ package email
 type TemplateType string
 
 const (
-	TemplateVerification  TemplateType = "verification"
-	TemplateResetPassword TemplateType = "reset_password"
+	TemplateVerification           TemplateType = "verification"
+	TemplateResetPassword          TemplateType = "reset_password"
+	TemplateAccountDeletionWarning TemplateType = "account_deletion_warning"
+	TemplateRegisterAttempt        TemplateType = "register_attempt"
+	TemplateAlreadyVerified        TemplateType = "already_verified"
+	TemplateUnknownAccount         TemplateType = "unknown_account"
+	TemplateNewSignIn              TemplateType = "new_sign_in"
+	TemplateInvitation             TemplateType = "invitation"
+	TemplateGuardianConsent        TemplateType = "guardian_consent"
+	TemplateImpersonationNotice    TemplateType = "impersonation_notice"
 )
 
+// Templates lists every template, for tools going through all of them such as test sends.
+var Templates = []TemplateType{
+	TemplateVerification,
+	TemplateResetPassword,
+	TemplateAccountDeletionWarning,
+	TemplateRegisterAttempt,
+	TemplateAlreadyVerified,
+	TemplateUnknownAccount,
+	TemplateNewSignIn,
+	TemplateInvitation,
+	TemplateGuardianConsent,
+	TemplateImpersonationNotice,
+}
+
 func GetSubject(t TemplateType, lang string) string {
 	switch t {
 	case TemplateVerification:
@@ -15,6 +37,30 @@ func GetSubject(t TemplateType, lang string) string {
 	case TemplateResetPassword:
 		return getResetPasswordSubject(lang)
 
+	case TemplateAccountDeletionWarning:
+		return getAccountDeletionWarningSubject(lang)
+
+	case TemplateRegisterAttempt:
+		return getRegisterAttemptSubject(lang)
+
+	case TemplateAlreadyVerified:
+		return getAlreadyVerifiedSubject(lang)
+
+	case TemplateUnknownAccount:
+		return getUnknownAccountSubject(lang)
+
+	case TemplateNewSignIn:
+		return getNewSignInSubject(lang)
+
+	case TemplateInvitation:
+		return getInvitationSubject(lang)
+
+	case TemplateGuardianConsent:
+		return getGuardianConsentSubject(lang)
+
+	case TemplateImpersonationNotice:
+		return getImpersonationNoticeSubject(lang)
+
 	default:
 		return "JamLink Notification"
 	}
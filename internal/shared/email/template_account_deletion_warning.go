@@ -0,0 +1,10 @@
+package email
+
+func getAccountDeletionWarningSubject(lang string) string {
+	switch lang {
+	case "fr-FR":
+		return "Ton compte JamLink va être supprimé"
+	default:
+		return "Your JamLink account is about to be deleted"
+	}
+}
@@ -0,0 +1,10 @@
+package email
+
+func getAlreadyVerifiedSubject(lang string) string {
+	switch lang {
+	case "fr-FR":
+		return "Ton compte JamLink est déjà vérifié"
+	default:
+		return "Your JamLink account is already verified"
+	}
+}
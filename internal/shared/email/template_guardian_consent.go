@@ -0,0 +1,10 @@
+package email
+
+func getGuardianConsentSubject(lang string) string {
+	switch lang {
+	case "fr-FR":
+		return "Autorisation parentale pour un compte JamLink"
+	default:
+		return "Parental consent for a JamLink account"
+	}
+}
@@ -0,0 +1,10 @@
+package email
+
+func getImpersonationNoticeSubject(lang string) string {
+	switch lang {
+	case "fr-FR":
+		return "L’équipe JamLink a accédé à ton compte"
+	default:
+		return "The JamLink team accessed your account"
+	}
+}
@@ -0,0 +1,10 @@
+package email
+
+func getInvitationSubject(lang string) string {
+	switch lang {
+	case "fr-FR":
+		return "Tu es invité(e) à rejoindre JamLink"
+	default:
+		return "You're invited to join JamLink"
+	}
+}
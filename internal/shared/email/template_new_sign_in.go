@@ -0,0 +1,10 @@
+package email
+
+func getNewSignInSubject(lang string) string {
+	switch lang {
+	case "fr-FR":
+		return "Nouvelle connexion à ton compte JamLink"
+	default:
+		return "New sign-in to your JamLink account"
+	}
+}
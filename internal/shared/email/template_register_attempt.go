@@ -0,0 +1,10 @@
+package email
+
+func getRegisterAttemptSubject(lang string) string {
+	switch lang {
+	case "fr-FR":
+		return "Tentative d’inscription avec ton adresse JamLink"
+	default:
+		return "Someone tried to sign up with your JamLink address"
+	}
+}
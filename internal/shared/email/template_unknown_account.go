@@ -0,0 +1,10 @@
+package email
+
+func getUnknownAccountSubject(lang string) string {
+	switch lang {
+	case "fr-FR":
+		return "Aucun compte JamLink pour cette adresse"
+	default:
+		return "No JamLink account for this address"
+	}
+}
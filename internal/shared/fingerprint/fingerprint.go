@@ -0,0 +1,81 @@
+package fingerprint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/netip"
+)
+
+// LoginContext is what the HTTP layer knows about where a login comes from.
+type LoginContext struct {
+	UserAgent string
+	IP        string
+}
+
+type Fingerprint struct {
+	Hash     string
+	Device   string
+	IPPrefix string
+	Country  string
+	City     string
+}
+
+type Fingerprinter interface {
+	Fingerprint(login LoginContext) Fingerprint
+}
+
+type fingerprinter struct {
+	geo GeoLocator
+}
+
+// NewFingerprinter builds a fingerprinter. geo may be nil when no GeoIP database is configured.
+func NewFingerprinter(geo GeoLocator) Fingerprinter {
+	return &fingerprinter{geo: geo}
+}
+
+// Fingerprint identifies a device by its browser family, its operating system and where it connects from.
+// The full user agent is left out since it changes with every browser update. The country is used when a
+// GeoIP database is available, since it is far more stable than the IP prefix of a mobile or home network.
+func (f *fingerprinter) Fingerprint(login LoginContext) Fingerprint {
+	fp := Fingerprint{
+		Device:   DescribeUserAgent(login.UserAgent),
+		IPPrefix: ipPrefix(login.IP),
+	}
+
+	if f.geo != nil {
+		if addr, err := netip.ParseAddr(login.IP); err == nil {
+			fp.Country, fp.City = f.geo.Locate(addr)
+		}
+	}
+
+	location := fp.Country
+	if location == "" {
+		location = fp.IPPrefix
+	}
+
+	sum := sha256.Sum256([]byte(fp.Device + "|" + location))
+	fp.Hash = hex.EncodeToString(sum[:])
+
+	return fp
+}
+
+// ipPrefix masks IPv4 addresses to /24 and IPv6 addresses to /48.
+func ipPrefix(ip string) string {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ""
+	}
+
+	bits := 48
+	if addr.Unmap().Is4() {
+		addr = addr.Unmap()
+		bits = 24
+	}
+
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return ""
+	}
+
+	return prefix.String()
+}
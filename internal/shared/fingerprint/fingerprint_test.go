@@ -0,0 +1,26 @@
+package fingerprint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprint_SurvivesBrowserUpdates(t *testing.T) {
+	fingerprinter := NewFingerprinter(nil)
+
+	before := fingerprinter.Fingerprint(LoginContext{UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:126.0) Gecko/20100101 Firefox/126.0", IP: "81.250.12.34"})
+	after := fingerprinter.Fingerprint(LoginContext{UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:127.0) Gecko/20100101 Firefox/127.0", IP: "81.250.12.99"})
+
+	assert.Equal(t, "Firefox / Windows", before.Device)
+	assert.Equal(t, before.Hash, after.Hash)
+}
+
+func TestFingerprint_TellsBrowsersApart(t *testing.T) {
+	fingerprinter := NewFingerprinter(nil)
+
+	firefox := fingerprinter.Fingerprint(LoginContext{UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:126.0) Gecko/20100101 Firefox/126.0", IP: "81.250.12.34"})
+	chrome := fingerprinter.Fingerprint(LoginContext{UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36", IP: "81.250.12.34"})
+
+	assert.NotEqual(t, firefox.Hash, chrome.Hash)
+}
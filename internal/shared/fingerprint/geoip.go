@@ -0,0 +1,85 @@
+package fingerprint
+
+import (
+	"bufio"
+	"fmt"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+)
+
+type GeoLocator interface {
+	Locate(addr netip.Addr) (country string, city string)
+}
+
+type geoEntry struct {
+	prefix  netip.Prefix
+	country string
+	city    string
+}
+
+// CSVGeoLocator resolves addresses from a local CSV file with one "network,country,city" line per range,
+// e.g. "81.250.0.0/16,FR,Paris". Lines starting with # are ignored.
+type CSVGeoLocator struct {
+	entries []geoEntry
+}
+
+func LoadCSVGeoLocator(path string) (*CSVGeoLocator, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	locator := &CSVGeoLocator{}
+	scanner := bufio.NewScanner(file)
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Split(text, ",")
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("geoip %s:%d: expected network,country[,city]", path, line)
+		}
+
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(fields[0]))
+		if err != nil {
+			return nil, fmt.Errorf("geoip %s:%d: %w", path, line, err)
+		}
+
+		entry := geoEntry{prefix: prefix.Masked(), country: strings.TrimSpace(fields[1])}
+		if len(fields) > 2 {
+			entry.city = strings.TrimSpace(fields[2])
+		}
+		locator.entries = append(locator.entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Most specific networks first so the first match wins.
+	sort.SliceStable(locator.entries, func(i, j int) bool {
+		return locator.entries[i].prefix.Bits() > locator.entries[j].prefix.Bits()
+	})
+
+	return locator, nil
+}
+
+func (l *CSVGeoLocator) Locate(addr netip.Addr) (string, string) {
+	addr = addr.Unmap()
+
+	for _, entry := range l.entries {
+		if entry.prefix.Contains(addr) {
+			return entry.country, entry.city
+		}
+	}
+
+	return "", ""
+}
@@ -0,0 +1,199 @@
+// Package metrics holds the Prometheus metrics of the API and of the maintenance worker. They are
+// package-level collectors updated through small record functions, so that recording a metric never
+// needs to be threaded through constructors.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "jamlink"
+
+// Login providers.
+const (
+	ProviderPassword = "password"
+	ProviderGoogle   = "google"
+	ProviderDevice   = "device"
+)
+
+// Funnels and their steps: a user asks for an email, receives it, then follows its link.
+const (
+	FunnelVerification  = "verification"
+	FunnelPasswordReset = "password_reset"
+
+	StepRequested = "requested"
+	StepEmailSent = "email_sent"
+	StepCompleted = "completed"
+)
+
+// Email outcomes: rejected is a refusal of the provider, such as an invalid address, failed an error
+// of the provider or of the network.
+const (
+	EmailSent     = "sent"
+	EmailRejected = "rejected"
+	EmailFailed   = "failed"
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Duration of the HTTP requests by route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Duration of the database queries by operation and table.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table", "outcome"})
+
+	passwordHashDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "password_hash_duration_seconds",
+		Help:      "Duration of bcrypt hashing and comparisons.",
+		Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1},
+	}, []string{"operation"})
+
+	logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Login attempts by provider and outcome.",
+	}, []string{"provider", "outcome"})
+
+	refreshTokenRotations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "refresh_token_rotations_total",
+		Help:      "Refresh token exchanges by outcome.",
+	}, []string{"outcome"})
+
+	funnelSteps = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "funnel_steps_total",
+		Help:      "Steps reached in the email verification and password reset funnels.",
+	}, []string{"funnel", "step"})
+
+	emails = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "emails_total",
+		Help:      "Emails sent through the provider by template, language and outcome.",
+	}, []string{"template", "lang", "outcome"})
+
+	maintenanceRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "maintenance_runs_total",
+		Help:      "Runs of the maintenance worker, skipped when another replica held the lock.",
+	}, []string{"outcome"})
+
+	maintenanceItems = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "maintenance_items_total",
+		Help:      "Items handled by the maintenance worker by action.",
+	}, []string{"action"})
+
+	maintenanceFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "maintenance_failures_total",
+		Help:      "Items the maintenance worker failed to handle.",
+	})
+
+	maintenanceLastRunDuration = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "maintenance_last_run_duration_seconds",
+		Help:      "Duration of the last completed run of the maintenance worker.",
+	})
+
+	maintenanceLastRun = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "maintenance_last_run_timestamp_seconds",
+		Help:      "Unix time of the last completed run of the maintenance worker.",
+	})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestDuration,
+		queryDuration,
+		passwordHashDuration,
+		logins,
+		refreshTokenRotations,
+		funnelSteps,
+		emails,
+		maintenanceRuns,
+		maintenanceItems,
+		maintenanceFailures,
+		maintenanceLastRunDuration,
+		maintenanceLastRun,
+	)
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTPRequest records a request under its route pattern, never its path, which would make a
+// series of every user ID.
+func ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	httpRequestDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+func ObserveQuery(operation, table string, duration time.Duration, success bool) {
+	queryDuration.WithLabelValues(operation, table, outcome(success)).Observe(duration.Seconds())
+}
+
+// ObservePasswordHash records the duration of a bcrypt "hash" or "compare".
+func ObservePasswordHash(operation string, duration time.Duration) {
+	passwordHashDuration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+func RecordLogin(provider string, err error) {
+	logins.WithLabelValues(provider, outcome(err == nil)).Inc()
+}
+
+func RecordRefreshTokenRotation(err error) {
+	refreshTokenRotations.WithLabelValues(outcome(err == nil)).Inc()
+}
+
+func RecordFunnelStep(funnel, step string) {
+	funnelSteps.WithLabelValues(funnel, step).Inc()
+}
+
+func RecordEmail(template, lang, result string) {
+	emails.WithLabelValues(template, lang, result).Inc()
+}
+
+// RecordMaintenanceRunSkipped records a run that found another replica holding the lock.
+func RecordMaintenanceRunSkipped() {
+	maintenanceRuns.WithLabelValues("skipped").Inc()
+}
+
+// RecordMaintenanceRun records a completed run. items counts what was handled by action, such as
+// "tokens_deleted".
+func RecordMaintenanceRun(items map[string]int64, failures int, duration time.Duration) {
+	maintenanceRuns.WithLabelValues("completed").Inc()
+	for action, count := range items {
+		maintenanceItems.WithLabelValues(action).Add(float64(count))
+	}
+	maintenanceFailures.Add(float64(failures))
+	maintenanceLastRunDuration.Set(duration.Seconds())
+	maintenanceLastRun.SetToCurrentTime()
+}
+
+func outcome(success bool) string {
+	if success {
+		return "success"
+	}
+	return "failure"
+}
@@ -0,0 +1,57 @@
+package security
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Authentication method references, following the "amr" values of RFC 8176 where one exists.
+const (
+	AuthMethodPassword = "pwd"
+	AuthMethodOTP      = "otp"
+	AuthMethodPasskey  = "hwk"
+	AuthMethodGoogle   = "google"
+	// AuthMethodDevice marks sessions of devices approved by the user from a browser (RFC 8628).
+	AuthMethodDevice = "device"
+)
+
+// AuthContext records when and how the user last proved their identity. It is carried over on refresh
+// so that refreshing a session never counts as a fresh authentication.
+type AuthContext struct {
+	Time    time.Time
+	Methods []string
+}
+
+func NewAuthContext(methods ...string) *AuthContext {
+	return &AuthContext{Time: time.Now(), Methods: methods}
+}
+
+// IsRecent reports whether the authentication happened less than maxAge ago.
+func (a *AuthContext) IsRecent(maxAge time.Duration) bool {
+	return a != nil && !a.Time.IsZero() && time.Since(a.Time) <= maxAge
+}
+
+// AuthContextFromClaims reads the auth_time and amr claims. Tokens issued before these claims existed
+// yield an AuthContext with a zero Time, which is never recent.
+func AuthContextFromClaims(claims jwt.MapClaims) *AuthContext {
+	auth := &AuthContext{}
+
+	if authTime, ok := claims["auth_time"].(float64); ok {
+		auth.Time = time.Unix(int64(authTime), 0)
+	}
+
+	if methods, ok := claims["amr"].([]interface{}); ok {
+		for _, method := range methods {
+			if m, ok := method.(string); ok {
+				auth.Methods = append(auth.Methods, m)
+			}
+		}
+	}
+
+	return auth
+}
+
+// StepUpTokenType is the type of the short-lived token granted by a re-authentication. It proves a
+// recent authentication without replacing the session's access and refresh tokens.
+const StepUpTokenType = "step_up"
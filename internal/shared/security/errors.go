@@ -1,23 +1,33 @@
 package security
 
-import "errors"
+import (
+	"errors"
+
+	"jamlink-backend/internal/shared/apperror"
+)
 
 var (
 	// Password
 	ErrPasswordHashing    = errors.New("failed to hash password")
-	ErrPasswordComparison = errors.New("password does not match")
+	ErrPasswordComparison = apperror.Unauthorized("invalid_credentials", "invalid email or password")
 
 	// JWT Generation
 	ErrJWTGeneration = errors.New("failed to generate JWT")
 
 	// JWT Validation
 	ErrInvalidJWTSigningMethod = errors.New("unexpected JWT signing method")
-	ErrInvalidToken            = errors.New("invalid JWT token")
-	ErrCannotExtractClaims     = errors.New("unable to extract claims from token")
+	ErrInvalidToken            = apperror.Unauthorized("invalid_token", "invalid JWT token")
+	ErrCannotExtractClaims     = apperror.Unauthorized("invalid_token_claims", "unable to extract claims from token")
 
 	// JWT Claims parsing
-	ErrInvalidUserID    = errors.New("invalid or missing user ID in JWT claims")
-	ErrInvalidUserEmail = errors.New("invalid or missing email in JWT claims")
+	ErrInvalidUserID    = apperror.Unauthorized("token_missing_user_id", "invalid or missing user ID in JWT claims")
+	ErrInvalidUserEmail = apperror.Unauthorized("token_missing_email", "invalid or missing email in JWT claims")
+
+	// Token strategy
+	ErrUnknownTokenStrategy = errors.New("TOKEN_STRATEGY must be jwt or opaque")
+
+	// Signing keys
+	ErrInvalidSigningKey = errors.New("signing key must be an RSA private key in PEM format")
 
 	// Secure Random Generation
 	ErrSecureRandomGeneration = errors.New("failed to generate secure random string")
@@ -0,0 +1,89 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// signingKeyBits is the size of the RSA keys generated by RotateSigningKeys.
+const signingKeyBits = 2048
+
+// KeyRotation reports the keys involved in a rotation. Previous is nil when there was no active key.
+type KeyRotation struct {
+	Active   SigningKey
+	Previous *SigningKey
+}
+
+// RotateSigningKeys generates a new active key at activePath. The replaced key moves to previousPath,
+// where it stays published until the next rotation so the ID tokens it signed can still be verified;
+// the key previously there is dropped. Servers read the files at startup and must be restarted.
+func RotateSigningKeys(activePath, previousPath string) (*KeyRotation, error) {
+	if activePath == "" || previousPath == "" {
+		return nil, errors.New("rotating the signing keys requires both the active and the previous key files")
+	}
+
+	rotation := &KeyRotation{}
+	current, err := loadSigningKey(activePath)
+	switch {
+	case err == nil:
+		rotation.Previous = &current
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, signingKeyBits)
+	if err != nil {
+		return nil, fmt.Errorf("generate signing key: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("encode signing key: %w", err)
+	}
+
+	if rotation.Previous != nil {
+		raw, err := os.ReadFile(activePath)
+		if err != nil {
+			return nil, fmt.Errorf("read signing key: %w", err)
+		}
+		if err := writeKeyFile(previousPath, raw); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeKeyFile(activePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})); err != nil {
+		return nil, err
+	}
+
+	rotation.Active = NewSigningKey(privateKey)
+	return rotation, nil
+}
+
+// writeKeyFile replaces path through a rename, so that a server starting meanwhile never reads half a key.
+func writeKeyFile(path string, content []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("write signing key: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0o600)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("write signing key: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package security
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotateSigningKeys_KeepsTheReplacedKeyPublished(t *testing.T) {
+	dir := t.TempDir()
+	active := filepath.Join(dir, "oidc.pem")
+	previous := filepath.Join(dir, "oidc.previous.pem")
+
+	first, err := RotateSigningKeys(active, previous)
+	require.NoError(t, err)
+	assert.Nil(t, first.Previous)
+
+	second, err := RotateSigningKeys(active, previous)
+	require.NoError(t, err)
+	require.NotNil(t, second.Previous)
+	assert.Equal(t, first.Active.ID, second.Previous.ID)
+	assert.NotEqual(t, first.Active.ID, second.Active.ID)
+
+	keys, err := LoadKeySet(active, previous)
+	require.NoError(t, err)
+	jwks := keys.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, second.Active.ID, jwks.Keys[0].Kid)
+	assert.Equal(t, first.Active.ID, jwks.Keys[1].Kid)
+
+	info, err := os.Stat(active)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestRotateSigningKeys_RequiresThePreviousKeyFile(t *testing.T) {
+	_, err := RotateSigningKeys(filepath.Join(t.TempDir(), "oidc.pem"), "")
+
+	assert.Error(t, err)
+}
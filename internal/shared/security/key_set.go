@@ -0,0 +1,152 @@
+package security
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey is an RSA key identified by its RFC 7638 thumbprint.
+type SigningKey struct {
+	ID         string
+	PrivateKey *rsa.PrivateKey
+}
+
+// JWK is the public part of a SigningKey, as published in a JWKS (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// KeySet signs tokens read by third parties, such as OpenID Connect ID tokens, with RS256. Unlike the
+// HS256 secret of GenerateJWT, its public keys can be published. Previous keys stay published after a
+// rotation so the tokens they signed can still be verified.
+type KeySet struct {
+	active   SigningKey
+	previous []SigningKey
+}
+
+func NewSigningKey(privateKey *rsa.PrivateKey) SigningKey {
+	return SigningKey{ID: thumbprint(&privateKey.PublicKey), PrivateKey: privateKey}
+}
+
+func NewKeySet(active SigningKey, previous ...SigningKey) *KeySet {
+	return &KeySet{active: active, previous: previous}
+}
+
+// LoadKeySet reads PEM RSA keys. It returns a nil KeySet, i.e. the OpenID provider disabled, when
+// activePath is empty. previousPath, when not empty, keeps the key replaced by the last rotation
+// published.
+func LoadKeySet(activePath, previousPath string) (*KeySet, error) {
+	if activePath == "" {
+		return nil, nil
+	}
+
+	active, err := loadSigningKey(activePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var previous []SigningKey
+	if previousPath != "" {
+		key, err := loadSigningKey(previousPath)
+		if err != nil {
+			return nil, err
+		}
+		previous = append(previous, key)
+	}
+
+	return NewKeySet(active, previous...), nil
+}
+
+// Sign signs claims with the active key and names it in the "kid" header.
+func (k *KeySet) Sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = k.active.ID
+
+	signed, err := token.SignedString(k.active.PrivateKey)
+	if err != nil {
+		return "", ErrJWTGeneration
+	}
+
+	return signed, nil
+}
+
+func (k *KeySet) JWKS() JWKS {
+	keys := []JWK{publicJWK(k.active)}
+	for _, key := range k.previous {
+		keys = append(keys, publicJWK(key))
+	}
+
+	return JWKS{Keys: keys}
+}
+
+func loadSigningKey(path string) (SigningKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("read signing key: %w", err)
+	}
+
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return SigningKey{}, ErrInvalidSigningKey
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return NewSigningKey(key), nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return SigningKey{}, ErrInvalidSigningKey
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return SigningKey{}, ErrInvalidSigningKey
+	}
+
+	return NewSigningKey(key), nil
+}
+
+func publicJWK(key SigningKey) JWK {
+	return JWK{
+		Kty: "RSA",
+		Use: "sig",
+		Alg: "RS256",
+		Kid: key.ID,
+		N:   base64.RawURLEncoding.EncodeToString(key.PrivateKey.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PrivateKey.E)).Bytes()),
+	}
+}
+
+// thumbprint computes the RFC 7638 thumbprint: the hash of the required members in lexicographic order.
+func thumbprint(key *rsa.PublicKey) string {
+	members, _ := json.Marshal(struct {
+		E   string `json:"e"`
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+	}{
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		Kty: "RSA",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+	})
+
+	sum := sha256.Sum256(members)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,82 @@
+package security
+
+import (
+	"context"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ServiceSubjectPrefix starts the "sub" claim of the tokens issued to OAuth clients, which are not
+// tied to a user.
+const ServiceSubjectPrefix = "client:"
+
+type PrincipalKind string
+
+const (
+	PrincipalUser    PrincipalKind = "user"
+	PrincipalService PrincipalKind = "service"
+)
+
+// Principal is who a request acts for: a user, identified by the "id" claim, or a service, identified
+// by the client id of its "sub" claim and limited to the scopes of its token.
+type Principal struct {
+	Kind   PrincipalKind
+	ID     string
+	Scopes []string
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying principal, so that the layers below the handlers know
+// who the request acts for.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal of ctx, or nil when the request is not authenticated.
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
+
+func WithSubject(sub string) ClaimOption {
+	return func(claims jwt.MapClaims) {
+		claims["sub"] = sub
+	}
+}
+
+// WithScopes adds the space-delimited "scope" claim of RFC 8693.
+func WithScopes(scopes []string) ClaimOption {
+	return func(claims jwt.MapClaims) {
+		claims["scope"] = strings.Join(scopes, " ")
+	}
+}
+
+func PrincipalFromClaims(claims jwt.MapClaims) *Principal {
+	if sub, ok := claims["sub"].(string); ok && strings.HasPrefix(sub, ServiceSubjectPrefix) {
+		scope, _ := claims["scope"].(string)
+
+		return &Principal{
+			Kind:   PrincipalService,
+			ID:     strings.TrimPrefix(sub, ServiceSubjectPrefix),
+			Scopes: strings.Fields(scope),
+		}
+	}
+
+	id, _ := claims["id"].(string)
+	return &Principal{Kind: PrincipalUser, ID: id}
+}
+
+func (p *Principal) IsService() bool {
+	return p.Kind == PrincipalService
+}
+
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
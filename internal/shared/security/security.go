@@ -1,10 +1,13 @@
 package security
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
-	"os"
+	"jamlink-backend/internal/shared/metrics"
+	"math/big"
 	"time"
 
 	"crypto/rand"
@@ -13,24 +16,39 @@ import (
 )
 
 type SecurityService interface {
-	HashPassword(password string) (string, error)
-	CheckPassword(password, hash string) bool
-	GenerateJWT(id *uuid.UUID, email *string, duration time.Duration, tokenType string, isVerified bool) (string, error)
-	ValidateJWT(tokenString string) (jwt.MapClaims, error)
-	GetJWTInfo(tokenString string) (uuid.UUID, error)
+	HashPassword(ctx context.Context, password string) (string, error)
+	CheckPassword(ctx context.Context, password, hash string) bool
+	GenerateJWT(ctx context.Context, id *uuid.UUID, email *string, duration time.Duration, tokenType string, isVerified bool, auth *AuthContext, opts ...ClaimOption) (string, error)
+	ValidateJWT(ctx context.Context, tokenString string) (jwt.MapClaims, error)
+	GetJWTInfo(ctx context.Context, tokenString string) (uuid.UUID, error)
+	GetJWTAuthContext(ctx context.Context, tokenString string) (*AuthContext, error)
 	GenerateSecureRandomString(n int) (string, error)
+	GenerateNumericCode(digits int) (string, error)
+	HashOTP(code string) string
 }
 
-type securityService struct{}
+// securityService delegates the format of its tokens to a TokenStrategy. The GenerateJWT and ValidateJWT
+// names predate the opaque strategy; they handle whichever kind of token the strategy issues.
+type securityService struct {
+	secret []byte
+	tokens TokenStrategy
+}
 
-func NewSecurityService() SecurityService {
-	return &securityService{}
+// NewSecurityService keys the hashes of HashOTP with secret, the JWT secret.
+func NewSecurityService(secret []byte, tokens TokenStrategy) SecurityService {
+	return &securityService{secret: secret, tokens: tokens}
 }
 
-var jwtSecret = []byte(os.Getenv("JWT_SECRET"))
+// HashPassword and CheckPassword cannot be interrupted once started, so they give up beforehand when
+// the request is already cancelled.
+func (s *securityService) HashPassword(ctx context.Context, password string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
 
-func (s *securityService) HashPassword(password string) (string, error) {
+	start := time.Now()
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	metrics.ObservePasswordHash("hash", time.Since(start))
 
 	if err != nil {
 		return "", ErrPasswordHashing
@@ -39,13 +57,21 @@ func (s *securityService) HashPassword(password string) (string, error) {
 	return string(bytes), nil
 }
 
-func (s *securityService) CheckPassword(password, hash string) bool {
+func (s *securityService) CheckPassword(ctx context.Context, password, hash string) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	start := time.Now()
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	metrics.ObservePasswordHash("compare", time.Since(start))
 
 	return err == nil
 }
 
-func (s *securityService) GenerateJWT(id *uuid.UUID, email *string, duration time.Duration, tokenType string, isVerified bool) (string, error) {
+// GenerateJWT adds the auth_time and amr claims when auth is given. Tokens that are not tied to a
+// session (email verification, password reset) pass a nil auth.
+func (s *securityService) GenerateJWT(ctx context.Context, id *uuid.UUID, email *string, duration time.Duration, tokenType string, isVerified bool, auth *AuthContext, opts ...ClaimOption) (string, error) {
 	claims := jwt.MapClaims{
 		"iat":        time.Now().Unix(),
 		"exp":        time.Now().Add(duration).Unix(),
@@ -59,39 +85,23 @@ func (s *securityService) GenerateJWT(id *uuid.UUID, email *string, duration tim
 	if email != nil {
 		claims["email"] = *email
 	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	tokenString, err := token.SignedString(jwtSecret)
-	if err != nil {
-		return "", ErrJWTGeneration
+	if auth != nil {
+		claims["auth_time"] = auth.Time.Unix()
+		claims["amr"] = auth.Methods
 	}
-
-	return tokenString, nil
-}
-
-func (s *securityService) ValidateJWT(tokenString string) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidJWTSigningMethod
-		}
-		return jwtSecret, nil
-	})
-
-	if err != nil || !token.Valid {
-		return nil, ErrInvalidToken
+	for _, opt := range opts {
+		opt(claims)
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return nil, ErrCannotExtractClaims
-	}
+	return s.tokens.Issue(ctx, claims, time.Now().Add(duration))
+}
 
-	return claims, nil
+func (s *securityService) ValidateJWT(ctx context.Context, tokenString string) (jwt.MapClaims, error) {
+	return s.tokens.Parse(ctx, tokenString)
 }
 
-func (s *securityService) ParseVerificationJWT(tokenStr string) (string, error) {
-	claims, err := s.ValidateJWT(tokenStr)
+func (s *securityService) ParseVerificationJWT(ctx context.Context, tokenStr string) (string, error) {
+	claims, err := s.ValidateJWT(ctx, tokenStr)
 	if err != nil {
 		return "", err
 	}
@@ -102,8 +112,8 @@ func (s *securityService) ParseVerificationJWT(tokenStr string) (string, error)
 	return email, nil
 }
 
-func (s *securityService) GetJWTInfo(tokenString string) (uuid.UUID, error) {
-	claims, err := s.ValidateJWT(tokenString)
+func (s *securityService) GetJWTInfo(ctx context.Context, tokenString string) (uuid.UUID, error) {
+	claims, err := s.ValidateJWT(ctx, tokenString)
 	if err != nil {
 		return uuid.Nil, err
 	}
@@ -121,6 +131,15 @@ func (s *securityService) GetJWTInfo(tokenString string) (uuid.UUID, error) {
 	return id, nil
 }
 
+func (s *securityService) GetJWTAuthContext(ctx context.Context, tokenString string) (*AuthContext, error) {
+	claims, err := s.ValidateJWT(ctx, tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	return AuthContextFromClaims(claims), nil
+}
+
 func (s *securityService) GenerateSecureRandomString(n int) (string, error) {
 	bytes := make([]byte, n)
 	_, err := rand.Read(bytes)
@@ -130,3 +149,20 @@ func (s *securityService) GenerateSecureRandomString(n int) (string, error) {
 
 	return base64.URLEncoding.EncodeToString(bytes), nil
 }
+
+func (s *securityService) GenerateNumericCode(digits int) (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil)
+
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", ErrSecureRandomGeneration
+	}
+
+	return fmt.Sprintf("%0*d", digits, n), nil
+}
+
+// HashOTP is the keyed hash under which codes and tokens are stored, so that the stored value cannot be
+// used in their place.
+func (s *securityService) HashOTP(code string) string {
+	return keyedHash(s.secret, code)
+}
@@ -0,0 +1,46 @@
+package security
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashPassword_GivesUpWhenCancelled(t *testing.T) {
+	svc := NewSecurityService([]byte("secret"), NewJWTStrategy([]byte("secret")))
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	hash, err := svc.HashPassword(ctx, "correct horse battery staple")
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, hash)
+}
+
+type recordingSessionStore struct {
+	created []string
+}
+
+func (s *recordingSessionStore) CreateSession(_ context.Context, tokenHash string, _ *uuid.UUID, _ []byte, _ time.Time) error {
+	s.created = append(s.created, tokenHash)
+	return nil
+}
+
+func (s *recordingSessionStore) FindSession(context.Context, string) ([]byte, time.Time, error) {
+	return nil, time.Time{}, ErrInvalidToken
+}
+
+func TestOpaqueStrategy_IssuesInTheStoreOfTheContext(t *testing.T) {
+	own, txStore := &recordingSessionStore{}, &recordingSessionStore{}
+	strategy := NewOpaqueStrategy(own, []byte("secret"))
+
+	_, err := strategy.Issue(WithSessionStore(t.Context(), txStore), jwt.MapClaims{"type": AccessTokenType}, time.Now().Add(time.Minute))
+
+	assert.NoError(t, err)
+	assert.Len(t, txStore.created, 1)
+	assert.Empty(t, own.created)
+}
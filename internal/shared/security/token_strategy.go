@@ -0,0 +1,161 @@
+package security
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	TokenStrategyJWT    = "jwt"
+	TokenStrategyOpaque = "opaque"
+
+	// OpaqueTokenPrefix makes opaque tokens recognizable, e.g. by secret scanners.
+	OpaqueTokenPrefix = "jlo_"
+)
+
+// TokenStrategy turns claims into the tokens handed to clients and back. Whatever the strategy,
+// Parse returns the claims given to Issue, so callers of GenerateJWT and ValidateJWT do not depend on it.
+type TokenStrategy interface {
+	Issue(ctx context.Context, claims jwt.MapClaims, expiresAt time.Time) (string, error)
+	Parse(ctx context.Context, token string) (jwt.MapClaims, error)
+}
+
+// SessionStore keeps the claims of opaque tokens. Only a keyed hash of the token is stored, and the
+// claims are JSON. userID is nil for tokens that are not tied to an account.
+type SessionStore interface {
+	CreateSession(ctx context.Context, tokenHash string, userID *uuid.UUID, claims []byte, expiresAt time.Time) error
+	FindSession(ctx context.Context, tokenHash string) (claims []byte, expiresAt time.Time, err error)
+}
+
+type sessionStoreKey struct{}
+
+// WithSessionStore returns a copy of ctx in which the opaque strategy stores the sessions it issues in
+// store instead of its own, e.g. in the token repository of a transaction so they are rolled back with it.
+func WithSessionStore(ctx context.Context, store SessionStore) context.Context {
+	return context.WithValue(ctx, sessionStoreKey{}, store)
+}
+
+// NewTokenStrategy returns the strategy named name: "jwt" for self-contained HS256 tokens signed with
+// secret, or "opaque" for random tokens whose claims are kept in store, so deleting them revokes the
+// tokens at once. Switching strategy invalidates every token issued before.
+func NewTokenStrategy(name string, secret []byte, store SessionStore) (TokenStrategy, error) {
+	switch name {
+	case TokenStrategyJWT:
+		return NewJWTStrategy(secret), nil
+	case TokenStrategyOpaque:
+		return NewOpaqueStrategy(store, secret), nil
+	default:
+		return nil, ErrUnknownTokenStrategy
+	}
+}
+
+type jwtStrategy struct {
+	secret []byte
+}
+
+func NewJWTStrategy(secret []byte) TokenStrategy {
+	return jwtStrategy{secret: secret}
+}
+
+func (s jwtStrategy) Issue(_ context.Context, claims jwt.MapClaims, _ time.Time) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	tokenString, err := token.SignedString(s.secret)
+	if err != nil {
+		return "", ErrJWTGeneration
+	}
+
+	return tokenString, nil
+}
+
+func (s jwtStrategy) Parse(_ context.Context, tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidJWTSigningMethod
+		}
+		return s.secret, nil
+	})
+
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrCannotExtractClaims
+	}
+
+	return claims, nil
+}
+
+type opaqueStrategy struct {
+	store  SessionStore
+	secret []byte
+}
+
+func NewOpaqueStrategy(store SessionStore, secret []byte) TokenStrategy {
+	return &opaqueStrategy{store: store, secret: secret}
+}
+
+func (s *opaqueStrategy) Issue(ctx context.Context, claims jwt.MapClaims, expiresAt time.Time) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", ErrSecureRandomGeneration
+	}
+	token := OpaqueTokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	encoded, err := json.Marshal(claims)
+	if err != nil {
+		return "", ErrJWTGeneration
+	}
+
+	var userID *uuid.UUID
+	if rawID, ok := claims["id"].(string); ok {
+		if id, err := uuid.Parse(rawID); err == nil {
+			userID = &id
+		}
+	}
+
+	store := s.store
+	if txStore, ok := ctx.Value(sessionStoreKey{}).(SessionStore); ok {
+		store = txStore
+	}
+
+	if err := store.CreateSession(ctx, keyedHash(s.secret, token), userID, encoded, expiresAt); err != nil {
+		return "", ErrJWTGeneration
+	}
+
+	return token, nil
+}
+
+// Parse decodes the stored claims as JSON, like a JWT payload, so numbers are float64 in both strategies.
+func (s *opaqueStrategy) Parse(ctx context.Context, token string) (jwt.MapClaims, error) {
+	encoded, expiresAt, err := s.store.FindSession(ctx, keyedHash(s.secret, token))
+	if err != nil || time.Now().After(expiresAt) {
+		return nil, ErrInvalidToken
+	}
+
+	claims := jwt.MapClaims{}
+	if err := json.Unmarshal(encoded, &claims); err != nil {
+		return nil, ErrCannotExtractClaims
+	}
+
+	return claims, nil
+}
+
+// keyedHash keys the hash with the JWT secret so a leaked table cannot be brute-forced offline.
+func keyedHash(secret []byte, value string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}